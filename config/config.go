@@ -1,7 +1,10 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -12,22 +15,33 @@ import (
 )
 
 type Config struct {
+	Version int       `yaml:"version"`
 	Metrics Metrics   `yaml:"metrics"`
 	Sensors []*Sensor `yaml:"sensors"`
 }
 
 type configWithOptionalMetrics struct {
+	Version int       `yaml:"version,omitempty"`
 	Metrics *Metrics  `yaml:"metrics,omitempty"`
 	Sensors []*Sensor `yaml:"sensors,omitempty"`
 }
 
 func Load(filePath string) (*Config, error) {
+	return load(filePath, false)
+}
+
+// LoadStrict loads the config like Load but fails on keys which do not map to any config field.
+func LoadStrict(filePath string) (*Config, error) {
+	return load(filePath, true)
+}
+
+func load(filePath string, strict bool) (*Config, error) {
 	stat, err := os.Stat(filePath)
 	if err != nil {
 		return nil, err
 	}
 	if !stat.IsDir() {
-		return loadFile[Config](filePath)
+		return loadFile[Config](filePath, strict)
 	}
 
 	absFilePath, err := filepath.Abs(filePath)
@@ -48,7 +62,7 @@ func Load(filePath string) (*Config, error) {
 			return nil
 		}
 
-		if !strings.HasSuffix(filePath, ".yaml") && strings.HasSuffix(filePath, ".yml") {
+		if !isConfigFile(filePath) {
 			return nil
 		}
 
@@ -59,7 +73,7 @@ func Load(filePath string) (*Config, error) {
 
 		logrus.WithField("file", relativeFilePath).Info("loading config")
 
-		c, err := loadFile[configWithOptionalMetrics](filePath)
+		c, err := loadFile[configWithOptionalMetrics](filePath, strict)
 		if err != nil {
 			return fmt.Errorf("failed to load config file %s: %w", filePath, err)
 		}
@@ -81,16 +95,62 @@ func Load(filePath string) (*Config, error) {
 	return &config, nil
 }
 
-func loadFile[T any](filePath string) (*T, error) {
-	f, err := os.Open(filePath)
+func isConfigFile(filePath string) bool {
+	return strings.HasSuffix(filePath, ".yaml") || strings.HasSuffix(filePath, ".yml")
+}
+
+func loadFile[T any](filePath string, strict bool) (*T, error) {
+	data, err := os.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer f.Close()
+
+	if strict {
+		if err := checkKnownFields[T](data); err != nil {
+			return nil, err
+		}
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if node.Kind == 0 {
+		return nil, io.EOF
+	}
+
+	if err := interpolate(&node); err != nil {
+		return nil, fmt.Errorf("failed to interpolate config: %w", err)
+	}
 
 	var config *T
-	if err := yaml.NewDecoder(f).Decode(&config); err != nil {
+	if err := node.Decode(&config); err != nil {
 		return nil, err
 	}
 	return config, nil
 }
+
+// checkKnownFields reports the keys of data which do not map to any field of T, type errors are ignored
+// as values are only interpolated afterward.
+func checkKnownFields[T any](data []byte) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var config *T
+	err := decoder.Decode(&config)
+	var typeError *yaml.TypeError
+	if !errors.As(err, &typeError) {
+		return nil
+	}
+
+	var unknownFields []string
+	for _, message := range typeError.Errors {
+		if strings.Contains(message, "not found in type") {
+			unknownFields = append(unknownFields, message)
+		}
+	}
+	if len(unknownFields) == 0 {
+		return nil
+	}
+	return &yaml.TypeError{Errors: unknownFields}
+}
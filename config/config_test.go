@@ -0,0 +1,89 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStrict(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      string
+		expectedErr string
+	}{
+		{
+			name: "known keys",
+			config: `
+version: 1
+sensors:
+  - name: sensor
+    outputs:
+      - type: mqtt
+        options:
+          topic: yasp/sensor
+        queue:
+          workers: 2
+        retry:
+          initialBackoff: 1s
+`,
+		},
+		{
+			name: "unknown keys",
+			config: `
+versoin: 1
+sensors:
+  - name: sensor
+    outputs:
+      - type: mqtt
+        queue:
+          worker: 2
+`,
+			expectedErr: "yaml: unmarshal errors:\n" +
+				"  line 2: field versoin not found in type config.Config\n" +
+				"  line 8: field worker not found in type config.OutputQueue",
+		},
+		{
+			name: "bad duration",
+			config: `
+sensors:
+  - name: sensor
+    outputs:
+      - type: mqtt
+        retry:
+          initialBackoff: 5 seconds
+`,
+			expectedErr: "yaml: unmarshal errors:\n" +
+				"  line 7: cannot unmarshal !!str `5 seconds` into time.Duration",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(configFile, []byte(tt.config), 0o600))
+
+			_, err := LoadStrict(configFile)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestLoadIgnoresUnknownKeys(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+sensors:
+  - name: sensor
+    enabeld: true
+`), 0o600))
+
+	config, err := Load(configFile)
+	require.NoError(t, err)
+	assert.Equal(t, "sensor", config.Sensors[0].Name)
+}
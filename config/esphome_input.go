@@ -0,0 +1,46 @@
+package config
+
+import (
+	"net"
+	"time"
+)
+
+// EspHomeInput subscribes to the bluetooth advertisements of an esphome bluetooth proxy over the native api.
+type EspHomeInput struct {
+	Address           string        `yaml:"address"`
+	Password          string        `yaml:"password"`
+	EncryptionKey     string        `yaml:"encryptionKey"`
+	ClientInfo        string        `yaml:"clientInfo"`
+	ServiceDataUuid   string        `yaml:"serviceDataUuid"`
+	ReconnectInterval time.Duration `yaml:"reconnectInterval"`
+	PingInterval      time.Duration `yaml:"pingInterval"`
+}
+
+// GetAddress returns the address of the proxy, the default api port 6053 is used when it has none.
+func (e *EspHomeInput) GetAddress() string {
+	if _, _, err := net.SplitHostPort(e.Address); err != nil {
+		return net.JoinHostPort(e.Address, "6053")
+	}
+	return e.Address
+}
+
+func (e *EspHomeInput) GetClientInfo() string {
+	if e.ClientInfo == "" {
+		return "yasp"
+	}
+	return e.ClientInfo
+}
+
+func (e *EspHomeInput) GetReconnectInterval() time.Duration {
+	if e.ReconnectInterval <= 0 {
+		return 10 * time.Second
+	}
+	return e.ReconnectInterval
+}
+
+func (e *EspHomeInput) GetPingInterval() time.Duration {
+	if e.PingInterval <= 0 {
+		return 20 * time.Second
+	}
+	return e.PingInterval
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"net/url"
+	"time"
+)
+
+type HomeAssistant struct {
+	Enabled             bool          `yaml:"enabled"`
+	BrokerUrls          []*Url        `yaml:"brokerUrls"`
+	Username            string        `yaml:"username"`
+	Password            string        `yaml:"password"`
+	ClientId            string        `yaml:"clientId"`
+	KeepAlive           uint16        `yaml:"keepAlive"`
+	QoS                 byte          `yaml:"qos"`
+	Retain              bool          `yaml:"retain"`
+	DiscoveryPrefix     string        `yaml:"discoveryPrefix"`
+	StateTopicPrefix    string        `yaml:"stateTopicPrefix"`
+	AvailabilityTimeout time.Duration `yaml:"availabilityTimeout"`
+}
+
+func (ha *HomeAssistant) GetBrokerUrls() []*url.URL {
+	urls := make([]*url.URL, len(ha.BrokerUrls))
+	for i := range ha.BrokerUrls {
+		urls[i] = ha.BrokerUrls[i].URL
+	}
+	return urls
+}
+
+func (ha *HomeAssistant) GetDiscoveryPrefix() string {
+	if ha.DiscoveryPrefix == "" {
+		return "homeassistant"
+	}
+	return ha.DiscoveryPrefix
+}
+
+func (ha *HomeAssistant) GetStateTopicPrefix() string {
+	if ha.StateTopicPrefix == "" {
+		return "yasp"
+	}
+	return ha.StateTopicPrefix
+}
+
+func (ha *HomeAssistant) GetAvailabilityTimeout() time.Duration {
+	if ha.AvailabilityTimeout <= 0 {
+		return 10 * time.Minute
+	}
+	return ha.AvailabilityTimeout
+}
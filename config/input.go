@@ -1,6 +1,9 @@
 package config
 
 type Input struct {
+	Name       string        `yaml:"name"`
+	Type       string        `yaml:"type"`
+	Options    Options       `yaml:"options"`
 	Transforms []*Transform  `yaml:"transforms"`
 	Mqtt       *MqttInput    `yaml:"mqtt"`
 	Memphis    *MemphisInput `yaml:"memphis"`
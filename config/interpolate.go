@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const fileReferencePrefix = "file:"
+
+// interpolate expands the references of every scalar value of node in place:
+//
+//	${NAME}           the value of the environment variable NAME, which has to be set
+//	${NAME:-default}  the value of the environment variable NAME, or default when it is unset or empty
+//	${file:/path}     the content of the file at /path without trailing newlines, e.g. a mounted secret
+//	$${               a literal ${
+func interpolate(node *yaml.Node) error {
+	var errs []error
+	walk(node, func(scalar *yaml.Node) {
+		if !strings.Contains(scalar.Value, "${") {
+			return
+		}
+
+		value, err := expand(scalar.Value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", scalar.Line, err))
+			return
+		}
+		scalar.Value = value
+		if scalar.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+			// lets plain values resolve to the type of the expanded value, e.g. keepAlive: ${KEEP_ALIVE}
+			scalar.Tag = ""
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// walk calls fn with every scalar node which is not a mapping key.
+func walk(node *yaml.Node, fn func(scalar *yaml.Node)) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		fn(node)
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			walk(node.Content[i], fn)
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			walk(child, fn)
+		}
+	}
+}
+
+func expand(value string) (string, error) {
+	var sb strings.Builder
+	for {
+		index := strings.Index(value, "${")
+		if index == -1 {
+			sb.WriteString(value)
+			return sb.String(), nil
+		}
+
+		if index > 0 && value[index-1] == '$' {
+			sb.WriteString(value[:index-1])
+			sb.WriteString("${")
+			value = value[index+2:]
+			continue
+		}
+
+		end := strings.IndexByte(value[index:], '}')
+		if end == -1 {
+			return "", fmt.Errorf("unterminated reference: %s", value[index:])
+		}
+
+		resolved, err := resolve(value[index+2 : index+end])
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(value[:index])
+		sb.WriteString(resolved)
+		value = value[index+end+1:]
+	}
+}
+
+func resolve(reference string) (string, error) {
+	if filePath, ok := strings.CutPrefix(reference, fileReferencePrefix); ok {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read referenced file %s: %w", filePath, err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	}
+
+	name, defaultValue, hasDefault := strings.Cut(reference, ":-")
+	if name == "" {
+		return "", fmt.Errorf("invalid reference: ${%s}", reference)
+	}
+	value, exists := os.LookupEnv(name)
+	if hasDefault && value == "" {
+		return defaultValue, nil
+	}
+	if !exists {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
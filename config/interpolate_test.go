@@ -0,0 +1,113 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	assert.NoError(t, os.WriteFile(secretFile, []byte("s3cr3t\n"), 0o600))
+	t.Setenv("YASP_TEST_PASSWORD", "hunter2")
+	t.Setenv("YASP_TEST_EMPTY", "")
+
+	tests := []struct {
+		name          string
+		value         string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "plain value",
+			value:    "tcp://localhost:1883",
+			expected: "tcp://localhost:1883",
+		},
+		{
+			name:     "environment variable",
+			value:    "${YASP_TEST_PASSWORD}",
+			expected: "hunter2",
+		},
+		{
+			name:     "environment variable within value",
+			value:    "user:${YASP_TEST_PASSWORD}@host",
+			expected: "user:hunter2@host",
+		},
+		{
+			name:     "default for unset variable",
+			value:    "${YASP_TEST_UNSET:-fallback}",
+			expected: "fallback",
+		},
+		{
+			name:     "default for empty variable",
+			value:    "${YASP_TEST_EMPTY:-fallback}",
+			expected: "fallback",
+		},
+		{
+			name:     "empty variable",
+			value:    "${YASP_TEST_EMPTY}",
+			expected: "",
+		},
+		{
+			name:     "file reference",
+			value:    "${file:" + secretFile + "}",
+			expected: "s3cr3t",
+		},
+		{
+			name:     "escaped reference",
+			value:    "$${YASP_TEST_PASSWORD}",
+			expected: "${YASP_TEST_PASSWORD}",
+		},
+		{
+			name:          "unset variable",
+			value:         "${YASP_TEST_UNSET}",
+			expectedError: "environment variable YASP_TEST_UNSET is not set",
+		},
+		{
+			name:          "missing file",
+			value:         "${file:/nonexistent/secret}",
+			expectedError: "failed to read referenced file /nonexistent/secret: open /nonexistent/secret: no such file or directory",
+		},
+		{
+			name:          "unterminated reference",
+			value:         "${YASP_TEST_PASSWORD",
+			expectedError: "unterminated reference: ${YASP_TEST_PASSWORD",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := expand(tt.value)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestLoadInterpolated(t *testing.T) {
+	t.Setenv("YASP_TEST_KEEP_ALIVE", "30")
+	t.Setenv("YASP_TEST_PASSWORD", "hunter2")
+
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(configFile, []byte(`
+sensors:
+  - name: sensor
+    input:
+      mqtt:
+        keepAlive: ${YASP_TEST_KEEP_ALIVE}
+        password: "${YASP_TEST_PASSWORD}"
+        clientId: ${YASP_TEST_CLIENT_ID:-yasp}
+`), 0o600))
+
+	config, err := LoadStrict(configFile)
+	assert.NoError(t, err)
+	mqtt := config.Sensors[0].Input.Mqtt
+	assert.Equal(t, uint16(30), mqtt.KeepAlive)
+	assert.Equal(t, "hunter2", mqtt.Password)
+	assert.Equal(t, "yasp", mqtt.ClientId)
+}
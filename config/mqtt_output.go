@@ -13,7 +13,7 @@ type MqttOutput struct {
 	ClientId   string `yaml:"clientId"`
 	KeepAlive  uint16 `yaml:"keepAlive"`
 	QoS        byte   `yaml:"qos"`
-	Retain     bool   `yaml:"bool"`
+	Retain     bool   `yaml:"retain"`
 }
 
 func (m *MqttOutput) GetBrokerUrls() []*url.URL {
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Options is a free-form config section which is decoded by the input or output it belongs to, options holding the
+// same values are deeply equal regardless of where they are located in the config file.
+type Options struct {
+	node *yaml.Node
+}
+
+// NewOptions encodes value as options, used to hand the legacy config keys to their input or output.
+func NewOptions(value any) (Options, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return Options{}, fmt.Errorf("failed to encode options: %w", err)
+	}
+	return Options{node: &node}, nil
+}
+
+func (o *Options) UnmarshalYAML(node *yaml.Node) error {
+	o.node = withoutPosition(node)
+	return nil
+}
+
+// withoutPosition returns a copy of node without the line, column and comments, so a reload only sees changed values.
+func withoutPosition(node *yaml.Node) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	result := *node
+	result.Line = 0
+	result.Column = 0
+	result.HeadComment = ""
+	result.LineComment = ""
+	result.FootComment = ""
+	result.Alias = withoutPosition(node.Alias)
+	if node.Content != nil {
+		result.Content = make([]*yaml.Node, len(node.Content))
+		for i, child := range node.Content {
+			result.Content[i] = withoutPosition(child)
+		}
+	}
+	return &result
+}
+
+func (o Options) MarshalYAML() (any, error) {
+	return o.node, nil
+}
+
+// Decode decodes the options into value, keys which do not map to any field of value are ignored.
+func (o Options) Decode(value any) error {
+	if o.node == nil {
+		return nil
+	}
+	return o.node.Decode(value)
+}
+
+// DecodeStrict decodes the options into value like Decode but fails on keys which do not map to any field of value.
+func (o Options) DecodeStrict(value any) error {
+	if o.node == nil {
+		return nil
+	}
+
+	data, err := yaml.Marshal(o.node)
+	if err != nil {
+		return fmt.Errorf("failed to encode options: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	return decoder.Decode(value)
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestOptionsEqualRegardlessOfPosition(t *testing.T) {
+	const sensor = `
+name: living room
+input:
+  type: mqtt
+  options:
+    topics:
+      - ble/#
+    brokerUrls:
+      - tcp://localhost:1883
+`
+	tests := []struct {
+		name     string
+		other    string
+		expected bool
+	}{
+		{
+			name:     "same config",
+			other:    sensor,
+			expected: true,
+		},
+		{
+			name:     "shifted by a comment line",
+			other:    "# living room sensor\n" + sensor,
+			expected: true,
+		},
+		{
+			name: "commented options",
+			other: `
+name: living room
+input:
+  type: mqtt
+  options:
+    # the gateways publish here
+    topics:
+      - ble/#   # every gateway
+    brokerUrls:
+      - tcp://localhost:1883
+`,
+			expected: true,
+		},
+		{
+			name: "changed option value",
+			other: `
+name: living room
+input:
+  type: mqtt
+  options:
+    topics:
+      - ble/kitchen/#
+    brokerUrls:
+      - tcp://localhost:1883
+`,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var a, b Sensor
+			require.NoError(t, yaml.Unmarshal([]byte(sensor), &a))
+			require.NoError(t, yaml.Unmarshal([]byte(tt.other), &b))
+			assert.Equal(t, tt.expected, reflect.DeepEqual(&a, &b))
+
+			var mqtt MqttInput
+			require.NoError(t, b.Input.Options.DecodeStrict(&mqtt))
+			assert.Len(t, mqtt.BrokerUrls, 1)
+		})
+	}
+}
@@ -1,8 +1,15 @@
 package config
 
 type Output struct {
-	Mqtt       *MqttOutput  `yaml:"mqtt"`
-	InfluxDb2  *InfluxDb2   `yaml:"influxdb2"`
-	Prometheus *Prometheus  `yaml:"prometheus"`
-	Transforms []*Transform `yaml:"transforms"`
+	Type          string           `yaml:"type"`
+	Options       Options          `yaml:"options"`
+	Mqtt          *MqttOutput      `yaml:"mqtt"`
+	InfluxDb2     *InfluxDb2       `yaml:"influxdb2"`
+	Prometheus    *Prometheus      `yaml:"prometheus"`
+	HomeAssistant *HomeAssistant   `yaml:"homeassistant"`
+	Transforms    []*Transform     `yaml:"transforms"`
+	Queue         *OutputQueue     `yaml:"queue"`
+	Retry         *OutputRetry     `yaml:"retry"`
+	Spool         *OutputSpool     `yaml:"spool"`
+	Aggregate     *OutputAggregate `yaml:"aggregate"`
 }
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"time"
+)
+
+// OutputAggregate buckets the numeric value property of the events by the rendered key and publishes a single event per
+// key with the min, max, mean, count and last properties when the window closes.
+type OutputAggregate struct {
+	Enabled bool          `yaml:"enabled"`
+	Window  time.Duration `yaml:"window"`
+	Key     string        `yaml:"key"`
+}
+
+func (oa *OutputAggregate) GetWindow() time.Duration {
+	if oa == nil || oa.Window <= 0 {
+		return time.Minute
+	}
+	return oa.Window
+}
+
+func (oa *OutputAggregate) GetKey() string {
+	if oa == nil || oa.Key == "" {
+		return `{{ index .Properties "deviceName" }}/{{ index .Properties "unit" }}`
+	}
+	return oa.Key
+}
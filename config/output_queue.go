@@ -0,0 +1,34 @@
+package config
+
+const (
+	OverflowPolicyBlock      = "block"
+	OverflowPolicyDropOldest = "drop-oldest"
+	OverflowPolicyDropNewest = "drop-newest"
+)
+
+type OutputQueue struct {
+	Size           int    `yaml:"size"`
+	Workers        int    `yaml:"workers"`
+	OverflowPolicy string `yaml:"overflowPolicy"`
+}
+
+func (oq *OutputQueue) GetSize() int {
+	if oq == nil || oq.Size <= 0 {
+		return 100
+	}
+	return oq.Size
+}
+
+func (oq *OutputQueue) GetWorkers() int {
+	if oq == nil || oq.Workers <= 0 {
+		return 1
+	}
+	return oq.Workers
+}
+
+func (oq *OutputQueue) GetOverflowPolicy() string {
+	if oq == nil || oq.OverflowPolicy == "" {
+		return OverflowPolicyBlock
+	}
+	return oq.OverflowPolicy
+}
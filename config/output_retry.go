@@ -0,0 +1,32 @@
+package config
+
+import (
+	"time"
+)
+
+type OutputRetry struct {
+	MaxAttempts    int           `yaml:"maxAttempts"`
+	InitialBackoff time.Duration `yaml:"initialBackoff"`
+	MaxBackoff     time.Duration `yaml:"maxBackoff"`
+}
+
+func (r *OutputRetry) GetMaxAttempts() int {
+	if r == nil || r.MaxAttempts <= 0 {
+		return 1
+	}
+	return r.MaxAttempts
+}
+
+func (r *OutputRetry) GetInitialBackoff() time.Duration {
+	if r == nil || r.InitialBackoff <= 0 {
+		return time.Second
+	}
+	return r.InitialBackoff
+}
+
+func (r *OutputRetry) GetMaxBackoff() time.Duration {
+	if r == nil || r.MaxBackoff <= 0 {
+		return 30 * time.Second
+	}
+	return r.MaxBackoff
+}
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"time"
+)
+
+type OutputSpool struct {
+	Enabled        bool          `yaml:"enabled"`
+	Directory      string        `yaml:"directory"`
+	MaxSize        int64         `yaml:"maxSize"`
+	MaxAge         time.Duration `yaml:"maxAge"`
+	ReplayInterval time.Duration `yaml:"replayInterval"`
+}
+
+func (s *OutputSpool) GetReplayInterval() time.Duration {
+	if s.ReplayInterval <= 0 {
+		return 10 * time.Second
+	}
+	return s.ReplayInterval
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+)
+
+// Plugin is a single input or output implementation selected by its registered type name.
+type Plugin struct {
+	Type    string
+	Options Options
+	// Key is the config key holding the options, options for the type selected implementation or its legacy key.
+	Key string
+}
+
+// Plugins returns the inputs of the config, the one selected by type followed by every enabled legacy key.
+func (i *Input) Plugins() ([]Plugin, error) {
+	return plugins(i.Type, i.Options, []legacyPlugin{
+		{name: "mqtt", enabled: i.Mqtt != nil && i.Mqtt.Enabled, value: i.Mqtt},
+		{name: "memphis", enabled: i.Memphis != nil && i.Memphis.Enabled, value: i.Memphis},
+	})
+}
+
+// Plugins returns the outputs of the config, the one selected by type followed by every enabled legacy key.
+func (o *Output) Plugins() ([]Plugin, error) {
+	return plugins(o.Type, o.Options, []legacyPlugin{
+		{name: "mqtt", enabled: o.Mqtt != nil && o.Mqtt.Enabled, value: o.Mqtt},
+		{name: "influxdb2", enabled: o.InfluxDb2 != nil && o.InfluxDb2.Enabled, value: o.InfluxDb2},
+		{name: "prometheus", enabled: o.Prometheus != nil && o.Prometheus.Enabled, value: o.Prometheus},
+		{name: "homeassistant", enabled: o.HomeAssistant != nil && o.HomeAssistant.Enabled, value: o.HomeAssistant},
+	})
+}
+
+type legacyPlugin struct {
+	name    string
+	enabled bool
+	value   any
+}
+
+func plugins(pluginType string, options Options, legacyPlugins []legacyPlugin) ([]Plugin, error) {
+	var result []Plugin
+	if pluginType != "" {
+		result = append(result, Plugin{
+			Type:    pluginType,
+			Options: options,
+			Key:     "options",
+		})
+	}
+
+	for _, legacyPlugin := range legacyPlugins {
+		if !legacyPlugin.enabled {
+			continue
+		}
+		legacyOptions, err := NewOptions(legacyPlugin.value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", legacyPlugin.name, err)
+		}
+		result = append(result, Plugin{
+			Type:    legacyPlugin.name,
+			Options: legacyOptions,
+			Key:     legacyPlugin.name,
+		})
+	}
+	return result, nil
+}
@@ -1,9 +1,26 @@
 package config
 
 type Sensor struct {
-	Enabled bool      `yaml:"enabled"`
-	Name    string    `yaml:"name"`
-	Input   *Input    `yaml:"input"`
-	Outputs []*Output `yaml:"outputs"`
-	Devices []*Device `yaml:"devices"`
+	Enabled  bool            `yaml:"enabled"`
+	Name     string          `yaml:"name"`
+	Input    *Input          `yaml:"input"`
+	Inputs   []*Input        `yaml:"inputs"`
+	Merge    *SensorMerge    `yaml:"merge"`
+	Outputs  []*Output       `yaml:"outputs"`
+	Devices  []*Device       `yaml:"devices"`
+	Commands *SensorCommands `yaml:"commands"`
+}
+
+// GetInputs returns the single input followed by every entry of the inputs list.
+func (s *Sensor) GetInputs() []*Input {
+	var inputs []*Input
+	if s.Input != nil {
+		inputs = append(inputs, s.Input)
+	}
+	for _, input := range s.Inputs {
+		if input != nil {
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
 }
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// commandTopicReserved are the characters a device name must not contain to be a level of the command topics.
+const commandTopicReserved = " \t\r\n\x00+#/"
+
+// SensorCommands receives the commands of every device of the sensor on <topic>/<device name>/set and publishes on
+// <topic>/<device name>/ack whether the decoded device state reflected the command within the ack timeout.
+type SensorCommands struct {
+	Enabled    bool          `yaml:"enabled"`
+	Topic      string        `yaml:"topic"`
+	BrokerUrls []*Url        `yaml:"brokerUrls"`
+	Username   string        `yaml:"username"`
+	Password   string        `yaml:"password"`
+	ClientId   string        `yaml:"clientId"`
+	KeepAlive  uint16        `yaml:"keepAlive"`
+	QoS        byte          `yaml:"qos"`
+	AckTimeout time.Duration `yaml:"ackTimeout"`
+}
+
+func (c *SensorCommands) GetBrokerUrls() []*url.URL {
+	urls := make([]*url.URL, len(c.BrokerUrls))
+	for i := range c.BrokerUrls {
+		urls[i] = c.BrokerUrls[i].URL
+	}
+	return urls
+}
+
+func (c *SensorCommands) GetAckTimeout() time.Duration {
+	if c == nil || c.AckTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return c.AckTimeout
+}
+
+// IsTopicLevel reports whether the device name is usable as a level of the command topics.
+func (c *SensorCommands) IsTopicLevel(deviceName string) bool {
+	return !strings.ContainsAny(deviceName, commandTopicReserved)
+}
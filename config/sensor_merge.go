@@ -0,0 +1,18 @@
+package config
+
+import (
+	"time"
+)
+
+// SensorMerge merges the copies of an advertisement received from several gateways within the window into one event.
+type SensorMerge struct {
+	Enabled bool          `yaml:"enabled"`
+	Window  time.Duration `yaml:"window"`
+}
+
+func (m *SensorMerge) GetWindow() time.Duration {
+	if m == nil || m.Window <= 0 {
+		return time.Second
+	}
+	return m.Window
+}
@@ -18,3 +18,10 @@ func (u *Url) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	u.URL = parsedUrl
 	return err
 }
+
+func (u *Url) MarshalYAML() (interface{}, error) {
+	if u == nil || u.URL == nil {
+		return "", nil
+	}
+	return u.URL.String(), nil
+}
@@ -0,0 +1,358 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/template"
+)
+
+// problems collects every validation problem of the configuration, so they can be reported at once.
+type problems struct {
+	errs []error
+}
+
+func (p *problems) add(path, format string, args ...any) {
+	if path == "" {
+		p.errs = append(p.errs, fmt.Errorf(format, args...))
+		return
+	}
+	p.errs = append(p.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (p *problems) required(path, field, value string) {
+	if strings.TrimSpace(value) == "" {
+		p.add(path, "%s is required", field)
+	}
+}
+
+func (p *problems) template(path, field, value string) {
+	if value == "" {
+		return
+	}
+	if err := template.Validate(field, value); err != nil {
+		p.add(path, "invalid %s template: %s", field, err)
+	}
+}
+
+func (p *problems) err() error {
+	return errors.Join(p.errs...)
+}
+
+// Validate checks the required fields of every sensor, input, output and device and parses every template, it returns
+// all found problems joined together. The options of the inputs and outputs are validated by their implementations.
+func (c *Config) Validate() error {
+	var p problems
+	c.Metrics.validate(&p)
+
+	sensorNames := make(map[string]bool, len(c.Sensors))
+	for i, sensor := range c.Sensors {
+		path := fmt.Sprintf("sensors[%d]", i)
+		if sensor == nil {
+			p.add(path, "sensor is empty")
+			continue
+		}
+		if sensor.Name != "" {
+			path = fmt.Sprintf("sensors[%s]", sensor.Name)
+			if sensorNames[sensor.Name] {
+				p.add(path, "duplicate sensor name")
+			}
+			sensorNames[sensor.Name] = true
+		}
+		sensor.validate(&p, path)
+	}
+	return p.err()
+}
+
+func (m *Metrics) validate(p *problems) {
+	if m.Enabled {
+		p.required("metrics", "listenAddr", m.ListenAddr)
+		p.required("metrics", "endpoint", m.Endpoint)
+	}
+
+	for i, mapping := range m.MetricsMapping {
+		path := fmt.Sprintf("metrics.metricsMapping[%d]", i)
+		if mapping == nil {
+			p.add(path, "metrics mapping is empty")
+			continue
+		}
+		p.required(path, "name", mapping.Name)
+		switch strings.ToLower(mapping.Type) {
+		case "counter", "gauge", "summary", "histogram":
+		default:
+			p.add(path, "unsupported type: %q", mapping.Type)
+		}
+	}
+}
+
+func (s *Sensor) validate(p *problems, path string) {
+	p.required(path, "name", s.Name)
+
+	if len(s.GetInputs()) == 0 {
+		p.add(path, "input or inputs is required")
+	}
+	if s.Input != nil {
+		s.Input.validate(p, path+".input")
+	}
+	inputNames := make(map[string]bool, len(s.Inputs))
+	for i, input := range s.Inputs {
+		inputPath := fmt.Sprintf("%s.inputs[%d]", path, i)
+		if input == nil {
+			p.add(inputPath, "input is empty")
+			continue
+		}
+		input.validate(p, inputPath)
+		if input.Name == "" {
+			continue
+		}
+		if inputNames[input.Name] || (s.Input != nil && s.Input.Name == input.Name) {
+			p.add(inputPath, "duplicate input name: %s", input.Name)
+		}
+		inputNames[input.Name] = true
+	}
+
+	if s.Merge != nil && s.Merge.Window < 0 {
+		p.add(path+".merge", "window must not be negative")
+	}
+
+	var enabledOutputs int
+	for i, output := range s.Outputs {
+		outputPath := fmt.Sprintf("%s.outputs[%d]", path, i)
+		if output == nil {
+			p.add(outputPath, "output is empty")
+			continue
+		}
+		if output.validate(p, outputPath) {
+			enabledOutputs++
+		}
+	}
+	if enabledOutputs == 0 {
+		p.add(path, "at least one enabled output is required")
+	}
+
+	for i, device := range s.Devices {
+		devicePath := fmt.Sprintf("%s.devices[%d]", path, i)
+		if device == nil {
+			p.add(devicePath, "device is empty")
+			continue
+		}
+		p.required(devicePath, "name", device.Name)
+		p.required(devicePath, "type", device.Type)
+		// the device name is a level of the command topics
+		if s.Commands != nil && s.Commands.Enabled && !s.Commands.IsTopicLevel(device.Name) {
+			p.add(devicePath, "name must not contain whitespace, +, # or / when commands are enabled: %q", device.Name)
+		}
+	}
+
+	if s.Commands != nil && s.Commands.Enabled {
+		if len(s.Commands.BrokerUrls) == 0 {
+			p.add(path+".commands", "brokerUrls is required")
+		}
+		p.required(path+".commands", "topic", s.Commands.Topic)
+		if strings.ContainsAny(s.Commands.Topic, "+#") {
+			p.add(path+".commands", "topic must not contain wildcards: %s", s.Commands.Topic)
+		}
+		if s.Commands.AckTimeout < 0 {
+			p.add(path+".commands", "ackTimeout must not be negative")
+		}
+	}
+}
+
+func (i *Input) validate(p *problems, path string) {
+	plugins, err := i.Plugins()
+	if err != nil {
+		p.add(path, "%s", err)
+		return
+	}
+
+	if len(plugins) == 0 {
+		p.add(path, "no input is enabled")
+	}
+	validateTransforms(p, path, i.Transforms)
+}
+
+// validate reports the problems of the output and whether it is enabled, disabled outputs are skipped by the sensor.
+func (o *Output) validate(p *problems, path string) bool {
+	plugins, err := o.Plugins()
+	if err != nil {
+		p.add(path, "%s", err)
+		return false
+	}
+	if len(plugins) == 0 {
+		return false
+	}
+	validateTransforms(p, path, o.Transforms)
+
+	if o.Queue != nil {
+		switch o.Queue.GetOverflowPolicy() {
+		case OverflowPolicyBlock, OverflowPolicyDropOldest, OverflowPolicyDropNewest:
+		default:
+			p.add(path+".queue", "unsupported overflowPolicy: %q", o.Queue.OverflowPolicy)
+		}
+	}
+	if o.Spool != nil && o.Spool.Enabled {
+		p.required(path+".spool", "directory", o.Spool.Directory)
+	}
+	if o.Aggregate != nil && o.Aggregate.Enabled {
+		if o.Aggregate.Window < 0 {
+			p.add(path+".aggregate", "window must not be negative")
+		}
+		p.template(path+".aggregate", "key", o.Aggregate.Key)
+	}
+	return true
+}
+
+// Validate reports the missing fields of the mqtt input options.
+func (m *MqttInput) Validate() error {
+	var p problems
+	if len(m.BrokerUrls) == 0 {
+		p.add("", "brokerUrls is required")
+	}
+	if len(m.Topics) == 0 {
+		p.add("", "topics is required")
+	}
+	return p.err()
+}
+
+// Validate reports the missing fields of the memphis input options.
+func (m *MemphisInput) Validate() error {
+	var p problems
+	p.required("", "hostname", m.Hostname)
+	p.required("", "station", m.Station)
+	p.required("", "consumerName", m.ConsumerName)
+	return p.err()
+}
+
+// Validate reports the missing fields of the esphome input options and an encryption key which is not a noise psk.
+func (e *EspHomeInput) Validate() error {
+	var p problems
+	p.required("", "address", e.Address)
+	if e.EncryptionKey != "" {
+		if key, err := base64.StdEncoding.DecodeString(e.EncryptionKey); err != nil || len(key) != 32 {
+			p.add("", "encryptionKey must be a base64 encoded 32 byte key")
+		}
+	}
+	return p.err()
+}
+
+// Validate reports the missing fields and the invalid topic template of the mqtt output options.
+func (m *MqttOutput) Validate() error {
+	var p problems
+	if len(m.BrokerUrls) == 0 {
+		p.add("", "brokerUrls is required")
+	}
+	p.required("", "topic", m.Topic)
+	p.template("", "topic", m.Topic)
+	return p.err()
+}
+
+// Validate reports the missing fields of the home assistant output options.
+func (ha *HomeAssistant) Validate() error {
+	var p problems
+	if len(ha.BrokerUrls) == 0 {
+		p.add("", "brokerUrls is required")
+	}
+	return p.err()
+}
+
+// Validate reports the missing fields and the invalid templates of the influxdb2 output options.
+func (i *InfluxDb2) Validate() error {
+	var p problems
+	p.required("", "url", i.Url)
+	p.required("", "bucket", i.Bucket)
+	p.required("", "measurement", i.Measurement)
+	p.template("", "measurement", i.Measurement)
+	for k, v := range i.TagMapping {
+		p.template("", "tagMapping."+k, v)
+	}
+	for k, v := range i.FieldMapping {
+		p.template("", "fieldMapping."+k, v)
+	}
+	return p.err()
+}
+
+// Validate reports the invalid templates of the prometheus output options and verifies that the metrics are defined
+// by metricsMapping with the same labels.
+func (pr *Prometheus) Validate(metricsMapping []*MetricsMapping) error {
+	var p problems
+	if len(pr.MetricsMapping) == 0 {
+		p.add("", "metricsMapping is required")
+	}
+
+	for i, mapping := range pr.MetricsMapping {
+		mappingPath := fmt.Sprintf("metricsMapping[%d]", i)
+		p.required(mappingPath, "name", mapping.Name)
+		p.required(mappingPath, "value", mapping.Value)
+		p.template(mappingPath, "name", mapping.Name)
+		p.template(mappingPath, "namespace", mapping.Namespace)
+		p.template(mappingPath, "subsystem", mapping.Subsystem)
+		p.template(mappingPath, "value", mapping.Value)
+		p.template(mappingPath, "condition", mapping.Condition)
+		for k, v := range mapping.Labels {
+			p.template(mappingPath, "labels key", k)
+			p.template(mappingPath, "labels."+k, v)
+		}
+
+		// templated names and labels are only known once data is observed
+		if isTemplate(mapping.Name) || isTemplate(mapping.Namespace) || isTemplate(mapping.Subsystem) {
+			continue
+		}
+		index := slices.IndexFunc(metricsMapping, func(m *MetricsMapping) bool {
+			return m != nil &&
+				strings.EqualFold(m.Name, mapping.Name) &&
+				strings.EqualFold(m.Namespace, mapping.Namespace) &&
+				strings.EqualFold(m.Subsystem, mapping.Subsystem)
+		})
+		if index == -1 {
+			p.add(mappingPath, "metric %s is not defined in metrics.metricsMapping", mappingKey(mapping))
+			continue
+		}
+
+		var labels []string
+		for k := range mapping.Labels {
+			if isTemplate(k) {
+				labels = nil
+				break
+			}
+			labels = append(labels, k)
+		}
+		if labels == nil && len(mapping.Labels) != 0 {
+			continue
+		}
+		expectedLabels := slices.Clone(metricsMapping[index].Labels)
+		slices.Sort(labels)
+		slices.Sort(expectedLabels)
+		if !slices.Equal(labels, expectedLabels) {
+			p.add(mappingPath, "labels %v of metric %s do not match metrics.metricsMapping labels %v", labels, mappingKey(mapping), expectedLabels)
+		}
+	}
+	return p.err()
+}
+
+func validateTransforms(p *problems, path string, transforms []*Transform) {
+	for i, transform := range transforms {
+		transformPath := fmt.Sprintf("%s.transforms[%d]", path, i)
+		if transform == nil {
+			p.add(transformPath, "transform is empty")
+			continue
+		}
+		p.required(transformPath, "name", transform.Name)
+	}
+}
+
+func isTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+func mappingKey(mapping PrometheusMetricsMapping) string {
+	var parts []string
+	for _, part := range []string{mapping.Namespace, mapping.Subsystem, mapping.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "_")
+}
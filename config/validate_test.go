@@ -0,0 +1,327 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const validateSensor = `
+name: sensor
+input:
+  type: mqtt
+  options:
+    brokerUrls: [tcp://localhost:1883]
+    topics: [sensors/#]
+outputs:
+  - type: mqtt
+    options:
+      brokerUrls: [tcp://localhost:1883]
+      topic: yasp/sensor
+`
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		metrics     string
+		sensors     []string
+		expectedErr string
+	}{
+		{
+			name:    "valid sensor",
+			sensors: []string{validateSensor},
+		},
+		{
+			name: "commands with a valid device name",
+			sensors: []string{validateSensor + `
+devices:
+  - name: heat-pump
+    type: p1p2
+commands:
+  enabled: true
+  topic: yasp/commands
+  brokerUrls: [tcp://localhost:1883]
+`},
+		},
+		{
+			name: "commands with a device name which is not a topic level",
+			sensors: []string{validateSensor + `
+devices:
+  - name: heat pump
+    type: p1p2
+  - name: heat/pump
+    type: p1p2
+  - name: heat+pump
+    type: p1p2
+commands:
+  enabled: true
+  topic: yasp/commands/#
+  brokerUrls: [tcp://localhost:1883]
+`},
+			expectedErr: "sensors[sensor].devices[0]: name must not contain whitespace, +, # or / when commands are enabled: \"heat pump\"\n" +
+				"sensors[sensor].devices[1]: name must not contain whitespace, +, # or / when commands are enabled: \"heat/pump\"\n" +
+				"sensors[sensor].devices[2]: name must not contain whitespace, +, # or / when commands are enabled: \"heat+pump\"\n" +
+				"sensors[sensor].commands: topic must not contain wildcards: yasp/commands/#",
+		},
+		{
+			name: "device names are not checked without commands",
+			sensors: []string{validateSensor + `
+devices:
+  - name: heat pump
+    type: p1p2
+`},
+		},
+		{
+			name:        "duplicate sensor names",
+			sensors:     []string{validateSensor, validateSensor},
+			expectedErr: "sensors[sensor]: duplicate sensor name",
+		},
+		{
+			name: "missing names and types",
+			sensors: []string{`
+input:
+  type: mqtt
+  options:
+    brokerUrls: [tcp://localhost:1883]
+    topics: [sensors/#]
+outputs:
+  - type: mqtt
+    options:
+      topic: yasp/sensor
+    transforms:
+      - properties: {unit: temperature}
+devices:
+  - name: thermometer
+`},
+			expectedErr: "sensors[0]: name is required\n" +
+				"sensors[0].outputs[0].transforms[0]: name is required\n" +
+				"sensors[0].devices[0]: type is required",
+		},
+		{
+			name: "duplicate input names",
+			sensors: []string{`
+name: sensor
+inputs:
+  - name: broker
+    type: mqtt
+    options:
+      brokerUrls: [tcp://localhost:1883]
+      topics: [sensors/#]
+  - name: broker
+    type: mqtt
+    options:
+      brokerUrls: [tcp://localhost:1883]
+      topics: [other/#]
+outputs:
+  - type: mqtt
+    options:
+      brokerUrls: [tcp://localhost:1883]
+      topic: yasp/sensor
+`},
+			expectedErr: "sensors[sensor].inputs[1]: duplicate input name: broker",
+		},
+		{
+			name: "invalid output settings",
+			sensors: []string{`
+name: sensor
+input:
+  type: mqtt
+  options:
+    brokerUrls: [tcp://localhost:1883]
+    topics: [sensors/#]
+merge:
+  window: -1s
+outputs:
+  - type: mqtt
+    options:
+      brokerUrls: [tcp://localhost:1883]
+      topic: '{{ .Properties'
+    queue:
+      overflowPolicy: drop-everything
+    spool:
+      enabled: true
+    aggregate:
+      enabled: true
+      window: -1m
+`},
+			expectedErr: "sensors[sensor].merge: window must not be negative\n" +
+				"sensors[sensor].outputs[0].queue: unsupported overflowPolicy: \"drop-everything\"\n" +
+				"sensors[sensor].outputs[0].spool: directory is required\n" +
+				"sensors[sensor].outputs[0].aggregate: window must not be negative",
+		},
+		{
+			name: "no enabled input or output",
+			sensors: []string{`
+name: sensor
+input:
+  mqtt:
+    enabled: false
+outputs:
+  - mqtt:
+      enabled: false
+`},
+			expectedErr: "sensors[sensor].input: no input is enabled\n" +
+				"sensors[sensor]: at least one enabled output is required",
+		},
+		{
+			name: "unsupported metric type",
+			metrics: `
+metricsMapping:
+  - name: temperature
+    type: gauge
+    labels: [device]
+  - name: humidity
+    type: histogrammm
+`,
+			sensors: []string{`
+name: sensor
+input:
+  type: mqtt
+  options:
+    brokerUrls: [tcp://localhost:1883]
+    topics: [sensors/#]
+outputs:
+  - type: prometheus
+    options:
+      metricsMapping:
+        - name: temperature
+          value: '{{ index .Properties "value" }}'
+          labels:
+            room: kitchen
+        - name: pressure
+          value: '{{ index .Properties "value" }}'
+`},
+			expectedErr: "metrics.metricsMapping[1]: unsupported type: \"histogrammm\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{}
+			require.NoError(t, yaml.Unmarshal([]byte(tt.metrics), &config.Metrics))
+			for _, value := range tt.sensors {
+				var sensor Sensor
+				require.NoError(t, yaml.Unmarshal([]byte(value), &sensor))
+				config.Sensors = append(config.Sensors, &sensor)
+			}
+
+			err := config.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestPluginValidate(t *testing.T) {
+	metricsMapping := []*MetricsMapping{
+		{Name: "temperature", Type: "gauge", Labels: []string{"device"}},
+	}
+
+	tests := []struct {
+		name        string
+		options     string
+		validate    func(options Options) error
+		expectedErr string
+	}{
+		{
+			name:    "valid mqtt input",
+			options: `{brokerUrls: [tcp://localhost:1883], topics: [sensors/#]}`,
+			validate: func(options Options) error {
+				var mqtt MqttInput
+				require.NoError(t, options.DecodeStrict(&mqtt))
+				return mqtt.Validate()
+			},
+		},
+		{
+			name:    "mqtt input without broker and topics",
+			options: `{}`,
+			validate: func(options Options) error {
+				var mqtt MqttInput
+				require.NoError(t, options.DecodeStrict(&mqtt))
+				return mqtt.Validate()
+			},
+			expectedErr: "brokerUrls is required\ntopics is required",
+		},
+		{
+			name:    "mqtt output without broker",
+			options: `{topic: yasp/sensor}`,
+			validate: func(options Options) error {
+				var mqtt MqttOutput
+				require.NoError(t, options.DecodeStrict(&mqtt))
+				return mqtt.Validate()
+			},
+			expectedErr: "brokerUrls is required",
+		},
+		{
+			name:    "mqtt output with an invalid topic template",
+			options: `{brokerUrls: [tcp://localhost:1883], topic: '{{ .Properties'}`,
+			validate: func(options Options) error {
+				var mqtt MqttOutput
+				require.NoError(t, options.DecodeStrict(&mqtt))
+				return mqtt.Validate()
+			},
+			expectedErr: "invalid topic template: failed to parse template: template: topic:1: unclosed action",
+		},
+		{
+			name:    "esphome input with an invalid encryption key",
+			options: `{address: 192.168.1.10, encryptionKey: c2hvcnQ=}`,
+			validate: func(options Options) error {
+				var espHome EspHomeInput
+				require.NoError(t, options.DecodeStrict(&espHome))
+				return espHome.Validate()
+			},
+			expectedErr: "encryptionKey must be a base64 encoded 32 byte key",
+		},
+		{
+			name: "mapped prometheus metric",
+			options: `
+metricsMapping:
+  - name: temperature
+    value: '{{ index .Properties "value" }}'
+    labels:
+      device: '{{ index .Properties "deviceName" }}'
+`,
+			validate: func(options Options) error {
+				var prometheus Prometheus
+				require.NoError(t, options.DecodeStrict(&prometheus))
+				return prometheus.Validate(metricsMapping)
+			},
+		},
+		{
+			name: "prometheus metric not mapped",
+			options: `
+metricsMapping:
+  - name: temperature
+    value: '{{ index .Properties "value" }}'
+    labels:
+      room: kitchen
+  - name: pressure
+    value: '{{ index .Properties "value" }}'
+`,
+			validate: func(options Options) error {
+				var prometheus Prometheus
+				require.NoError(t, options.DecodeStrict(&prometheus))
+				return prometheus.Validate(metricsMapping)
+			},
+			expectedErr: "metricsMapping[0]: labels [room] of metric temperature do not match metrics.metricsMapping labels [device]\n" +
+				"metricsMapping[1]: metric pressure is not defined in metrics.metricsMapping",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options Options
+			require.NoError(t, yaml.Unmarshal([]byte(tt.options), &options))
+
+			err := tt.validate(options)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
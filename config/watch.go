@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Watch polls the config file or directory at filePath every interval and notifies the returned channel
+// whenever the content fingerprint (path, size and modification time of every config file) changes.
+func Watch(ctx context.Context, filePath string, interval time.Duration) <-chan struct{} {
+	changes := make(chan struct{}, 1)
+	if interval <= 0 {
+		return changes
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		lastFingerprint, err := fingerprint(filePath)
+		if err != nil {
+			logrus.WithError(err).WithField("path", filePath).Warn("config: failed to fingerprint config")
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				currentFingerprint, err := fingerprint(filePath)
+				if err != nil {
+					logrus.WithError(err).WithField("path", filePath).Warn("config: failed to fingerprint config")
+					continue
+				}
+				if currentFingerprint == lastFingerprint {
+					continue
+				}
+				lastFingerprint = currentFingerprint
+
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return changes
+}
+
+func fingerprint(filePath string) (string, error) {
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	if !stat.IsDir() {
+		_, _ = fmt.Fprintf(hash, "%s,%d,%d", filePath, stat.Size(), stat.ModTime().UnixNano())
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+
+	err = filepath.WalkDir(filePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !isConfigFile(path) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(hash, "%s,%d,%d;", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(t *testing.T, dir string)
+		notify bool
+	}{
+		{
+			name:   "unchanged config",
+			change: func(t *testing.T, dir string) {},
+			notify: false,
+		},
+		{
+			name: "modified config file",
+			change: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("sensors: [ ]\n"), 0o644))
+			},
+			notify: true,
+		},
+		{
+			name: "added config file",
+			change: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "sensors.yaml"), []byte("sensors: []\n"), 0o644))
+			},
+			notify: true,
+		},
+		{
+			name: "added unrelated file",
+			change: func(t *testing.T, dir string) {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("notes\n"), 0o644))
+			},
+			notify: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("sensors: []\n"), 0o644))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			changes := Watch(ctx, dir, 10*time.Millisecond)
+
+			// let the watcher take the initial fingerprint before changing the config
+			time.Sleep(50 * time.Millisecond)
+			tt.change(t, dir)
+
+			select {
+			case <-changes:
+				require.True(t, tt.notify, "unexpected change notification")
+			case <-time.After(200 * time.Millisecond):
+				require.False(t, tt.notify, "missing change notification")
+			}
+		})
+	}
+}
+
+func TestWatchDisabled(t *testing.T) {
+	changes := Watch(context.Background(), t.TempDir(), 0)
+	select {
+	case <-changes:
+		t.Fatal("unexpected change notification")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
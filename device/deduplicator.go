@@ -0,0 +1,78 @@
+package device
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/nikiforov-soft/yasp/internal/ttlmap"
+)
+
+const (
+	DeduplicationWindowPropertiesKey = "dedupeWindow"
+	DefaultDeduplicationWindow       = 10 * time.Second
+
+	deduplicationSize = 1024
+)
+
+var (
+	duplicatesDroppedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "duplicates_dropped",
+		Help:      "The amount of duplicated frames the devices dropped.",
+		Namespace: "yasp",
+		Subsystem: "device",
+	}, []string{"deviceType"})
+)
+
+// Deduplicator drops frames whose packet id or frame counter matches the last one seen for the same mac address within a window.
+// The frames are keyed by the mac address of the advertisement, as a device without a configured mac address decodes the
+// advertisements of every sensor of its type.
+type Deduplicator struct {
+	deviceType string
+	window     time.Duration
+	now        func() time.Time
+	lastSeen   *ttlmap.Map[string, uint32]
+	lock       sync.Mutex
+}
+
+// NewDeduplicator creates a deduplicator using the window from the device dedupeWindow property, a window of 0 disables deduplication.
+func NewDeduplicator(deviceType string, properties map[string]string) (*Deduplicator, error) {
+	window := DefaultDeduplicationWindow
+	if windowValue, exists := properties[DeduplicationWindowPropertiesKey]; exists {
+		var err error
+		window, err = time.ParseDuration(windowValue)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s property value: %w", DeduplicationWindowPropertiesKey, err)
+		}
+	}
+
+	return &Deduplicator{
+		deviceType: deviceType,
+		window:     window,
+		now:        time.Now,
+		lastSeen:   ttlmap.New[string, uint32](window, deduplicationSize),
+	}, nil
+}
+
+// IsDuplicate reports whether id was already seen for the advertisement macAddress within the window, duplicates are counted
+// in the dropped metric.
+func (d *Deduplicator) IsDuplicate(macAddress string, id uint32) bool {
+	if d.window <= 0 {
+		return false
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	now := d.now()
+	lastId, seenAt, exists := d.lastSeen.Load(macAddress)
+	if exists && lastId == id && now.Sub(seenAt) < d.window {
+		duplicatesDroppedCounter.WithLabelValues(d.deviceType).Inc()
+		return true
+	}
+	d.lastSeen.Store(macAddress, id, now)
+	return false
+}
@@ -0,0 +1,94 @@
+package device
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduplicator(t *testing.T) {
+	type frame struct {
+		after      time.Duration
+		macAddress string
+		id         uint32
+		duplicate  bool
+	}
+	tests := []struct {
+		name       string
+		properties map[string]string
+		frames     []frame
+	}{
+		{
+			name: "repeated counter inside the window",
+			frames: []frame{
+				{macAddress: "a4:c1:38:00:00:01", id: 1},
+				{after: time.Second, macAddress: "a4:c1:38:00:00:01", id: 1, duplicate: true},
+				{after: 8 * time.Second, macAddress: "a4:c1:38:00:00:01", id: 1, duplicate: true},
+				{after: time.Second, macAddress: "a4:c1:38:00:00:01", id: 2},
+			},
+		},
+		{
+			name: "repeated counter outside the window",
+			frames: []frame{
+				{macAddress: "a4:c1:38:00:00:01", id: 1},
+				{after: 10 * time.Second, macAddress: "a4:c1:38:00:00:01", id: 1},
+				{after: 9 * time.Second, macAddress: "a4:c1:38:00:00:01", id: 1, duplicate: true},
+			},
+		},
+		{
+			name:       "configured window",
+			properties: map[string]string{"dedupeWindow": "1m"},
+			frames: []frame{
+				{macAddress: "a4:c1:38:00:00:01", id: 1},
+				{after: 59 * time.Second, macAddress: "a4:c1:38:00:00:01", id: 1, duplicate: true},
+				{after: time.Minute, macAddress: "a4:c1:38:00:00:01", id: 1},
+			},
+		},
+		{
+			name:       "disabled",
+			properties: map[string]string{"dedupeWindow": "0s"},
+			frames: []frame{
+				{macAddress: "a4:c1:38:00:00:01", id: 1},
+				{macAddress: "a4:c1:38:00:00:01", id: 1},
+			},
+		},
+		{
+			name: "counters of other advertisements",
+			frames: []frame{
+				{macAddress: "a4:c1:38:00:00:01", id: 1},
+				{macAddress: "a4:c1:38:00:00:02", id: 1},
+				{macAddress: "a4:c1:38:00:00:01", id: 2},
+				{macAddress: "a4:c1:38:00:00:02", id: 1, duplicate: true},
+				{macAddress: "a4:c1:38:00:00:01", id: 1},
+			},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deviceType := fmt.Sprintf("deduplicator-test-%d", i)
+			d, err := NewDeduplicator(deviceType, tt.properties)
+			require.NoError(t, err)
+			now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			d.now = func() time.Time { return now }
+
+			var duplicates int
+			for j, f := range tt.frames {
+				now = now.Add(f.after)
+				assert.Equal(t, f.duplicate, d.IsDuplicate(f.macAddress, f.id), "frame %d", j)
+				if f.duplicate {
+					duplicates++
+				}
+			}
+			assert.Equal(t, float64(duplicates), testutil.ToFloat64(duplicatesDroppedCounter.WithLabelValues(deviceType)))
+		})
+	}
+}
+
+func TestDeduplicatorInvalidWindow(t *testing.T) {
+	_, err := NewDeduplicator("deduplicator-test", map[string]string{"dedupeWindow": "10"})
+	assert.EqualError(t, err, `invalid dedupeWindow property value: time: missing unit in duration "10"`)
+}
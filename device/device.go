@@ -5,5 +5,18 @@ import (
 )
 
 type Device interface {
-	Decode(ctx context.Context, data *Data) (*Data, error)
+	// Decode decodes the input data into zero or more events, nil is returned when the data does not belong to the device.
+	Decode(ctx context.Context, data *Data) ([]*Data, error)
+}
+
+// Command is a high-level command for a device, e.g. {"temperature": 22, "mode": "cooling"}.
+type Command map[string]interface{}
+
+// Commander is implemented by the devices which can be controlled.
+type Commander interface {
+	// Encode encodes the command into the messages to send, the topic of every message is set by its outputTopic property.
+	// The returned command is the one acknowledged, completed with the values resolved by the device, e.g. its unit.
+	Encode(ctx context.Context, command Command) (Command, []*Data, error)
+	// Acknowledged reports whether the decoded event reflects the state requested by the command.
+	Acknowledged(command Command, event *Data) bool
 }
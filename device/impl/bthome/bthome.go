@@ -0,0 +1,122 @@
+package bthome
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+	"github.com/nikiforov-soft/yasp/device/vendors/bthome"
+)
+
+const (
+	deviceType                 = "bthome"
+	macAddressPropertiesKey    = "macAddress"
+	encryptionKeyPropertiesKey = "encryptionKey"
+)
+
+type bthomeDevice struct {
+	name         string
+	macAddress   string
+	decoder      *bthome.Decoder
+	deduplicator *device.Deduplicator
+}
+
+func (bd *bthomeDevice) Decode(_ context.Context, data *device.Data) ([]*device.Data, error) {
+	macAddress, ok := device.MacAddress(data.Properties)
+	if !ok || macAddress != bd.macAddress {
+		return nil, nil
+	}
+
+	sensorData, err := bd.decoder.Decode(data.Data)
+	if err != nil {
+		if errors.Is(err, bthome.ErrBindKeyRequired) {
+			logrus.
+				WithField("deviceName", bd.name).
+				Debug("bthome: dropping encrypted advertisement, the device has no encryptionKey property")
+			return nil, nil
+		}
+		if errors.Is(err, bthome.ErrReplayedCounter) {
+			logrus.
+				WithError(err).
+				WithField("deviceName", bd.name).
+				Debug("bthome: dropping replayed advertisement")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("bthome: failed to parse sensor data: device name: %s, data: %s - %w", bd.name, hex.EncodeToString(data.Data), err)
+	}
+
+	if sensorData.HasPacketId && bd.deduplicator.IsDuplicate(bd.macAddress, uint32(sensorData.PacketId)) {
+		return nil, nil
+	}
+
+	result := make([]*device.Data, 0, len(sensorData.Measurements))
+	for _, measurement := range sensorData.Measurements {
+		value := measurement.FormattedValue()
+
+		properties := make(map[string]interface{}, len(data.Properties)+8)
+		for k, v := range data.Properties {
+			properties[k] = v
+		}
+		properties["deviceName"] = bd.name
+		properties["deviceType"] = deviceType
+		properties["deviceMacAddress"] = bd.macAddress
+		properties["unit"] = measurement.Type
+		properties["unitOfMeasurement"] = measurement.Unit
+		properties["measurementIndex"] = strconv.Itoa(measurement.Index)
+		properties["objectId"] = strconv.Itoa(int(measurement.ObjectId))
+		properties["value"] = value
+
+		result = append(result, &device.Data{
+			Data:       []byte(value),
+			Properties: properties,
+		})
+	}
+	return result, nil
+}
+
+func init() {
+	err := device.RegisterDevice(deviceType, func(ctx context.Context, config *config.Device) (device.Device, error) {
+		macAddressValue, exists := config.Properties[macAddressPropertiesKey]
+		if !exists {
+			return nil, fmt.Errorf("bthome: device %s is missing %s property", config.Name, macAddressPropertiesKey)
+		}
+
+		macAddress, err := net.ParseMAC(macAddressValue)
+		if err != nil {
+			return nil, fmt.Errorf("bthome: device %s has invalid mac address value: %w", config.Name, err)
+		}
+
+		var encryptionKey []byte
+		if encryptionKeyValue, exists := config.Properties[encryptionKeyPropertiesKey]; exists {
+			encryptionKey, err = hex.DecodeString(encryptionKeyValue)
+			if err != nil {
+				return nil, fmt.Errorf("bthome: device %s has invalid encryption key value: %w", config.Name, err)
+			}
+			if len(encryptionKey) != 16 {
+				return nil, fmt.Errorf("bthome: device %s has invalid encryption key length: %d", config.Name, len(encryptionKey))
+			}
+		}
+
+		deduplicator, err := device.NewDeduplicator(deviceType, config.Properties)
+		if err != nil {
+			return nil, fmt.Errorf("bthome: device %s - %w", config.Name, err)
+		}
+
+		return &bthomeDevice{
+			name:         config.Name,
+			macAddress:   macAddress.String(),
+			decoder:      bthome.NewDecoder(macAddress.String(), encryptionKey),
+			deduplicator: deduplicator,
+		}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
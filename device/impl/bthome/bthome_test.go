@@ -0,0 +1,148 @@
+package bthome
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name        string
+		properties  map[string]string
+		macAddress  string
+		data        string
+		expected    []map[string]interface{}
+		expectedErr string
+	}{
+		{
+			name:       "one event per measurement",
+			properties: map[string]string{"macAddress": "54:48:E6:8F:80:A5"},
+			macAddress: "54:48:e6:8f:80:a5",
+			data:       "4002d5fd03bf1304138a0112e2040c020c",
+			expected: []map[string]interface{}{
+				{"unit": "Temperature", "unitOfMeasurement": "°C", "objectId": "2", "measurementIndex": "0", "value": "-5.55", "deviceMacAddress": "54:48:e6:8f:80:a5"},
+				{"unit": "Humidity", "unitOfMeasurement": "%", "objectId": "3", "value": "50.55"},
+				{"unit": "Pressure", "unitOfMeasurement": "hPa", "objectId": "4", "value": "1008.83"},
+				{"unit": "CO2", "unitOfMeasurement": "ppm", "objectId": "18", "value": "1250"},
+				{"unit": "Voltage", "unitOfMeasurement": "V", "objectId": "12", "value": "3.074"},
+			},
+		},
+		{
+			name:       "repeated measurements are indexed",
+			properties: map[string]string{"macAddress": "54:48:E6:8F:80:A5"},
+			macAddress: "54:48:E6:8F:80:A5",
+			data:       "443a013a004511015840",
+			expected: []map[string]interface{}{
+				{"unit": "Button", "measurementIndex": "0", "value": "press"},
+				{"unit": "Button", "measurementIndex": "1", "value": "none"},
+				{"unit": "Temperature", "measurementIndex": "0", "value": "27.3"},
+				{"unit": "Temperature", "measurementIndex": "1", "value": "22.4"},
+			},
+		},
+		{
+			name:       "advertisement of another device",
+			properties: map[string]string{"macAddress": "54:48:E6:8F:80:A5"},
+			macAddress: "54:48:E6:8F:80:A6",
+			data:       "4002d5fd03bf1304138a0112e2040c020c",
+		},
+		{
+			name:       "advertisement without mac address",
+			properties: map[string]string{"macAddress": "54:48:E6:8F:80:A5"},
+			data:       "4002d5fd03bf1304138a0112e2040c020c",
+		},
+		{
+			name:       "encrypted",
+			properties: map[string]string{"macAddress": "54:48:E6:8F:80:A5", "encryptionKey": "231d39c1d7cc1ab1aee224cd096db932"},
+			macAddress: "54:48:E6:8F:80:A5",
+			data:       "41a47266c95f730011223378237214",
+			expected: []map[string]interface{}{
+				{"unit": "Temperature", "value": "25.06"},
+				{"unit": "Humidity", "value": "50.55"},
+			},
+		},
+		{
+			name:       "encrypted without key",
+			properties: map[string]string{"macAddress": "54:48:E6:8F:80:A5"},
+			macAddress: "54:48:E6:8F:80:A5",
+			data:       "41a47266c95f730011223378237214",
+		},
+		{
+			name:        "encrypted with wrong key",
+			properties:  map[string]string{"macAddress": "54:48:E6:8F:80:A5", "encryptionKey": "231d39c1d7cc1ab1aee224cd096db933"},
+			macAddress:  "54:48:E6:8F:80:A5",
+			data:        "41a47266c95f730011223378237214",
+			expectedErr: "bthome: failed to parse sensor data: device name: thermometer, data: 41a47266c95f730011223378237214 - bthome: failed to read encrypted payload: failed to decrypt data: AESCCM: Message authentication failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := device.NewDevice(context.Background(), &config.Device{
+				Name:       "thermometer",
+				Type:       deviceType,
+				Properties: tt.properties,
+			})
+			require.NoError(t, err)
+
+			data, err := hex.DecodeString(tt.data)
+			require.NoError(t, err)
+
+			properties := map[string]interface{}{}
+			if tt.macAddress != "" {
+				properties["macAddress"] = tt.macAddress
+			}
+			result, err := dev.Decode(context.Background(), &device.Data{
+				Data:       data,
+				Properties: properties,
+			})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, len(tt.expected))
+			for i, expected := range tt.expected {
+				assert.Equal(t, "thermometer", result[i].Properties["deviceName"])
+				for k, v := range expected {
+					assert.Equal(t, v, result[i].Properties[k], k)
+				}
+				assert.Equal(t, expected["value"], string(result[i].Data))
+			}
+		})
+	}
+}
+
+func TestInvalidEncryptionKey(t *testing.T) {
+	tests := []struct {
+		name          string
+		encryptionKey string
+		expectedErr   string
+	}{
+		{
+			name:          "not hex",
+			encryptionKey: "not a key",
+			expectedErr:   "bthome: device thermometer has invalid encryption key value: encoding/hex: invalid byte: U+006E 'n'",
+		},
+		{
+			name:          "invalid length",
+			encryptionKey: "231d39c1d7cc1ab1",
+			expectedErr:   "bthome: device thermometer has invalid encryption key length: 8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := device.NewDevice(context.Background(), &config.Device{
+				Name:       "thermometer",
+				Type:       deviceType,
+				Properties: map[string]string{"macAddress": "54:48:E6:8F:80:A5", "encryptionKey": tt.encryptionKey},
+			})
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
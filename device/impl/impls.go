@@ -1,8 +1,11 @@
 package impl
 
 import (
+	_ "github.com/nikiforov-soft/yasp/device/impl/bthome"
 	_ "github.com/nikiforov-soft/yasp/device/impl/lywsd03mmc"
+	_ "github.com/nikiforov-soft/yasp/device/impl/mibeacon"
 	_ "github.com/nikiforov-soft/yasp/device/impl/p1p2"
 	_ "github.com/nikiforov-soft/yasp/device/impl/passthrough"
 	_ "github.com/nikiforov-soft/yasp/device/impl/shelly"
+	_ "github.com/nikiforov-soft/yasp/device/impl/zigbee2mqtt"
 )
@@ -11,6 +11,7 @@ import (
 
 	"github.com/nikiforov-soft/yasp/config"
 	"github.com/nikiforov-soft/yasp/device"
+	"github.com/nikiforov-soft/yasp/device/vendors/atc"
 	"github.com/nikiforov-soft/yasp/device/vendors/xiaomi"
 )
 
@@ -18,15 +19,44 @@ const (
 	deviceType                 = "LYWSD03MMC"
 	macAddressPropertiesKey    = "macAddress"
 	encryptionKeyPropertiesKey = "encryptionKey"
+	firmwarePropertiesKey      = "firmware"
+)
+
+// The firmware selects the advertisement formats of the sensor, auto detects the unencrypted custom formats by the
+// mac address they carry, the encrypted ones by decrypting them with the encryption key, and decodes everything else
+// as MiBeacon.
+const (
+	firmwareAuto    = "auto"
+	firmwareStock   = "stock"
+	firmwareAtc1441 = "atc1441"
+	firmwarePvvx    = "pvvx"
 )
 
 type lywsd03mmc struct {
 	name          string
 	macAddress    string
+	firmware      string
 	encryptionKey []byte
+	deduplicator  *device.Deduplicator
 }
 
-func (s *lywsd03mmc) Decode(_ context.Context, data *device.Data) (*device.Data, error) {
+type measurement struct {
+	unit  string
+	value string
+}
+
+func (s *lywsd03mmc) Decode(_ context.Context, data *device.Data) ([]*device.Data, error) {
+	if macAddress, ok := device.MacAddress(data.Properties); ok && macAddress != s.macAddress {
+		return nil, nil
+	}
+
+	if format := s.customFormat(data.Data); format != atc.FormatUnknown {
+		return s.decodeCustom(format, data)
+	}
+	if s.firmware == firmwareAtc1441 {
+		return nil, nil
+	}
+
 	frame, err := xiaomi.ParseBLEFrame(data.Data, func(mac string) ([]byte, error) {
 		if !strings.EqualFold(s.macAddress, mac) {
 			return nil, nil
@@ -43,6 +73,10 @@ func (s *lywsd03mmc) Decode(_ context.Context, data *device.Data) (*device.Data,
 		return nil, err
 	}
 
+	if s.deduplicator.IsDuplicate(s.macAddress, uint32(frame.FrameCounter)) {
+		return nil, nil
+	}
+
 	var unit string
 	var value string
 	switch event := frame.Event.(type) {
@@ -61,20 +95,85 @@ func (s *lywsd03mmc) Decode(_ context.Context, data *device.Data) (*device.Data,
 		return nil, fmt.Errorf("LYWSD03MMC: unhandled sensor data: device name: %s, macAddress: %s, event: %T", s.name, frame.MacAddress, event)
 	}
 
-	properties := make(map[string]interface{}, len(data.Properties)+5)
-	for k, v := range data.Properties {
-		properties[k] = v
+	return s.events(data, firmwareStock, frame.FrameCounter, []measurement{{unit: unit, value: value}}), nil
+}
+
+// customFormat returns the custom firmware format of data, or unknown when data is to be decoded as MiBeacon.
+func (s *lywsd03mmc) customFormat(data []byte) atc.Format {
+	format := atc.DetectFormat(data)
+	switch s.firmware {
+	case firmwareAtc1441:
+		if format == atc.FormatAtc1441 || format == atc.FormatAtc1441Encrypted {
+			return format
+		}
+	case firmwarePvvx:
+		// pvvx advertises its own formats as well as the atc1441 ones
+		return format
+	case firmwareAuto:
+		if format == atc.FormatAtc1441 || format == atc.FormatPvvx {
+			sensorData, err := atc.Parse(format, data, s.macAddress, nil)
+			if err == nil && sensorData.MacAddress == s.macAddress {
+				return format
+			}
+		}
+		// the encrypted formats carry no mac address, the nonce of the sensor only authenticates its own advertisements
+		if format.IsEncrypted() && len(s.encryptionKey) != 0 {
+			if _, err := atc.Parse(format, data, s.macAddress, s.encryptionKey); err == nil {
+				return format
+			}
+		}
 	}
-	properties["deviceName"] = s.name
-	properties["deviceType"] = deviceType
-	properties["deviceMacAddress"] = s.macAddress
-	properties["unit"] = unit
-	properties["value"] = value
-
-	return &device.Data{
-		Data:       []byte(value),
-		Properties: properties,
-	}, nil
+	return atc.FormatUnknown
+}
+
+func (s *lywsd03mmc) decodeCustom(format atc.Format, data *device.Data) ([]*device.Data, error) {
+	sensorData, err := atc.Parse(format, data.Data, s.macAddress, s.encryptionKey)
+	if err != nil {
+		if errors.Is(err, atc.ErrBindKeyRequired) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("LYWSD03MMC: failed to parse %s advertisement: device name: %s, data: %s - %w", format, s.name, hex.EncodeToString(data.Data), err)
+	}
+	if sensorData.MacAddress != "" && sensorData.MacAddress != s.macAddress {
+		return nil, nil
+	}
+
+	if s.deduplicator.IsDuplicate(s.macAddress, uint32(sensorData.Counter)) {
+		return nil, nil
+	}
+
+	measurements := []measurement{
+		{unit: "Temperature", value: strconv.FormatFloat(sensorData.Temperature, 'f', 2, 64)},
+		{unit: "Humidity", value: strconv.FormatFloat(sensorData.Humidity, 'f', 2, 64)},
+		{unit: "Battery", value: strconv.FormatInt(int64(sensorData.Battery), 10)},
+	}
+	if sensorData.HasVoltage {
+		measurements = append(measurements, measurement{unit: "Voltage", value: strconv.FormatFloat(float64(sensorData.Voltage)/1000.0, 'f', 3, 64)})
+	}
+	return s.events(data, format.String(), sensorData.Counter, measurements), nil
+}
+
+func (s *lywsd03mmc) events(data *device.Data, format string, frameCounter uint8, measurements []measurement) []*device.Data {
+	result := make([]*device.Data, 0, len(measurements))
+	for _, m := range measurements {
+		properties := make(map[string]interface{}, len(data.Properties)+7)
+		for k, v := range data.Properties {
+			properties[k] = v
+		}
+		properties["deviceName"] = s.name
+		properties["deviceType"] = deviceType
+		properties["deviceMacAddress"] = s.macAddress
+		properties["format"] = format
+		properties["frameCounter"] = strconv.Itoa(int(frameCounter))
+		properties["unit"] = m.unit
+		properties["value"] = m.value
+
+		result = append(result, &device.Data{
+			Data:       []byte(m.value),
+			Properties: properties,
+		})
+	}
+	return result
 }
 
 func init() {
@@ -89,20 +188,39 @@ func init() {
 			return nil, fmt.Errorf("LYWSD03MMC: device %s has invalid mac address value: %w", config.Name, err)
 		}
 
+		firmware := strings.ToLower(config.Properties[firmwarePropertiesKey])
+		switch firmware {
+		case "":
+			firmware = firmwareAuto
+		case firmwareAuto, firmwareStock, firmwareAtc1441, firmwarePvvx:
+		default:
+			return nil, fmt.Errorf("LYWSD03MMC: device %s has invalid %s value: %s, expected one of auto, stock, atc1441 or pvvx", config.Name, firmwarePropertiesKey, firmware)
+		}
+
+		// the custom firmwares advertise unencrypted by default
+		var encryptionKey []byte
 		encryptionKeyValue, exists := config.Properties[encryptionKeyPropertiesKey]
-		if !exists {
+		if !exists && firmware == firmwareStock {
 			return nil, fmt.Errorf("LYWSD03MMC: device %s is missing %s property", config.Name, encryptionKeyPropertiesKey)
 		}
+		if exists {
+			encryptionKey, err = hex.DecodeString(encryptionKeyValue)
+			if err != nil {
+				return nil, fmt.Errorf("LYWSD03MMC: device %s has invalid encryption key value: %w", config.Name, err)
+			}
+		}
 
-		encryptionKey, err := hex.DecodeString(encryptionKeyValue)
+		deduplicator, err := device.NewDeduplicator(deviceType, config.Properties)
 		if err != nil {
-			return nil, fmt.Errorf("LYWSD03MMC: device %s has invalid encryption key value: %w", config.Name, err)
+			return nil, fmt.Errorf("LYWSD03MMC: device %s - %w", config.Name, err)
 		}
 
 		return &lywsd03mmc{
 			name:          config.Name,
 			macAddress:    macAddress.String(),
+			firmware:      firmware,
 			encryptionKey: encryptionKey,
+			deduplicator:  deduplicator,
 		}, nil
 	})
 	if err != nil {
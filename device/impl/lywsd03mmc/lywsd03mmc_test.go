@@ -0,0 +1,138 @@
+package lywsd03mmc
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		data       string
+		expected   []map[string]interface{}
+	}{
+		{
+			name:       "auto detected atc1441",
+			properties: map[string]string{"macAddress": "A4:C1:38:AB:CD:EF"},
+			data:       "a4c138abcdef00eb3c5a0b8c2a",
+			expected: []map[string]interface{}{
+				{"unit": "Temperature", "value": "23.50", "format": "atc1441", "frameCounter": "42"},
+				{"unit": "Humidity", "value": "60.00"},
+				{"unit": "Battery", "value": "90"},
+				{"unit": "Voltage", "value": "2.956"},
+			},
+		},
+		{
+			name:       "auto detected pvvx",
+			properties: map[string]string{"macAddress": "A4:C1:38:AB:CD:EF"},
+			data:       "efcdab38c1a42e094c138c0b5a2a05",
+			expected: []map[string]interface{}{
+				{"unit": "Temperature", "value": "23.50", "format": "pvvx"},
+				{"unit": "Humidity", "value": "49.40"},
+				{"unit": "Battery", "value": "90"},
+				{"unit": "Voltage", "value": "2.956"},
+			},
+		},
+		{
+			name:       "atc1441 of another sensor",
+			properties: map[string]string{"macAddress": "A4:C1:38:00:00:01", "firmware": "atc1441"},
+			data:       "a4c138abcdef00eb3c5a0b8c2a",
+		},
+		{
+			name:       "pvvx encrypted",
+			properties: map[string]string{"macAddress": "A4:C1:38:AB:CD:EF", "firmware": "pvvx", "encryptionKey": "b9ea895fac7eea6d30532432a516f3a3"},
+			data:       "2a504fe4ed89f7fdf45481",
+			expected: []map[string]interface{}{
+				{"unit": "Temperature", "value": "23.50", "format": "pvvx-encrypted"},
+				{"unit": "Humidity", "value": "49.40"},
+				{"unit": "Battery", "value": "90"},
+			},
+		},
+		{
+			name:       "auto detected atc1441 encrypted",
+			properties: map[string]string{"macAddress": "A4:C1:38:AB:CD:EF", "encryptionKey": "b9ea895fac7eea6d30532432a516f3a3"},
+			data:       "2ac5ceff45d4402e",
+			expected: []map[string]interface{}{
+				{"unit": "Temperature", "value": "23.50", "format": "atc1441-encrypted", "frameCounter": "42"},
+				{"unit": "Humidity", "value": "42.50"},
+				{"unit": "Battery", "value": "90"},
+			},
+		},
+		{
+			name:       "auto detected pvvx encrypted",
+			properties: map[string]string{"macAddress": "A4:C1:38:AB:CD:EF", "encryptionKey": "b9ea895fac7eea6d30532432a516f3a3"},
+			data:       "2a504fe4ed89f7fdf45481",
+			expected: []map[string]interface{}{
+				{"unit": "Temperature", "value": "23.50", "format": "pvvx-encrypted"},
+				{"unit": "Humidity", "value": "49.40"},
+				{"unit": "Battery", "value": "90"},
+			},
+		},
+		{
+			name:       "auto encrypted of another sensor",
+			properties: map[string]string{"macAddress": "A4:C1:38:00:00:01", "encryptionKey": "b9ea895fac7eea6d30532432a516f3a3"},
+			data:       "2a504fe4ed89f7fdf45481",
+		},
+		{
+			name:       "atc1441 encrypted without key",
+			properties: map[string]string{"macAddress": "A4:C1:38:AB:CD:EF", "firmware": "atc1441"},
+			data:       "2ac5ceff45d4402e",
+		},
+		{
+			name:       "stock mibeacon",
+			properties: map[string]string{"macAddress": "A4:C1:38:13:89:A3", "encryptionKey": "6badc40a09b9176765c76226f000d6cb"},
+			data:       "58585b051fa3891338c1a4f30a68073c000000f7058be5",
+			expected: []map[string]interface{}{
+				{"unit": "Humidity", "value": "30.80", "format": "stock", "frameCounter": "31"},
+			},
+		},
+		{
+			name:       "stock firmware ignores custom formats",
+			properties: map[string]string{"macAddress": "A4:C1:38:AB:CD:EF", "firmware": "stock", "encryptionKey": "6badc40a09b9176765c76226f000d6cb"},
+			data:       "efcdab38c1a42e094c138c0b5a2a05",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := device.NewDevice(context.Background(), &config.Device{
+				Name:       "thermometer",
+				Type:       deviceType,
+				Properties: tt.properties,
+			})
+			require.NoError(t, err)
+
+			data, err := hex.DecodeString(tt.data)
+			require.NoError(t, err)
+
+			result, err := dev.Decode(context.Background(), &device.Data{
+				Data:       data,
+				Properties: map[string]interface{}{},
+			})
+			require.NoError(t, err)
+			require.Len(t, result, len(tt.expected))
+			for i, expected := range tt.expected {
+				for k, v := range expected {
+					assert.Equal(t, v, result[i].Properties[k], k)
+				}
+				assert.Equal(t, expected["value"], string(result[i].Data))
+			}
+		})
+	}
+}
+
+func TestInvalidFirmware(t *testing.T) {
+	_, err := device.NewDevice(context.Background(), &config.Device{
+		Name:       "thermometer",
+		Type:       deviceType,
+		Properties: map[string]string{"macAddress": "A4:C1:38:AB:CD:EF", "firmware": "tasmota"},
+	})
+	assert.EqualError(t, err, "LYWSD03MMC: device thermometer has invalid firmware value: tasmota, expected one of auto, stock, atc1441 or pvvx")
+}
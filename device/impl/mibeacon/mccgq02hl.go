@@ -0,0 +1,18 @@
+package mibeacon
+
+import (
+	"context"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+// MCCGQ02HL door and window sensor, reports door state, light and battery, requires the encryptionKey property.
+func init() {
+	err := device.RegisterDevice("mccgq02hl", func(ctx context.Context, config *config.Device) (device.Device, error) {
+		return newMiBeaconDevice(config, 0x098b)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,18 @@
+package mibeacon
+
+import (
+	"context"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+// Mi Flora HHCCJCY01 plant sensor, reports temperature, illuminance, moisture, conductivity and battery.
+func init() {
+	err := device.RegisterDevice("mi-flora", func(ctx context.Context, config *config.Device) (device.Device, error) {
+		return newMiBeaconDevice(config, 0x0098)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,18 @@
+package mibeacon
+
+import (
+	"context"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+// Any MiBeacon device, e.g. the MJWSD05MMC thermometer, the RTCGQ02LM motion sensor or the YLKG07YL dimmer.
+func init() {
+	err := device.RegisterDevice("mibeacon", func(ctx context.Context, config *config.Device) (device.Device, error) {
+		return newMiBeaconDevice(config, 0)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,123 @@
+package mibeacon
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+	"github.com/nikiforov-soft/yasp/device/vendors/xiaomi"
+)
+
+const (
+	macAddressPropertiesKey    = "macAddress"
+	encryptionKeyPropertiesKey = "encryptionKey"
+)
+
+// miBeaconDevice decodes the MiBeacon frames of a single device into an event per measurement.
+type miBeaconDevice struct {
+	name          string
+	deviceType    string
+	macAddress    string
+	encryptionKey []byte
+	productId     uint16
+	deduplicator  *device.Deduplicator
+}
+
+// newMiBeaconDevice creates a device accepting the frames of productId, 0 accepts the frames of every product.
+func newMiBeaconDevice(config *config.Device, productId uint16) (device.Device, error) {
+	macAddressValue, exists := config.Properties[macAddressPropertiesKey]
+	if !exists {
+		return nil, fmt.Errorf("%s: device %s is missing %s property", config.Type, config.Name, macAddressPropertiesKey)
+	}
+
+	macAddress, err := net.ParseMAC(macAddressValue)
+	if err != nil {
+		return nil, fmt.Errorf("%s: device %s has invalid mac address value: %w", config.Type, config.Name, err)
+	}
+
+	var encryptionKey []byte
+	if encryptionKeyValue, exists := config.Properties[encryptionKeyPropertiesKey]; exists {
+		encryptionKey, err = hex.DecodeString(encryptionKeyValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: device %s has invalid encryption key value: %w", config.Type, config.Name, err)
+		}
+		if len(encryptionKey) != 16 {
+			return nil, fmt.Errorf("%s: device %s has invalid encryption key length: %d", config.Type, config.Name, len(encryptionKey))
+		}
+	}
+
+	deduplicator, err := device.NewDeduplicator(config.Type, config.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("%s: device %s - %w", config.Type, config.Name, err)
+	}
+
+	return &miBeaconDevice{
+		name:          config.Name,
+		deviceType:    config.Type,
+		macAddress:    macAddress.String(),
+		encryptionKey: encryptionKey,
+		productId:     productId,
+		deduplicator:  deduplicator,
+	}, nil
+}
+
+func (mbd *miBeaconDevice) Decode(_ context.Context, data *device.Data) ([]*device.Data, error) {
+	if macAddress, ok := device.MacAddress(data.Properties); ok && macAddress != mbd.macAddress {
+		return nil, nil
+	}
+
+	frame, err := xiaomi.ParseBLEFrame(data.Data, func(mac string) ([]byte, error) {
+		if !strings.EqualFold(mbd.macAddress, mac) {
+			return nil, nil
+		}
+		return mbd.encryptionKey, nil
+	})
+	if err != nil {
+		if errors.Is(err, xiaomi.ErrBindKeyRequired) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("%s: failed to parse frame: device name: %s, data: %s - %w", mbd.deviceType, mbd.name, hex.EncodeToString(data.Data), err)
+	}
+
+	if frame.MacAddress != "" && !strings.EqualFold(strings.ReplaceAll(mbd.macAddress, ":", ""), frame.MacAddress) {
+		return nil, nil
+	}
+	if mbd.productId != 0 && frame.ProductId != mbd.productId {
+		return nil, nil
+	}
+	if len(frame.Events) == 0 || mbd.deduplicator.IsDuplicate(mbd.macAddress, uint32(frame.FrameCounter)) {
+		return nil, nil
+	}
+
+	var result []*device.Data
+	for _, event := range frame.Events {
+		for _, measurement := range event.Measurements() {
+			value := measurement.FormattedValue()
+
+			properties := make(map[string]interface{}, len(data.Properties)+8)
+			for k, v := range data.Properties {
+				properties[k] = v
+			}
+			properties["deviceName"] = mbd.name
+			properties["deviceType"] = mbd.deviceType
+			properties["deviceMacAddress"] = mbd.macAddress
+			properties["unit"] = measurement.Type
+			properties["unitOfMeasurement"] = measurement.Unit
+			properties["productId"] = strconv.Itoa(int(frame.ProductId))
+			properties["objectId"] = strconv.Itoa(int(event.EventType()))
+			properties["value"] = value
+
+			result = append(result, &device.Data{
+				Data:       []byte(value),
+				Properties: properties,
+			})
+		}
+	}
+	return result, nil
+}
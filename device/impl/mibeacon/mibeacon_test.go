@@ -0,0 +1,98 @@
+package mibeacon
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name       string
+		deviceType string
+		macAddress string
+		data       string
+		expected   []map[string]interface{}
+	}{
+		{
+			name:       "mi flora",
+			deviceType: "mi-flora",
+			macAddress: "C4:7C:8D:6D:E6:8F",
+			data:       "71209800128fe66d8d7cc40d041002e2000710036400000810012b091002f4019999010a0a10015f",
+			expected: []map[string]interface{}{
+				{"unit": "Temperature", "unitOfMeasurement": "°C", "objectId": "4100", "value": "22.6"},
+				{"unit": "Illuminance", "unitOfMeasurement": "lx", "objectId": "4103", "value": "100"},
+				{"unit": "Moisture", "unitOfMeasurement": "%", "objectId": "4104", "value": "43"},
+				{"unit": "Conductivity", "unitOfMeasurement": "µS/cm", "objectId": "4105", "value": "500"},
+				{"unit": "Battery", "unitOfMeasurement": "%", "objectId": "4106", "value": "95"},
+			},
+		},
+		{
+			name:       "door sensor",
+			deviceType: "mccgq02hl",
+			macAddress: "a4:c1:3b:ab:cd:ef",
+			data:       "50508b0901efcdab3bc1a419100100181001010a10015a",
+			expected: []map[string]interface{}{
+				{"unit": "DoorState", "value": "open"},
+				{"unit": "Door", "value": "1"},
+				{"unit": "Light", "value": "1"},
+				{"unit": "Battery", "value": "90"},
+			},
+		},
+		{
+			name:       "dimmer",
+			deviceType: "mibeacon",
+			macAddress: "a4:c1:3b:ab:cd:ef",
+			data:       "5050b60303efcdab3bc1a401100300fd04",
+			expected: []map[string]interface{}{
+				{"unit": "Button", "productId": "950", "value": "rotate"},
+				{"unit": "Rotation", "value": "-3"},
+			},
+		},
+		{
+			name:       "other product",
+			deviceType: "mi-flora",
+			macAddress: "a4:c1:3b:ab:cd:ef",
+			data:       "50508b0901efcdab3bc1a419100100181001010a10015a",
+		},
+		{
+			name:       "other mac address",
+			deviceType: "mibeacon",
+			macAddress: "a4:c1:3b:00:00:01",
+			data:       "50508b0901efcdab3bc1a419100100181001010a10015a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := device.NewDevice(context.Background(), &config.Device{
+				Name:       "xiaomi",
+				Type:       tt.deviceType,
+				Properties: map[string]string{"macAddress": tt.macAddress},
+			})
+			require.NoError(t, err)
+
+			data, err := hex.DecodeString(tt.data)
+			require.NoError(t, err)
+
+			result, err := dev.Decode(context.Background(), &device.Data{
+				Data:       data,
+				Properties: map[string]interface{}{},
+			})
+			require.NoError(t, err)
+			require.Len(t, result, len(tt.expected))
+			for i, expected := range tt.expected {
+				for k, v := range expected {
+					assert.Equal(t, v, result[i].Properties[k], k)
+				}
+				assert.Equal(t, expected["value"], string(result[i].Data))
+				assert.Equal(t, tt.deviceType, result[i].Properties["deviceType"])
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package p1p2
+
+type DaikinDirection byte
+
+const (
+	DaikinDirectionUnknown                 DaikinDirection = 0xFF
+	DaikinDirectionControllerToHeatPump    DaikinDirection = 0x00
+	DaikinDirectionHeatPumpToController    DaikinDirection = 0x40
+	DaikinDirectionAuxControllerToHeatPump DaikinDirection = 0x80
+)
+
+func (dd DaikinDirection) String() string {
+	switch dd {
+	case DaikinDirectionControllerToHeatPump, DaikinDirectionAuxControllerToHeatPump:
+		return "request"
+	case DaikinDirectionHeatPumpToController:
+		return "response"
+	default:
+		return "unknown"
+	}
+}
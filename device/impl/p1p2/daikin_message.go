@@ -0,0 +1,200 @@
+package p1p2
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	daikinHeaderLength   = 3
+	daikinCrcPolynomial  = 0xD9
+	daikinMinPacketType  = 0x10
+	daikinMaxPacketType  = 0x16
+	daikinPayloadOffset  = daikinHeaderLength
+	daikinChecksumLength = 1
+)
+
+type daikinFieldKind byte
+
+const (
+	daikinFieldBit daikinFieldKind = iota
+	daikinFieldUint8
+	daikinFieldFloat88
+	daikinFieldUint16Div10
+	daikinFieldMode
+)
+
+// daikinField describes a value of a packet payload, offsets are relative to the payload following the header.
+type daikinField struct {
+	name   string
+	kind   daikinFieldKind
+	offset int
+	bit    byte
+}
+
+type daikinPacketKey struct {
+	direction  DaikinDirection
+	packetType byte
+}
+
+// daikinFields - the payload values of the Altherma LT heat pumps (EHYHB, EHBH/X, EHVH/X)
+// https://github.com/Arnold-n/P1P2Serial/blob/main/doc/Daikin-protocol-Altherma.md
+var daikinFields = map[daikinPacketKey][]daikinField{
+	{DaikinDirectionControllerToHeatPump, 0x10}: {
+		{name: "heatingPower", kind: daikinFieldBit, offset: 0, bit: 0},
+		{name: "operatingMode", kind: daikinFieldMode, offset: 1},
+		{name: "dhwPower", kind: daikinFieldBit, offset: 2, bit: 0},
+		{name: "targetRoomTemperature", kind: daikinFieldUint8, offset: 6},
+	},
+	{DaikinDirectionHeatPumpToController, 0x10}: {
+		{name: "heatingPower", kind: daikinFieldBit, offset: 0, bit: 0},
+		{name: "operatingMode", kind: daikinFieldMode, offset: 1},
+		{name: "dhwPower", kind: daikinFieldBit, offset: 2, bit: 0},
+		{name: "targetRoomTemperature", kind: daikinFieldUint8, offset: 6},
+		{name: "quietMode", kind: daikinFieldBit, offset: 9, bit: 2},
+		{name: "dhwActive", kind: daikinFieldBit, offset: 12, bit: 6},
+	},
+	{DaikinDirectionControllerToHeatPump, 0x11}: {
+		{name: "roomTemperature", kind: daikinFieldFloat88, offset: 0},
+	},
+	{DaikinDirectionHeatPumpToController, 0x11}: {
+		{name: "leavingWaterTemperature", kind: daikinFieldFloat88, offset: 0},
+		{name: "dhwTemperature", kind: daikinFieldFloat88, offset: 2},
+		{name: "outsideTemperature", kind: daikinFieldFloat88, offset: 4},
+		{name: "returnWaterTemperature", kind: daikinFieldFloat88, offset: 6},
+		{name: "leavingWaterHeatExchangerTemperature", kind: daikinFieldFloat88, offset: 8},
+		{name: "refrigerantTemperature", kind: daikinFieldFloat88, offset: 10},
+		{name: "roomTemperature", kind: daikinFieldFloat88, offset: 12},
+	},
+	{DaikinDirectionHeatPumpToController, 0x12}: {
+		{name: "heatPumpRunning", kind: daikinFieldBit, offset: 11, bit: 0},
+		{name: "compressorRunning", kind: daikinFieldBit, offset: 11, bit: 1},
+		{name: "circulationPumpRunning", kind: daikinFieldBit, offset: 11, bit: 3},
+		{name: "dhwRunning", kind: daikinFieldBit, offset: 11, bit: 5},
+	},
+	{DaikinDirectionHeatPumpToController, 0x13}: {
+		{name: "targetDhwTemperature", kind: daikinFieldUint8, offset: 0},
+		{name: "flow", kind: daikinFieldUint16Div10, offset: 9},
+	},
+	{DaikinDirectionControllerToHeatPump, 0x14}: {
+		{name: "targetLeavingWaterHeatingTemperature", kind: daikinFieldFloat88, offset: 0},
+		{name: "targetLeavingWaterCoolingTemperature", kind: daikinFieldFloat88, offset: 2},
+	},
+	{DaikinDirectionHeatPumpToController, 0x14}: {
+		{name: "targetLeavingWaterHeatingTemperature", kind: daikinFieldFloat88, offset: 0},
+		{name: "targetLeavingWaterCoolingTemperature", kind: daikinFieldFloat88, offset: 2},
+	},
+}
+
+type daikinMessage []byte
+
+func (dm daikinMessage) direction() DaikinDirection {
+	direction := DaikinDirection(dm[0])
+	switch direction {
+	case DaikinDirectionControllerToHeatPump,
+		DaikinDirectionHeatPumpToController,
+		DaikinDirectionAuxControllerToHeatPump:
+		return direction
+	default:
+		return DaikinDirectionUnknown
+	}
+}
+
+func (dm daikinMessage) packetType() byte {
+	return dm[2]
+}
+
+func (dm daikinMessage) payload() []byte {
+	return dm[daikinPayloadOffset : len(dm)-daikinChecksumLength]
+}
+
+func (dm daikinMessage) values() []DaikinValue {
+	direction := dm.direction()
+	if direction == DaikinDirectionAuxControllerToHeatPump {
+		direction = DaikinDirectionControllerToHeatPump
+	}
+
+	payload := dm.payload()
+	var values []DaikinValue
+	for _, field := range daikinFields[daikinPacketKey{direction: direction, packetType: dm.packetType()}] {
+		values = append(values, field.decode(payload)...)
+	}
+	return values
+}
+
+// decode returns the value of the field, fields beyond the payload of shorter packets of other models are skipped.
+func (df daikinField) decode(payload []byte) []DaikinValue {
+	switch df.kind {
+	case daikinFieldBit:
+		if df.offset >= len(payload) {
+			return nil
+		}
+		value := (payload[df.offset] & (1 << df.bit)) != 0
+		return []DaikinValue{{Name: df.name, Value: strconv.FormatBool(value)}}
+	case daikinFieldUint8:
+		if df.offset >= len(payload) {
+			return nil
+		}
+		return []DaikinValue{{Name: df.name, Value: strconv.Itoa(int(payload[df.offset]))}}
+	case daikinFieldMode:
+		if df.offset >= len(payload) {
+			return nil
+		}
+		mode := daikinModeOf(payload[df.offset])
+		return []DaikinValue{
+			{Name: df.name, Value: mode.String()},
+			{Name: df.name + "Id", Value: strconv.Itoa(mode.Id())},
+		}
+	case daikinFieldFloat88:
+		if df.offset+1 >= len(payload) {
+			return nil
+		}
+		value := float64(int8(payload[df.offset])) + float64(payload[df.offset+1])/256.0
+		return []DaikinValue{{Name: df.name, Value: strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)}}
+	case daikinFieldUint16Div10:
+		if df.offset+1 >= len(payload) {
+			return nil
+		}
+		value := float64(uint16(payload[df.offset])<<8|uint16(payload[df.offset+1])) / 10.0
+		return []DaikinValue{{Name: df.name, Value: strconv.FormatFloat(value, 'f', -1, 64)}}
+	default:
+		return nil
+	}
+}
+
+func (dm daikinMessage) validate() error {
+	if len(dm) < daikinHeaderLength+daikinChecksumLength {
+		return fmt.Errorf("invalid daikin packet length: %d", len(dm))
+	}
+
+	direction := dm.direction()
+	if direction == DaikinDirectionUnknown {
+		return fmt.Errorf("%w: %d", ErrUnknownDirection, dm[0])
+	}
+
+	if packetType := dm.packetType(); packetType < daikinMinPacketType || packetType > daikinMaxPacketType {
+		return fmt.Errorf("%w: 0x%02X", ErrUnknownPacketType, packetType)
+	}
+
+	if daikinChecksum(dm[:len(dm)-1]) != dm[len(dm)-1] {
+		return ErrInvalidChecksum
+	}
+	return nil
+}
+
+// daikinChecksum - crc8 of the packet with the 0xD9 polynomial, least significant bit first
+func daikinChecksum(data []byte) byte {
+	var crc byte
+	for _, b := range data {
+		for i := 0; i < 8; i++ {
+			if ((crc ^ b) & 0x01) != 0 {
+				crc = (crc >> 1) ^ daikinCrcPolynomial
+			} else {
+				crc >>= 1
+			}
+			b >>= 1
+		}
+	}
+	return crc
+}
@@ -0,0 +1,40 @@
+package p1p2
+
+type DaikinMode string
+
+func (dm DaikinMode) String() string {
+	return string(dm)
+}
+
+func (dm DaikinMode) Id() int {
+	switch dm {
+	case DaikinModeHeating:
+		return 2
+	case DaikinModeCooling:
+		return 1
+	case DaikinModeAuto:
+		return 3
+	default:
+		return -1
+	}
+}
+
+func daikinModeOf(value byte) DaikinMode {
+	switch value & 0x03 {
+	case 0x01:
+		return DaikinModeHeating
+	case 0x02:
+		return DaikinModeCooling
+	case 0x03:
+		return DaikinModeAuto
+	default:
+		return DaikinModeUnknown
+	}
+}
+
+const (
+	DaikinModeUnknown DaikinMode = ""
+	DaikinModeCooling DaikinMode = "Cooling"
+	DaikinModeHeating DaikinMode = "Heating"
+	DaikinModeAuto    DaikinMode = "Auto"
+)
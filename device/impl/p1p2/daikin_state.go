@@ -0,0 +1,12 @@
+package p1p2
+
+type DaikinValue struct {
+	Name  string
+	Value string
+}
+
+type DaikinState struct {
+	Direction  DaikinDirection
+	PacketType byte
+	Values     []DaikinValue
+}
@@ -5,6 +5,8 @@ import (
 )
 
 var (
-	ErrUnknownDirection = errors.New("unknown direction")
-	ErrInvalidChecksum  = errors.New("invalid checksum")
+	ErrUnknownDirection  = errors.New("unknown direction")
+	ErrInvalidChecksum   = errors.New("invalid checksum")
+	ErrInvalidLength     = errors.New("invalid message length")
+	ErrUnknownPacketType = errors.New("unknown packet type")
 )
@@ -0,0 +1,164 @@
+package p1p2
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+const (
+	hitachiMinTemperature = 16
+	hitachiMaxTemperature = 32
+)
+
+// HitachiCommand is the requested state of an indoor unit, nil fields keep their current value.
+type HitachiCommand struct {
+	UnitAddress *byte
+	Running     *bool
+	Mode        HitachiMode
+	FanSpeed    HitachiFanSpeed
+	Temperature *int
+}
+
+// ParseHitachiCommand reads the command keys named after the decoded properties: unitAddress, status, mode, fanSpeed and temperature.
+func ParseHitachiCommand(command device.Command) (HitachiCommand, error) {
+	var hc HitachiCommand
+	for key, value := range command {
+		switch key {
+		case "unitAddress":
+			unitAddress, err := commandInt(value)
+			if err != nil || unitAddress < 0 || unitAddress > 0xFF {
+				return HitachiCommand{}, fmt.Errorf("invalid %s: %v", key, value)
+			}
+			address := byte(unitAddress)
+			hc.UnitAddress = &address
+		case "status":
+			running, err := commandBool(value)
+			if err != nil {
+				return HitachiCommand{}, fmt.Errorf("invalid %s: %v", key, value)
+			}
+			hc.Running = &running
+		case "mode":
+			hc.Mode = HitachiModeUnknown
+			for mode := range hitachiModeBits {
+				if strings.EqualFold(fmt.Sprint(value), mode.String()) {
+					hc.Mode = mode
+				}
+			}
+			if hc.Mode == HitachiModeUnknown {
+				return HitachiCommand{}, fmt.Errorf("invalid %s: %v", key, value)
+			}
+		case "fanSpeed":
+			hc.FanSpeed = HitachiFanSpeedUnknown
+			for fanSpeed := range hitachiFanSpeedBits {
+				if strings.EqualFold(fmt.Sprint(value), fanSpeed.String()) {
+					hc.FanSpeed = fanSpeed
+				}
+			}
+			if hc.FanSpeed == HitachiFanSpeedUnknown {
+				return HitachiCommand{}, fmt.Errorf("invalid %s: %v", key, value)
+			}
+		case "temperature":
+			temperature, err := commandInt(value)
+			if err != nil || temperature < hitachiMinTemperature || temperature > hitachiMaxTemperature {
+				return HitachiCommand{}, fmt.Errorf("invalid %s: %v, expected %d-%d", key, value, hitachiMinTemperature, hitachiMaxTemperature)
+			}
+			hc.Temperature = &temperature
+		default:
+			return HitachiCommand{}, fmt.Errorf("unsupported command key: %s", key)
+		}
+	}
+	if hc.Running == nil && hc.Mode == HitachiModeUnknown && hc.FanSpeed == HitachiFanSpeedUnknown && hc.Temperature == nil {
+		return HitachiCommand{}, fmt.Errorf("command does not change status, mode, fanSpeed or temperature")
+	}
+	return hc, nil
+}
+
+// EncodeHitachiCommand applies the command to a copy of the last message the controller sent to the unit.
+func EncodeHitachiCommand(controllerMessage []byte, command HitachiCommand) ([]byte, error) {
+	msg := hitachiMessage(controllerMessage)
+	if err := msg.validate(); err != nil {
+		return nil, err
+	}
+	if msg.direction() != DirectionControllerToUnits {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownDirection, msg.direction())
+	}
+
+	msg = hitachiMessage(append([]byte(nil), controllerMessage...))
+	if command.Running != nil {
+		msg.setRunning(*command.Running)
+	}
+	if command.Mode != HitachiModeUnknown {
+		if err := msg.setMode(command.Mode); err != nil {
+			return nil, err
+		}
+	}
+	if command.FanSpeed != HitachiFanSpeedUnknown {
+		if err := msg.setFanSpeed(command.FanSpeed); err != nil {
+			return nil, err
+		}
+	}
+	if command.Temperature != nil {
+		msg.setTemperature(*command.Temperature)
+	}
+	msg.updateChecksum()
+	return msg, nil
+}
+
+// Matches reports whether the state reported by the unit reflects the command.
+func (hc HitachiCommand) Matches(state HitachiState) bool {
+	if state.Direction != DirectionUnitsToController {
+		return false
+	}
+	if hc.UnitAddress != nil && *hc.UnitAddress != state.UnitAddress {
+		return false
+	}
+	if hc.Running != nil && *hc.Running != state.Running {
+		return false
+	}
+	if hc.Mode != HitachiModeUnknown && hc.Mode != state.Mode {
+		return false
+	}
+	if hc.FanSpeed != HitachiFanSpeedUnknown && hc.FanSpeed != state.FanMode {
+		return false
+	}
+	if hc.Temperature != nil && *hc.Temperature != state.Temperature {
+		return false
+	}
+	return true
+}
+
+func commandInt(value interface{}) (int, error) {
+	switch value := value.(type) {
+	case float64:
+		if value != float64(int(value)) {
+			return 0, fmt.Errorf("not an integer: %v", value)
+		}
+		return int(value), nil
+	case int:
+		return value, nil
+	case string:
+		return strconv.Atoi(value)
+	default:
+		return 0, fmt.Errorf("not a number: %v", value)
+	}
+}
+
+func commandBool(value interface{}) (bool, error) {
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case string:
+		switch strings.ToLower(value) {
+		case "on":
+			return true, nil
+		case "off":
+			return false, nil
+		}
+		return strconv.ParseBool(value)
+	default:
+		return false, fmt.Errorf("not a boolean: %v", value)
+	}
+}
@@ -7,3 +7,14 @@ const (
 	DirectionControllerToUnits HitachiDirection = 0x21
 	DirectionUnitsToController HitachiDirection = 0x89
 )
+
+func (hd HitachiDirection) String() string {
+	switch hd {
+	case DirectionControllerToUnits:
+		return "request"
+	case DirectionUnitsToController:
+		return "response"
+	default:
+		return "unknown"
+	}
+}
@@ -14,6 +14,10 @@ func (hfs HitachiFanSpeed) Id() int {
 		return 2
 	case HitachiFanSpeedHigh:
 		return 3
+	case HitachiFanSpeedSilent:
+		return 4
+	case HitachiFanSpeedAuto:
+		return 5
 	default:
 		return -1
 	}
@@ -24,4 +28,6 @@ const (
 	HitachiFanSpeedLow     HitachiFanSpeed = "Low"
 	HitachiFanSpeedMedium  HitachiFanSpeed = "Medium"
 	HitachiFanSpeedHigh    HitachiFanSpeed = "High"
+	HitachiFanSpeedSilent  HitachiFanSpeed = "Silent"
+	HitachiFanSpeedAuto    HitachiFanSpeed = "Auto"
 )
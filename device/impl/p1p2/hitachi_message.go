@@ -9,15 +9,51 @@ import (
 const (
 	RunningBit     = 1 << 0
 	ModeCoolingBit = 1 << 3
+	ModeDryBit     = 1 << 4
+	ModeFanBit     = 1 << 5
 	ModeHeatingBit = 1 << 6
+	ModeAutoBit    = 1 << 7
 )
 
 const (
-	FanSpeedLowBit    = 1 << 3
-	FanSpeedMediumBit = 1 << 2
+	FanSpeedAutoBit   = 1 << 0
 	FanSpeedHighBit   = 1 << 1
+	FanSpeedMediumBit = 1 << 2
+	FanSpeedLowBit    = 1 << 3
+	FanSpeedSilentBit = 1 << 4
+	LouverMask        = 0x60
+	LouverShift       = 5
+	SwingBit          = 1 << 7
 )
 
+const (
+	FilterSignBit = 1 << 0
+	DefrostBit    = 1 << 1
+)
+
+const (
+	// controllerToUnitsMinLength covers the fields up to the test mode flag of the controller messages.
+	controllerToUnitsMinLength = 0x0F
+	// unitsToControllerMinLength covers the fields up to the test mode flag of the unit messages.
+	unitsToControllerMinLength = 0x2C
+)
+
+var hitachiModeBits = map[HitachiMode]byte{
+	HitachiModeCooling: ModeCoolingBit,
+	HitachiModeDry:     ModeDryBit,
+	HitachiModeFan:     ModeFanBit,
+	HitachiModeHeating: ModeHeatingBit,
+	HitachiModeAuto:    ModeAutoBit,
+}
+
+var hitachiFanSpeedBits = map[HitachiFanSpeed]byte{
+	HitachiFanSpeedAuto:   FanSpeedAutoBit,
+	HitachiFanSpeedHigh:   FanSpeedHighBit,
+	HitachiFanSpeedMedium: FanSpeedMediumBit,
+	HitachiFanSpeedLow:    FanSpeedLowBit,
+	HitachiFanSpeedSilent: FanSpeedSilentBit,
+}
+
 type hitachiMessage []byte
 
 func (hm hitachiMessage) running() bool {
@@ -33,6 +69,15 @@ func (hm hitachiMessage) mode() HitachiMode {
 	if (operationMode & ModeHeatingBit) == ModeHeatingBit {
 		return HitachiModeHeating
 	}
+	if (operationMode & ModeDryBit) == ModeDryBit {
+		return HitachiModeDry
+	}
+	if (operationMode & ModeFanBit) == ModeFanBit {
+		return HitachiModeFan
+	}
+	if (operationMode & ModeAutoBit) == ModeAutoBit {
+		return HitachiModeAuto
+	}
 	logrus.WithField("operationMode", operationMode).Error("unknown operation mode")
 	return HitachiModeUnknown
 }
@@ -48,14 +93,69 @@ func (hm hitachiMessage) fanSpeed() HitachiFanSpeed {
 	if (fanSpeed & FanSpeedHighBit) == FanSpeedHighBit {
 		return HitachiFanSpeedHigh
 	}
+	if (fanSpeed & FanSpeedSilentBit) == FanSpeedSilentBit {
+		return HitachiFanSpeedSilent
+	}
+	if (fanSpeed & FanSpeedAutoBit) == FanSpeedAutoBit {
+		return HitachiFanSpeedAuto
+	}
 	logrus.WithField("fanSpeedMode", fanSpeed).Error("unknown fan speed mode")
 	return HitachiFanSpeedUnknown
 }
 
+func (hm hitachiMessage) louverPosition() int {
+	return int(hm[0x0B]&LouverMask) >> LouverShift
+}
+
+func (hm hitachiMessage) swing() bool {
+	return (hm[0x0B] & SwingBit) == SwingBit
+}
+
 func (hm hitachiMessage) temperature() int {
 	return int(hm[0x0C])
 }
 
+func (hm hitachiMessage) unitAddress() byte {
+	return hm[0x03]
+}
+
+func (hm hitachiMessage) refrigerantCycle() byte {
+	return hm[0x04]
+}
+
+// hasUnitStatus reports whether the message carries the measured temperatures and the status flags of the unit.
+func (hm hitachiMessage) hasUnitStatus() bool {
+	return hm.direction() == DirectionUnitsToController
+}
+
+func (hm hitachiMessage) roomTemperature() int {
+	if !hm.hasUnitStatus() {
+		return 0
+	}
+	return int(hm[0x10])
+}
+
+func (hm hitachiMessage) outdoorTemperature() int {
+	if !hm.hasUnitStatus() {
+		return 0
+	}
+	return int(int8(hm[0x22]))
+}
+
+func (hm hitachiMessage) filterSign() bool {
+	if !hm.hasUnitStatus() {
+		return false
+	}
+	return (hm[0x0D] & FilterSignBit) == FilterSignBit
+}
+
+func (hm hitachiMessage) defrost() bool {
+	if !hm.hasUnitStatus() {
+		return false
+	}
+	return (hm[0x0D] & DefrostBit) == DefrostBit
+}
+
 func (hm hitachiMessage) direction() HitachiDirection {
 	direction := HitachiDirection(hm[0])
 	switch direction {
@@ -101,6 +201,14 @@ func (hm hitachiMessage) validate() error {
 		return fmt.Errorf("%w: %d", ErrUnknownDirection, direction)
 	}
 
+	minLength := controllerToUnitsMinLength
+	if direction == DirectionUnitsToController {
+		minLength = unitsToControllerMinLength
+	}
+	if len(hm) < minLength {
+		return fmt.Errorf("%w: %d", ErrInvalidLength, len(hm))
+	}
+
 	if !hm.validateChecksum() {
 		return ErrInvalidChecksum
 	}
@@ -108,10 +216,49 @@ func (hm hitachiMessage) validate() error {
 	return nil
 }
 
-func (hm hitachiMessage) validateChecksum() bool {
+func (hm hitachiMessage) checksum() byte {
 	var checksum byte
 	for _, b := range hm[1 : len(hm)-1] {
 		checksum ^= b
 	}
-	return checksum == hm[len(hm)-1]
+	return checksum
+}
+
+func (hm hitachiMessage) validateChecksum() bool {
+	return hm.checksum() == hm[len(hm)-1]
+}
+
+// updateChecksum recalculates the checksum after the message was modified.
+func (hm hitachiMessage) updateChecksum() {
+	hm[len(hm)-1] = hm.checksum()
+}
+
+func (hm hitachiMessage) setRunning(running bool) {
+	if running {
+		hm[0x0A] |= RunningBit
+	} else {
+		hm[0x0A] &^= RunningBit
+	}
+}
+
+func (hm hitachiMessage) setMode(mode HitachiMode) error {
+	bit, ok := hitachiModeBits[mode]
+	if !ok {
+		return fmt.Errorf("unsupported mode: %s", mode)
+	}
+	hm[0x0A] = hm[0x0A]&^(ModeCoolingBit|ModeDryBit|ModeFanBit|ModeHeatingBit|ModeAutoBit) | bit
+	return nil
+}
+
+func (hm hitachiMessage) setFanSpeed(fanSpeed HitachiFanSpeed) error {
+	bit, ok := hitachiFanSpeedBits[fanSpeed]
+	if !ok {
+		return fmt.Errorf("unsupported fan speed: %s", fanSpeed)
+	}
+	hm[0x0B] = hm[0x0B]&^(FanSpeedAutoBit|FanSpeedHighBit|FanSpeedMediumBit|FanSpeedLowBit|FanSpeedSilentBit) | bit
+	return nil
+}
+
+func (hm hitachiMessage) setTemperature(temperature int) {
+	hm[0x0C] = byte(temperature)
 }
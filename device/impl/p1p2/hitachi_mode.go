@@ -12,6 +12,12 @@ func (hm HitachiMode) Id() int {
 		return 1
 	case HitachiModeHeating:
 		return 2
+	case HitachiModeDry:
+		return 3
+	case HitachiModeFan:
+		return 4
+	case HitachiModeAuto:
+		return 5
 	default:
 		return -1
 	}
@@ -21,4 +27,7 @@ const (
 	HitachiModeUnknown HitachiMode = ""
 	HitachiModeCooling HitachiMode = "Cooling"
 	HitachiModeHeating HitachiMode = "Heating"
+	HitachiModeDry     HitachiMode = "Dry"
+	HitachiModeFan     HitachiMode = "Fan"
+	HitachiModeAuto    HitachiMode = "Auto"
 )
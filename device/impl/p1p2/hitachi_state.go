@@ -1,11 +1,22 @@
 package p1p2
 
 type HitachiState struct {
-	Direction   HitachiDirection
-	Running     bool
-	Mode        HitachiMode
-	FanMode     HitachiFanSpeed
-	Temperature int
-	TestMode    bool
-	ErrorCode   byte
+	Direction HitachiDirection
+	// UnitAddress and RefrigerantCycle identify the indoor unit of systems with several units on one bus.
+	UnitAddress      byte
+	RefrigerantCycle byte
+	Running          bool
+	Mode             HitachiMode
+	FanMode          HitachiFanSpeed
+	LouverPosition   int
+	Swing            bool
+	Temperature      int
+	// RoomTemperature, OutdoorTemperature, FilterSign and Defrost are only reported by the units.
+	HasUnitStatus      bool
+	RoomTemperature    int
+	OutdoorTemperature int
+	FilterSign         bool
+	Defrost            bool
+	TestMode           bool
+	ErrorCode          byte
 }
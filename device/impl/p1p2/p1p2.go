@@ -2,28 +2,44 @@ package p1p2
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/nikiforov-soft/yasp/config"
 	"github.com/nikiforov-soft/yasp/device"
 )
 
-const allowedPrefixesKey = "allowedPrefixes"
+const (
+	allowedPrefixesKey = "allowedPrefixes"
+	vendorKey          = "vendor"
+	writeTopicKey      = "writeTopic"
+)
+
+const (
+	vendorHitachi = "hitachi"
+	vendorDaikin  = "daikin"
+)
 
 var p1p2MessagePattern = regexp.MustCompile("^[a-zA-Z] [0-9]{4}-[0-9]{2}-[0-9]{2} [0-9]{2}:[0-9]{2}:[0-9]{2} [a-zA-Z] +([0-9.]+:)? ([a-fA-F0-9]+)$")
 
 type p1p2 struct {
 	config *config.Device
+	vendor string
+	// controllerMessages holds the last message the controller sent to each unit, commands are encoded on top of it.
+	controllerMessages     map[byte][]byte
+	controllerMessagesLock sync.Mutex
 }
 
-func (p *p1p2) Decode(_ context.Context, data *device.Data) (*device.Data, error) {
+func (p *p1p2) Decode(_ context.Context, data *device.Data) ([]*device.Data, error) {
 	inputTopic, ok := data.Properties["inputTopic"].(string)
 	if !ok {
-		return data, nil
+		return []*device.Data{data}, nil
 	}
 	inputTopic = strings.TrimSpace(inputTopic)
 
@@ -42,11 +58,11 @@ func (p *p1p2) Decode(_ context.Context, data *device.Data) (*device.Data, error
 
 	lastSlashIndex := strings.LastIndex(inputTopic, "/")
 	if lastSlashIndex == -1 {
-		return data, nil
+		return []*device.Data{data}, nil
 	}
 
 	if len(inputTopic) < (lastSlashIndex + 1) {
-		return data, nil
+		return []*device.Data{data}, nil
 	}
 
 	properties := make(map[string]interface{}, len(data.Properties))
@@ -71,6 +87,10 @@ func (p *p1p2) Decode(_ context.Context, data *device.Data) (*device.Data, error
 		return nil, nil
 	}
 
+	if p.vendor == vendorDaikin {
+		return p.decodeDaikin(data, properties, stringPayload, hexString)
+	}
+
 	msg, err := ParseHitachiMessage(hexString)
 	if err != nil {
 		if errors.Is(err, ErrUnknownDirection) {
@@ -79,25 +99,173 @@ func (p *p1p2) Decode(_ context.Context, data *device.Data) (*device.Data, error
 		return nil, fmt.Errorf("failed to parse p1p2 packet: %s - %w", stringPayload, err)
 	}
 
+	if msg.Direction == DirectionControllerToUnits {
+		p.storeControllerMessage(msg.UnitAddress, hexString)
+	}
+
+	properties["vendor"] = vendorHitachi
+	properties["direction"] = msg.Direction.String()
+	properties["unitAddress"] = strconv.Itoa(int(msg.UnitAddress))
+	properties["refrigerantCycle"] = strconv.Itoa(int(msg.RefrigerantCycle))
 	properties["temperature"] = strconv.Itoa(msg.Temperature)
 	properties["mode"] = msg.Mode.String()
 	properties["modeId"] = msg.Mode.Id()
 	properties["fanSpeed"] = msg.FanMode.String()
 	properties["fanSpeedId"] = msg.FanMode.Id()
+	properties["louverPosition"] = strconv.Itoa(msg.LouverPosition)
+	properties["swing"] = strconv.FormatBool(msg.Swing)
 	properties["status"] = strconv.FormatBool(msg.Running)
+	if msg.HasUnitStatus {
+		properties["roomTemperature"] = strconv.Itoa(msg.RoomTemperature)
+		properties["outdoorTemperature"] = strconv.Itoa(msg.OutdoorTemperature)
+		properties["filterSign"] = strconv.FormatBool(msg.FilterSign)
+		properties["defrost"] = strconv.FormatBool(msg.Defrost)
+	}
 	properties["testMode"] = strconv.FormatBool(msg.TestMode)
 	properties["errorCode"] = strconv.Itoa(int(msg.ErrorCode))
 
-	return &device.Data{
-		Data:       data.Data,
-		Properties: properties,
+	return []*device.Data{
+		{
+			Data:       data.Data,
+			Properties: properties,
+		},
+	}, nil
+}
+
+// decodeDaikin sets a property per value of the packet, packets without known values are dropped.
+func (p *p1p2) decodeDaikin(data *device.Data, properties map[string]interface{}, stringPayload, hexString string) ([]*device.Data, error) {
+	state, err := ParseDaikinMessage(hexString)
+	if err != nil {
+		if errors.Is(err, ErrUnknownDirection) || errors.Is(err, ErrUnknownPacketType) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to parse p1p2 packet: %s - %w", stringPayload, err)
+	}
+	if len(state.Values) == 0 {
+		return nil, nil
+	}
+
+	properties["vendor"] = vendorDaikin
+	properties["direction"] = state.Direction.String()
+	properties["packetType"] = fmt.Sprintf("0x%02X", state.PacketType)
+	for _, value := range state.Values {
+		properties[value.Name] = value.Value
+	}
+
+	return []*device.Data{
+		{
+			Data:       data.Data,
+			Properties: properties,
+		},
+	}, nil
+}
+
+// Encode turns the command into a hitachi message written by the P1P2MQTT bridge listening on the writeTopic property.
+// The command may omit the unitAddress as long as the controller addressed a single unit, the returned command is
+// bound to the addressed unit.
+func (p *p1p2) Encode(_ context.Context, command device.Command) (device.Command, []*device.Data, error) {
+	if p.vendor != vendorHitachi {
+		return nil, nil, fmt.Errorf("p1p2: commands are not supported for vendor: %s", p.vendor)
+	}
+	writeTopic := p.config.Properties[writeTopicKey]
+	if writeTopic == "" {
+		return nil, nil, fmt.Errorf("p1p2: device %s has no %s property", p.config.Name, writeTopicKey)
+	}
+
+	hitachiCommand, err := ParseHitachiCommand(command)
+	if err != nil {
+		return nil, nil, fmt.Errorf("p1p2: invalid command: %w", err)
+	}
+
+	unitAddress, controllerMessage, err := p.controllerMessage(hitachiCommand.UnitAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	hitachiCommand.UnitAddress = &unitAddress
+
+	msg, err := EncodeHitachiCommand(controllerMessage, hitachiCommand)
+	if err != nil {
+		return nil, nil, fmt.Errorf("p1p2: failed to encode command: %w", err)
+	}
+
+	resolved := maps.Clone(command)
+	resolved["unitAddress"] = int(unitAddress)
+	return resolved, []*device.Data{
+		{
+			Data: []byte("W" + strings.ToUpper(hex.EncodeToString(msg))),
+			Properties: map[string]interface{}{
+				"outputTopic": writeTopic,
+				"unitAddress": strconv.Itoa(int(unitAddress)),
+			},
+		},
 	}, nil
 }
 
+// controllerMessage returns the unit and the last controller message sent to it, the unit may only be omitted while
+// the controller addressed a single unit.
+func (p *p1p2) controllerMessage(unitAddress *byte) (byte, []byte, error) {
+	p.controllerMessagesLock.Lock()
+	defer p.controllerMessagesLock.Unlock()
+
+	if unitAddress == nil {
+		if len(p.controllerMessages) > 1 {
+			return 0, nil, errors.New("p1p2: invalid command: unitAddress is required as the controller addresses several units")
+		}
+		for address := range p.controllerMessages {
+			unitAddress = &address
+		}
+	}
+	if unitAddress == nil || p.controllerMessages[*unitAddress] == nil {
+		return 0, nil, errors.New("p1p2: no controller message received for the unit yet")
+	}
+	return *unitAddress, p.controllerMessages[*unitAddress], nil
+}
+
+// Acknowledged reports whether the event is a message of the commanded unit reporting the requested state.
+func (p *p1p2) Acknowledged(command device.Command, event *device.Data) bool {
+	if p.vendor != vendorHitachi {
+		return false
+	}
+	hitachiCommand, err := ParseHitachiCommand(command)
+	if err != nil {
+		return false
+	}
+	payloadMatches := p1p2MessagePattern.FindStringSubmatch(string(event.Data))
+	if len(payloadMatches) != 3 {
+		return false
+	}
+	state, err := ParseHitachiMessage(strings.TrimSpace(payloadMatches[2]))
+	if err != nil {
+		return false
+	}
+	return hitachiCommand.Matches(state)
+}
+
+func (p *p1p2) storeControllerMessage(unitAddress byte, hexString string) {
+	msg, err := hex.DecodeString(hexString)
+	if err != nil {
+		return
+	}
+	p.controllerMessagesLock.Lock()
+	defer p.controllerMessagesLock.Unlock()
+	p.controllerMessages[unitAddress] = msg
+}
+
 func init() {
 	err := device.RegisterDevice("p1p2", func(ctx context.Context, config *config.Device) (device.Device, error) {
+		vendor := strings.ToLower(config.Properties[vendorKey])
+		switch vendor {
+		case "":
+			vendor = vendorHitachi
+		case vendorHitachi, vendorDaikin:
+		default:
+			return nil, fmt.Errorf("p1p2: device %s has invalid %s value: %s, expected hitachi or daikin", config.Name, vendorKey, vendor)
+		}
+
 		return &p1p2{
-			config: config,
+			config:             config,
+			vendor:             vendor,
+			controllerMessages: make(map[byte][]byte),
 		}, nil
 	})
 	if err != nil {
@@ -0,0 +1,284 @@
+package p1p2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+func TestDecodeVendor(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		payload    string
+		expected   map[string]interface{}
+	}{
+		{
+			name:    "hitachi by default",
+			payload: "R 2024-01-01 12:00:00 T 0.120: 89002D010A0101010101094816000001140002040000000420010220008088021018131E031000000000000078",
+			expected: map[string]interface{}{
+				"vendor":             "hitachi",
+				"bridge":             "P1P2",
+				"direction":          "response",
+				"unitAddress":        "1",
+				"temperature":        "22",
+				"mode":               "Cooling",
+				"louverPosition":     "2",
+				"swing":              "false",
+				"roomTemperature":    "20",
+				"outdoorTemperature": "19",
+				"filterSign":         "false",
+				"defrost":            "false",
+			},
+		},
+		{
+			name:    "hitachi unit 2 in auto mode",
+			payload: "R 2024-01-01 12:00:00 T 0.120: 89002D020A0101010101819017000001140002040000000420010220008088021018131E03100000000000002A",
+			expected: map[string]interface{}{
+				"vendor":      "hitachi",
+				"unitAddress": "2",
+				"mode":        "Auto",
+				"fanSpeed":    "Silent",
+				"swing":       "true",
+			},
+		},
+		{
+			name:       "daikin",
+			properties: map[string]string{"vendor": "Daikin"},
+			payload:    "R 2024-01-01 12:00:00 T 0.120: 4000111E802F00FCC01B401E001D001480000008",
+			expected: map[string]interface{}{
+				"vendor":                  "daikin",
+				"direction":               "response",
+				"packetType":              "0x11",
+				"leavingWaterTemperature": "30.5",
+				"outsideTemperature":      "-3.25",
+			},
+		},
+		{
+			name:       "daikin packet without known values",
+			properties: map[string]string{"vendor": "daikin"},
+			payload:    "R 2024-01-01 12:00:00 T 0.120: 400015000096",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := device.NewDevice(context.Background(), &config.Device{
+				Name:       "heat pump",
+				Type:       "p1p2",
+				Properties: tt.properties,
+			})
+			require.NoError(t, err)
+
+			result, err := dev.Decode(context.Background(), &device.Data{
+				Data:       []byte(tt.payload),
+				Properties: map[string]interface{}{"inputTopic": "P1P2/R/P1P2"},
+			})
+			require.NoError(t, err)
+			if tt.expected == nil {
+				assert.Empty(t, result)
+				return
+			}
+			require.Len(t, result, 1)
+			for k, v := range tt.expected {
+				assert.Equal(t, v, result[0].Properties[k], k)
+			}
+		})
+	}
+}
+
+func TestInvalidVendor(t *testing.T) {
+	_, err := device.NewDevice(context.Background(), &config.Device{
+		Name:       "heat pump",
+		Type:       "p1p2",
+		Properties: map[string]string{"vendor": "mitsubishi"},
+	})
+	assert.EqualError(t, err, "p1p2: device heat pump has invalid vendor value: mitsubishi, expected hitachi or daikin")
+}
+
+func TestCommand(t *testing.T) {
+	tests := []struct {
+		name               string
+		properties         map[string]string
+		controllerPayload  string
+		command            device.Command
+		expectedPayload    string
+		expectedError      string
+		acknowledgePayload string
+		acknowledged       bool
+	}{
+		{
+			name:               "heating 24c with auto fan speed",
+			properties:         map[string]string{"writeTopic": "P1P2/W/P1P2"},
+			controllerPayload:  "R 2024-01-01 12:00:00 T 0.120: 210011010A01010101010948160000004C",
+			command:            device.Command{"temperature": float64(24), "mode": "heating", "fanSpeed": "Auto", "status": true},
+			expectedPayload:    "W210011010A010101010141411800000003",
+			acknowledgePayload: "R 2024-01-01 12:00:01 T 0.120: 89002D010A0101010101414118000001140002040000000420010220008088021018131E031000000000000037",
+			acknowledged:       true,
+		},
+		{
+			name:               "unit still reports the previous state",
+			properties:         map[string]string{"writeTopic": "P1P2/W/P1P2"},
+			controllerPayload:  "R 2024-01-01 12:00:00 T 0.120: 210011010A01010101010948160000004C",
+			command:            device.Command{"temperature": "24", "status": "on"},
+			expectedPayload:    "W210011010A010101010109481800000042",
+			acknowledgePayload: "R 2024-01-01 12:00:01 T 0.120: 89002D010A0101010101094816000001140002040000000420010220008088021018131E031000000000000078",
+		},
+		{
+			name:              "no controller message received",
+			properties:        map[string]string{"writeTopic": "P1P2/W/P1P2"},
+			command:           device.Command{"temperature": float64(24)},
+			expectedError:     "p1p2: no controller message received for the unit yet",
+			controllerPayload: "R 2024-01-01 12:00:00 T 0.120: 89002D010A0101010101094816000001140002040000000420010220008088021018131E031000000000000078",
+		},
+		{
+			name:              "temperature out of range",
+			properties:        map[string]string{"writeTopic": "P1P2/W/P1P2"},
+			controllerPayload: "R 2024-01-01 12:00:00 T 0.120: 210011010A01010101010948160000004C",
+			command:           device.Command{"temperature": float64(40)},
+			expectedError:     "p1p2: invalid command: invalid temperature: 40, expected 16-32",
+		},
+		{
+			name:              "unsupported key",
+			properties:        map[string]string{"writeTopic": "P1P2/W/P1P2"},
+			controllerPayload: "R 2024-01-01 12:00:00 T 0.120: 210011010A01010101010948160000004C",
+			command:           device.Command{"swing": true},
+			expectedError:     "p1p2: invalid command: unsupported command key: swing",
+		},
+		{
+			name:              "missing write topic",
+			controllerPayload: "R 2024-01-01 12:00:00 T 0.120: 210011010A01010101010948160000004C",
+			command:           device.Command{"temperature": float64(24)},
+			expectedError:     "p1p2: device heat pump has no writeTopic property",
+		},
+		{
+			name:              "daikin",
+			properties:        map[string]string{"vendor": "daikin", "writeTopic": "P1P2/W/P1P2"},
+			controllerPayload: "R 2024-01-01 12:00:00 T 0.120: 400015000096",
+			command:           device.Command{"temperature": float64(24)},
+			expectedError:     "p1p2: commands are not supported for vendor: daikin",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := device.NewDevice(context.Background(), &config.Device{
+				Name:       "heat pump",
+				Type:       "p1p2",
+				Properties: tt.properties,
+			})
+			require.NoError(t, err)
+			commander, ok := dev.(device.Commander)
+			require.True(t, ok)
+
+			_, err = dev.Decode(context.Background(), &device.Data{
+				Data:       []byte(tt.controllerPayload),
+				Properties: map[string]interface{}{"inputTopic": "P1P2/R/P1P2"},
+			})
+			require.NoError(t, err)
+
+			resolved, result, err := commander.Encode(context.Background(), tt.command)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			assert.Equal(t, tt.expectedPayload, string(result[0].Data))
+			assert.Equal(t, "P1P2/W/P1P2", result[0].Properties["outputTopic"])
+
+			assert.Equal(t, tt.acknowledged, commander.Acknowledged(resolved, &device.Data{Data: []byte(tt.acknowledgePayload)}))
+		})
+	}
+}
+
+func TestCommandUnitAddress(t *testing.T) {
+	const (
+		controllerUnit1 = "R 2024-01-01 12:00:00 T 0.120: 210011010A01010101010948160000004C"
+		controllerUnit2 = "R 2024-01-01 12:00:01 T 0.120: 210011020A01010101010948160000004F"
+	)
+	tests := []struct {
+		name               string
+		controllerPayloads []string
+		command            device.Command
+		expectedPayload    string
+		expectedUnit       string
+		expectedError      string
+		acknowledgeUnit1   bool
+		acknowledgeUnit2   bool
+	}{
+		{
+			name:               "single unit",
+			controllerPayloads: []string{controllerUnit2},
+			command:            device.Command{"temperature": float64(24)},
+			expectedPayload:    "W210011020A010101010109481800000041",
+			expectedUnit:       "2",
+			acknowledgeUnit2:   true,
+		},
+		{
+			name:               "explicit unit",
+			controllerPayloads: []string{controllerUnit1, controllerUnit2},
+			command:            device.Command{"temperature": float64(24), "unitAddress": float64(1)},
+			expectedPayload:    "W210011010A010101010109481800000042",
+			expectedUnit:       "1",
+			acknowledgeUnit1:   true,
+		},
+		{
+			name:               "omitted unit with several units",
+			controllerPayloads: []string{controllerUnit1, controllerUnit2},
+			command:            device.Command{"temperature": float64(24)},
+			expectedError:      "p1p2: invalid command: unitAddress is required as the controller addresses several units",
+		},
+		{
+			name:               "unit without controller message",
+			controllerPayloads: []string{controllerUnit1, controllerUnit2},
+			command:            device.Command{"temperature": float64(24), "unitAddress": float64(3)},
+			expectedError:      "p1p2: no controller message received for the unit yet",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := device.NewDevice(context.Background(), &config.Device{
+				Name:       "heat pump",
+				Type:       "p1p2",
+				Properties: map[string]string{"writeTopic": "P1P2/W/P1P2"},
+			})
+			require.NoError(t, err)
+			commander := dev.(device.Commander)
+
+			decode := func(payload string) {
+				_, err := dev.Decode(context.Background(), &device.Data{
+					Data:       []byte(payload),
+					Properties: map[string]interface{}{"inputTopic": "P1P2/R/P1P2"},
+				})
+				require.NoError(t, err)
+			}
+			for _, payload := range tt.controllerPayloads {
+				decode(payload)
+			}
+
+			resolved, result, err := commander.Encode(context.Background(), tt.command)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			assert.Equal(t, tt.expectedPayload, string(result[0].Data))
+			assert.Equal(t, tt.expectedUnit, result[0].Properties["unitAddress"])
+
+			// the controller addressing another unit after the command was sent does not change the commanded unit
+			decode(controllerUnit1)
+			decode(controllerUnit2)
+
+			// both units report 24c, only the response of the commanded unit acknowledges the command
+			unit1 := &device.Data{Data: []byte("R 2024-01-01 12:00:02 T 0.120: 89002D010A0101010101094818000001140002040000000420010220008088021018131E031000000000000076")}
+			unit2 := &device.Data{Data: []byte("R 2024-01-01 12:00:02 T 0.120: 89002D020A0101010101094818000001140002040000000420010220008088021018131E031000000000000075")}
+			assert.Equal(t, tt.acknowledgeUnit1, commander.Acknowledged(resolved, unit1))
+			assert.Equal(t, tt.acknowledgeUnit2, commander.Acknowledged(resolved, unit2))
+		})
+	}
+}
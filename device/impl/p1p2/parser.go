@@ -16,12 +16,39 @@ func ParseHitachiMessage(messageHexString string) (HitachiState, error) {
 	}
 
 	return HitachiState{
-		Direction:   msg.direction(),
-		Running:     msg.running(),
-		Mode:        msg.mode(),
-		FanMode:     msg.fanSpeed(),
-		Temperature: msg.temperature(),
-		TestMode:    msg.testMode(),
-		ErrorCode:   msg.errorCode(),
+		Direction:          msg.direction(),
+		UnitAddress:        msg.unitAddress(),
+		RefrigerantCycle:   msg.refrigerantCycle(),
+		Running:            msg.running(),
+		Mode:               msg.mode(),
+		FanMode:            msg.fanSpeed(),
+		LouverPosition:     msg.louverPosition(),
+		Swing:              msg.swing(),
+		Temperature:        msg.temperature(),
+		HasUnitStatus:      msg.hasUnitStatus(),
+		RoomTemperature:    msg.roomTemperature(),
+		OutdoorTemperature: msg.outdoorTemperature(),
+		FilterSign:         msg.filterSign(),
+		Defrost:            msg.defrost(),
+		TestMode:           msg.testMode(),
+		ErrorCode:          msg.errorCode(),
+	}, nil
+}
+
+func ParseDaikinMessage(messageHexString string) (DaikinState, error) {
+	bytes, err := hex.DecodeString(messageHexString)
+	if err != nil {
+		return DaikinState{}, err
+	}
+
+	msg := daikinMessage(bytes)
+	if err := msg.validate(); err != nil {
+		return DaikinState{}, err
+	}
+
+	return DaikinState{
+		Direction:  msg.direction(),
+		PacketType: msg.packetType(),
+		Values:     msg.values(),
 	}, nil
 }
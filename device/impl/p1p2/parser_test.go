@@ -17,12 +17,18 @@ func Test_parseHitachiMessage(t *testing.T) {
 			name:             "test units to remote for fan speed low 22c, power on cooling, test mode off",
 			messageHexString: "89002D010A0101010101094816000001140002040000000420010220008088021018131E031000000000000078",
 			expected: HitachiState{
-				Direction:   DirectionUnitsToController,
-				Running:     true,
-				Mode:        HitachiModeCooling,
-				FanMode:     HitachiFanSpeedLow,
-				Temperature: 22,
-				TestMode:    false,
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            true,
+				Mode:               HitachiModeCooling,
+				FanMode:            HitachiFanSpeedLow,
+				LouverPosition:     2,
+				Temperature:        22,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+				TestMode:           false,
 			},
 			expectedError: "",
 		},
@@ -30,12 +36,18 @@ func Test_parseHitachiMessage(t *testing.T) {
 			name:             "test units to remote for fan speed high 30c, power on cooling, test mode off",
 			messageHexString: "89002D010A010101010109221E000001140002040000000420010220008088021018131E03100000000000001A",
 			expected: HitachiState{
-				Direction:   DirectionUnitsToController,
-				Running:     true,
-				Mode:        HitachiModeCooling,
-				FanMode:     HitachiFanSpeedHigh,
-				Temperature: 30,
-				TestMode:    false,
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            true,
+				Mode:               HitachiModeCooling,
+				FanMode:            HitachiFanSpeedHigh,
+				LouverPosition:     1,
+				Temperature:        30,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+				TestMode:           false,
 			},
 			expectedError: "",
 		},
@@ -43,12 +55,18 @@ func Test_parseHitachiMessage(t *testing.T) {
 			name:             "test units to remote for fan speed medium 30c, power on cooling, test mode off",
 			messageHexString: "89002D010A010101010109241E000001140002040000000420010220008088021018131E03100000000000001C",
 			expected: HitachiState{
-				Direction:   DirectionUnitsToController,
-				Running:     true,
-				Mode:        HitachiModeCooling,
-				FanMode:     HitachiFanSpeedMedium,
-				Temperature: 30,
-				TestMode:    false,
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            true,
+				Mode:               HitachiModeCooling,
+				FanMode:            HitachiFanSpeedMedium,
+				LouverPosition:     1,
+				Temperature:        30,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+				TestMode:           false,
 			},
 			expectedError: "",
 		},
@@ -56,12 +74,18 @@ func Test_parseHitachiMessage(t *testing.T) {
 			name:             "test units to remote for fan speed low 30c, power on cooling, test mode off",
 			messageHexString: "89002D010A010101010109281E000001140002040000000420010220008088021018131E031000000000000010",
 			expected: HitachiState{
-				Direction:   DirectionUnitsToController,
-				Running:     true,
-				Mode:        HitachiModeCooling,
-				FanMode:     HitachiFanSpeedLow,
-				Temperature: 30,
-				TestMode:    false,
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            true,
+				Mode:               HitachiModeCooling,
+				FanMode:            HitachiFanSpeedLow,
+				LouverPosition:     1,
+				Temperature:        30,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+				TestMode:           false,
 			},
 			expectedError: "",
 		},
@@ -69,12 +93,18 @@ func Test_parseHitachiMessage(t *testing.T) {
 			name:             "test units to remote for fan speed low 30c, power off cooling, test mode off",
 			messageHexString: "89002D010A010101010108281E000001140002040000000420010220008088021018131E031000000000000011",
 			expected: HitachiState{
-				Direction:   DirectionUnitsToController,
-				Running:     false,
-				Mode:        HitachiModeCooling,
-				FanMode:     HitachiFanSpeedLow,
-				Temperature: 30,
-				TestMode:    false,
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            false,
+				Mode:               HitachiModeCooling,
+				FanMode:            HitachiFanSpeedLow,
+				LouverPosition:     1,
+				Temperature:        30,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+				TestMode:           false,
 			},
 			expectedError: "",
 		},
@@ -82,12 +112,18 @@ func Test_parseHitachiMessage(t *testing.T) {
 			name:             "test units to remote for fan speed low 30c, power off cooling, test mode on",
 			messageHexString: "89002D010A010101010108081E000001140002040000000420010220008088021018131E031000000000080039",
 			expected: HitachiState{
-				Direction:   DirectionUnitsToController,
-				Running:     false,
-				Mode:        HitachiModeCooling,
-				FanMode:     HitachiFanSpeedLow,
-				Temperature: 30,
-				TestMode:    true,
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            false,
+				Mode:               HitachiModeCooling,
+				FanMode:            HitachiFanSpeedLow,
+				LouverPosition:     0,
+				Temperature:        30,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+				TestMode:           true,
 			},
 			expectedError: "",
 		},
@@ -95,16 +131,99 @@ func Test_parseHitachiMessage(t *testing.T) {
 			name:             "test units to remote for fan speed high 22c, power on cooling, test mode off",
 			messageHexString: "89002D010A0101010101096216000602140000040000000420010220008088021018131E031000000000000055",
 			expected: HitachiState{
-				Direction:   DirectionUnitsToController,
-				Running:     true,
-				Mode:        HitachiModeCooling,
-				FanMode:     HitachiFanSpeedHigh,
-				Temperature: 22,
-				TestMode:    false,
-				ErrorCode:   6,
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            true,
+				Mode:               HitachiModeCooling,
+				FanMode:            HitachiFanSpeedHigh,
+				LouverPosition:     3,
+				Temperature:        22,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+				TestMode:           false,
+				ErrorCode:          6,
 			},
 			expectedError: "",
 		},
+		{
+			name:             "test units to remote for fan speed low 24c, power on dry, test mode off",
+			messageHexString: "89002D010A0101010101110818000001140002040000000420010220008088021018131E03100000000000002E",
+			expected: HitachiState{
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            true,
+				Mode:               HitachiModeDry,
+				FanMode:            HitachiFanSpeedLow,
+				Temperature:        24,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+			},
+		},
+		{
+			name:             "test units to remote for fan speed medium 22c, power on fan, test mode off",
+			messageHexString: "89002D010A0101010101210416000001140002040000000420010220008088021018131E03100000000000001C",
+			expected: HitachiState{
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            true,
+				Mode:               HitachiModeFan,
+				FanMode:            HitachiFanSpeedMedium,
+				Temperature:        22,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+			},
+		},
+		{
+			name:             "test unit 2 to remote for fan speed silent 23c, power on auto, swing on",
+			messageHexString: "89002D020A0101010101819017000001140002040000000420010220008088021018131E03100000000000002A",
+			expected: HitachiState{
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        2,
+				RefrigerantCycle:   10,
+				Running:            true,
+				Mode:               HitachiModeAuto,
+				FanMode:            HitachiFanSpeedSilent,
+				Swing:              true,
+				Temperature:        23,
+				HasUnitStatus:      true,
+				RoomTemperature:    20,
+				OutdoorTemperature: 19,
+			},
+		},
+		{
+			name:             "test units to remote for fan speed auto 22c, power on heating, defrost and filter sign on",
+			messageHexString: "89002D010A0101010101410116030001120002040000000420010220008088021018FB1E031000000000000094",
+			expected: HitachiState{
+				Direction:          DirectionUnitsToController,
+				UnitAddress:        1,
+				RefrigerantCycle:   10,
+				Running:            true,
+				Mode:               HitachiModeHeating,
+				FanMode:            HitachiFanSpeedAuto,
+				Temperature:        22,
+				HasUnitStatus:      true,
+				RoomTemperature:    18,
+				OutdoorTemperature: -5,
+				FilterSign:         true,
+				Defrost:            true,
+			},
+		},
+		{
+			name:             "test units to remote with truncated message",
+			messageHexString: "89002D010A01010101010948160000",
+			expectedError:    "invalid message length: 15",
+		},
+		{
+			name:             "test units to remote with invalid checksum",
+			messageHexString: "89002D010A0101010101094816000001140002040000000420010220008088021018131E031000000000000079",
+			expectedError:    "invalid checksum",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -118,3 +237,118 @@ func Test_parseHitachiMessage(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseDaikinMessage(t *testing.T) {
+	tests := []struct {
+		name             string
+		messageHexString string
+		expected         DaikinState
+		expectedError    string
+	}{
+		{
+			name:             "heat pump temperatures",
+			messageHexString: "4000111E802F00FCC01B401E001D001480000008",
+			expected: DaikinState{
+				Direction:  DaikinDirectionHeatPumpToController,
+				PacketType: 0x11,
+				Values: []DaikinValue{
+					{Name: "leavingWaterTemperature", Value: "30.5"},
+					{Name: "dhwTemperature", Value: "47"},
+					{Name: "outsideTemperature", Value: "-3.25"},
+					{Name: "returnWaterTemperature", Value: "27.25"},
+					{Name: "leavingWaterHeatExchangerTemperature", Value: "30"},
+					{Name: "refrigerantTemperature", Value: "29"},
+					{Name: "roomTemperature", Value: "20.5"},
+				},
+			},
+		},
+		{
+			name:             "heat pump status",
+			messageHexString: "400010010101000000150000040000400093",
+			expected: DaikinState{
+				Direction:  DaikinDirectionHeatPumpToController,
+				PacketType: 0x10,
+				Values: []DaikinValue{
+					{Name: "heatingPower", Value: "true"},
+					{Name: "operatingMode", Value: "Heating"},
+					{Name: "operatingModeId", Value: "2"},
+					{Name: "dhwPower", Value: "true"},
+					{Name: "targetRoomTemperature", Value: "21"},
+					{Name: "quietMode", Value: "true"},
+					{Name: "dhwActive", Value: "true"},
+				},
+			},
+		},
+		{
+			name:             "controller room temperature",
+			messageHexString: "00001115800000BE",
+			expected: DaikinState{
+				Direction:  DaikinDirectionControllerToHeatPump,
+				PacketType: 0x11,
+				Values: []DaikinValue{
+					{Name: "roomTemperature", Value: "21.5"},
+				},
+			},
+		},
+		{
+			name:             "compressor and pumps",
+			messageHexString: "40001200000000000000000000000B00A5",
+			expected: DaikinState{
+				Direction:  DaikinDirectionHeatPumpToController,
+				PacketType: 0x12,
+				Values: []DaikinValue{
+					{Name: "heatPumpRunning", Value: "true"},
+					{Name: "compressorRunning", Value: "true"},
+					{Name: "circulationPumpRunning", Value: "true"},
+					{Name: "dhwRunning", Value: "false"},
+				},
+			},
+		},
+		{
+			name:             "dhw setpoint and flow",
+			messageHexString: "400013300000000000000000007B42",
+			expected: DaikinState{
+				Direction:  DaikinDirectionHeatPumpToController,
+				PacketType: 0x13,
+				Values: []DaikinValue{
+					{Name: "targetDhwTemperature", Value: "48"},
+					{Name: "flow", Value: "12.3"},
+				},
+			},
+		},
+		{
+			name:             "packet without known values",
+			messageHexString: "400015000096",
+			expected: DaikinState{
+				Direction:  DaikinDirectionHeatPumpToController,
+				PacketType: 0x15,
+			},
+		},
+		{
+			name:             "invalid checksum",
+			messageHexString: "400015000097",
+			expectedError:    "invalid checksum",
+		},
+		{
+			name:             "unknown packet type",
+			messageHexString: "4000300000F7",
+			expectedError:    "unknown packet type: 0x30",
+		},
+		{
+			name:             "unknown direction",
+			messageHexString: "210011000000",
+			expectedError:    "unknown direction: 33",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseDaikinMessage(test.messageHexString)
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, actual)
+			}
+		})
+	}
+}
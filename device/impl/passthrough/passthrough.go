@@ -11,7 +11,7 @@ type passthrough struct {
 	config *config.Device
 }
 
-func (p *passthrough) Decode(_ context.Context, data *device.Data) (*device.Data, error) {
+func (p *passthrough) Decode(_ context.Context, data *device.Data) ([]*device.Data, error) {
 	properties := make(map[string]interface{}, len(data.Properties)+4)
 	for k, v := range data.Properties {
 		properties[k] = v
@@ -20,9 +20,11 @@ func (p *passthrough) Decode(_ context.Context, data *device.Data) (*device.Data
 	properties["deviceType"] = p.config.Type
 	properties["deviceProperties"] = p.config.Properties
 	properties["value"] = string(data.Data)
-	return &device.Data{
-		Data:       data.Data,
-		Properties: properties,
+	return []*device.Data{
+		{
+			Data:       data.Data,
+			Properties: properties,
+		},
 	}, nil
 }
 
@@ -10,10 +10,7 @@ import (
 // https://shelly-api-docs.shelly.cloud/docs-ble/Devices/wall_eu
 func init() {
 	err := device.RegisterDevice("SBBT-004CEU", func(ctx context.Context, config *config.Device) (device.Device, error) {
-		return &shellyBtDevice{
-			name:       config.Name,
-			deviceType: config.Type,
-		}, nil
+		return newShellyBtDevice(config)
 	})
 	if err != nil {
 		panic(err)
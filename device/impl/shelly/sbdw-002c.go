@@ -10,10 +10,7 @@ import (
 // https://shelly-api-docs.shelly.cloud/docs-ble/Devices/dw/
 func init() {
 	err := device.RegisterDevice("SBDW-002C", func(ctx context.Context, config *config.Device) (device.Device, error) {
-		return &shellyBtDevice{
-			name:       config.Name,
-			deviceType: config.Type,
-		}, nil
+		return newShellyBtDevice(config)
 	})
 	if err != nil {
 		panic(err)
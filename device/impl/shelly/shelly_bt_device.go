@@ -4,45 +4,131 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 
+	"github.com/sirupsen/logrus"
+
+	"github.com/nikiforov-soft/yasp/config"
 	"github.com/nikiforov-soft/yasp/device"
 	"github.com/nikiforov-soft/yasp/device/vendors/bthome"
 )
 
+const (
+	macAddressPropertiesKey    = "macAddress"
+	encryptionKeyPropertiesKey = "encryptionKey"
+)
+
 type shellyBtDevice struct {
-	name       string
-	deviceType string
+	name         string
+	deviceType   string
+	macAddress   string
+	decoder      *bthome.Decoder
+	deduplicator *device.Deduplicator
+}
+
+func newShellyBtDevice(config *config.Device) (device.Device, error) {
+	sbd := &shellyBtDevice{
+		name:       config.Name,
+		deviceType: config.Type,
+	}
+
+	if macAddressValue, exists := config.Properties[macAddressPropertiesKey]; exists {
+		macAddress, err := net.ParseMAC(macAddressValue)
+		if err != nil {
+			return nil, fmt.Errorf("shelly: device %s has invalid mac address value: %w", config.Name, err)
+		}
+		sbd.macAddress = macAddress.String()
+	}
+
+	var encryptionKey []byte
+	if encryptionKeyValue, exists := config.Properties[encryptionKeyPropertiesKey]; exists {
+		if sbd.macAddress == "" {
+			return nil, fmt.Errorf("shelly: device %s is missing %s property required for decryption", config.Name, macAddressPropertiesKey)
+		}
+
+		var err error
+		encryptionKey, err = hex.DecodeString(encryptionKeyValue)
+		if err != nil {
+			return nil, fmt.Errorf("shelly: device %s has invalid encryption key value: %w", config.Name, err)
+		}
+		if len(encryptionKey) != 16 {
+			return nil, fmt.Errorf("shelly: device %s has invalid encryption key length: %d", config.Name, len(encryptionKey))
+		}
+	}
+
+	deduplicator, err := device.NewDeduplicator(config.Type, config.Properties)
+	if err != nil {
+		return nil, fmt.Errorf("shelly: device %s - %w", config.Name, err)
+	}
+
+	sbd.decoder = bthome.NewDecoder(sbd.macAddress, encryptionKey)
+	sbd.deduplicator = deduplicator
+	return sbd, nil
 }
 
-func (sbd *shellyBtDevice) Decode(_ context.Context, data *device.Data) (*device.Data, error) {
-	sensorData, err := bthome.Parse(data.Data)
+func (sbd *shellyBtDevice) Decode(_ context.Context, data *device.Data) ([]*device.Data, error) {
+	macAddress, ok := device.MacAddress(data.Properties)
+	if sbd.macAddress != "" {
+		if ok && macAddress != sbd.macAddress {
+			return nil, nil
+		}
+		macAddress = sbd.macAddress
+	}
+
+	sensorData, err := sbd.decoder.Decode(data.Data)
 	if err != nil {
+		if errors.Is(err, bthome.ErrBindKeyRequired) {
+			logrus.
+				WithField("deviceName", sbd.name).
+				Debug("shelly: dropping encrypted advertisement, the device has no encryptionKey property")
+			return nil, nil
+		}
+		if errors.Is(err, bthome.ErrReplayedCounter) {
+			logrus.
+				WithError(err).
+				WithField("deviceName", sbd.name).
+				Debug("shelly: dropping replayed advertisement")
+			return nil, nil
+		}
 		return nil, fmt.Errorf("shelly: failed to parse sensor data: %s - %w", hex.EncodeToString(data.Data), err)
 	}
 
+	if sensorData.HasPacketId && sbd.deduplicator.IsDuplicate(macAddress, uint32(sensorData.PacketId)) {
+		return nil, nil
+	}
+
 	result, err := json.Marshal(sensorData)
 	if err != nil {
 		return nil, fmt.Errorf("shelly: failed to marshal sensor data: %w", err)
 	}
 
-	properties := make(map[string]interface{}, len(data.Properties)+8)
+	properties := make(map[string]interface{}, len(data.Properties)+11)
 	for k, v := range data.Properties {
 		properties[k] = v
 	}
 	properties["deviceName"] = sbd.name
 	properties["deviceType"] = sbd.deviceType
-	properties["batteryPercent"] = sensorData.BatteryPercent
-	properties["illuminanceLux"] = sensorData.IlluminanceLux
-	properties["motionState"] = sensorData.MotionState
-	properties["windowState"] = sensorData.WindowState
-	properties["humidityPercent"] = sensorData.HumidityPercent
-	properties["buttonEvent"] = sensorData.ButtonEvent
-	properties["rotationDegrees"] = sensorData.RotationDegrees
-	properties["temperatureCelsius"] = sensorData.TemperatureCelsius
-
-	return &device.Data{
-		Data:       result,
-		Properties: properties,
+	properties["batteryPercent"] = sbd.findValue(sensorData, "Battery")
+	properties["illuminanceLux"] = sbd.findValue(sensorData, "Illuminance")
+	properties["motionState"] = sbd.findValue(sensorData, "Motion")
+	properties["windowState"] = sbd.findValue(sensorData, "Window")
+	properties["humidityPercent"] = sbd.findValue(sensorData, "Humidity")
+	properties["buttonEvent"] = sbd.findValue(sensorData, "Button")
+	properties["rotationDegrees"] = sbd.findValue(sensorData, "Rotation")
+	properties["temperatureCelsius"] = sbd.findValue(sensorData, "Temperature")
+	properties["measurements"] = sensorData.Measurements
+
+	return []*device.Data{
+		{
+			Data:       result,
+			Properties: properties,
+		},
 	}, nil
 }
+
+func (sbd *shellyBtDevice) findValue(sensorData *bthome.SensorData, measurementType string) float64 {
+	measurement, _ := sensorData.Find(measurementType)
+	return measurement.Value
+}
@@ -0,0 +1,91 @@
+package shelly
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name               string
+		properties         map[string]string
+		macAddress         string
+		data               string
+		expectedData       string
+		expectedProperties map[string]interface{}
+	}{
+		{
+			name:         "door window sensor",
+			properties:   map[string]string{"macAddress": "3C:2E:F5:00:00:01"},
+			macAddress:   "3C:2E:F5:00:00:01",
+			data:         "44002c0164051405002d013f83ff",
+			expectedData: `{"capabilityFlags":{"triggerBasedDevice":true,"version":2},"hasPacketId":true,"packetId":44,"measurements":[{"objectId":1,"type":"Battery","unit":"%","index":0,"value":100},{"objectId":5,"type":"Illuminance","unit":"lx","index":0,"value":13},{"objectId":45,"type":"Window","index":0,"value":1},{"objectId":63,"type":"Rotation","unit":"°","index":0,"value":-12.5}]}`,
+			expectedProperties: map[string]interface{}{
+				"deviceName":         "door",
+				"deviceType":         "SBBT-004CEU",
+				"batteryPercent":     float64(100),
+				"illuminanceLux":     float64(13),
+				"windowState":        float64(1),
+				"rotationDegrees":    -12.5,
+				"motionState":        float64(0),
+				"temperatureCelsius": float64(0),
+			},
+		},
+		{
+			name:         "any device without mac address",
+			macAddress:   "3C:2E:F5:00:00:02",
+			data:         "40450101",
+			expectedData: `{"capabilityFlags":{"version":2},"measurements":[{"objectId":69,"type":"Temperature","unit":"°C","index":0,"value":25.7}]}`,
+			expectedProperties: map[string]interface{}{
+				"temperatureCelsius": 25.7,
+			},
+		},
+		{
+			name:       "advertisement of another device",
+			properties: map[string]string{"macAddress": "3C:2E:F5:00:00:01"},
+			macAddress: "3C:2E:F5:00:00:02",
+			data:       "44002c0164051405002d013f83ff",
+		},
+		{
+			name:       "encrypted without key",
+			properties: map[string]string{"macAddress": "54:48:E6:8F:80:A5"},
+			macAddress: "54:48:E6:8F:80:A5",
+			data:       "41a47266c95f730011223378237214",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := device.NewDevice(context.Background(), &config.Device{
+				Name:       "door",
+				Type:       "SBBT-004CEU",
+				Properties: tt.properties,
+			})
+			require.NoError(t, err)
+
+			data, err := hex.DecodeString(tt.data)
+			require.NoError(t, err)
+
+			result, err := dev.Decode(context.Background(), &device.Data{
+				Data:       data,
+				Properties: map[string]interface{}{"macAddress": tt.macAddress},
+			})
+			require.NoError(t, err)
+			if tt.expectedData == "" {
+				assert.Empty(t, result)
+				return
+			}
+			require.Len(t, result, 1)
+			assert.JSONEq(t, tt.expectedData, string(result[0].Data))
+			for k, v := range tt.expectedProperties {
+				assert.Equal(t, v, result[0].Properties[k], k)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package zigbee2mqtt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// attributeUnit is the normalized unit name and unit of measurement of a zigbee2mqtt state attribute.
+type attributeUnit struct {
+	name              string
+	unitOfMeasurement string
+}
+
+// attributeUnits maps the zigbee2mqtt attributes to the unit names used by the other devices,
+// https://www.zigbee2mqtt.io/guide/usage/exposes.html
+var attributeUnits = map[string]attributeUnit{
+	"temperature":        {name: "Temperature", unitOfMeasurement: "°C"},
+	"local_temperature":  {name: "Temperature", unitOfMeasurement: "°C"},
+	"device_temperature": {name: "DeviceTemperature", unitOfMeasurement: "°C"},
+	"humidity":           {name: "Humidity", unitOfMeasurement: "%"},
+	"pressure":           {name: "Pressure", unitOfMeasurement: "hPa"},
+	"battery":            {name: "Battery", unitOfMeasurement: "%"},
+	"battery_low":        {name: "BatteryLow"},
+	"linkquality":        {name: "LinkQuality", unitOfMeasurement: "lqi"},
+	"illuminance":        {name: "Illuminance"},
+	"illuminance_lux":    {name: "Illuminance", unitOfMeasurement: "lx"},
+	"soil_moisture":      {name: "Moisture", unitOfMeasurement: "%"},
+	"co2":                {name: "CO2", unitOfMeasurement: "ppm"},
+	"pm25":               {name: "PM2.5", unitOfMeasurement: "µg/m³"},
+	"pm10":               {name: "PM10", unitOfMeasurement: "µg/m³"},
+	"power":              {name: "Power", unitOfMeasurement: "W"},
+	"energy":             {name: "Energy", unitOfMeasurement: "kWh"},
+	"current":            {name: "Current", unitOfMeasurement: "A"},
+	// battery powered devices report millivolts, mains powered devices volts
+	"voltage":    {name: "Voltage"},
+	"occupancy":  {name: "Occupancy"},
+	"presence":   {name: "Presence"},
+	"contact":    {name: "Contact"},
+	"water_leak": {name: "WaterLeak"},
+	"smoke":      {name: "Smoke"},
+	"gas":        {name: "GasDetected"},
+	"tamper":     {name: "Tamper"},
+	"vibration":  {name: "Vibration"},
+	"state":      {name: "State"},
+	"brightness": {name: "Brightness"},
+}
+
+// ignoredAttributes are numeric attributes which are not measurements.
+var ignoredAttributes = map[string]bool{
+	"last_seen": true,
+}
+
+// unitOf returns the normalized unit of the attribute, unknown snake case attributes are converted to pascal case.
+func unitOf(attribute string) attributeUnit {
+	if unit, exists := attributeUnits[attribute]; exists {
+		return unit
+	}
+
+	var sb strings.Builder
+	upper := true
+	for _, r := range attribute {
+		if r == '_' || r == '-' || r == ' ' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return attributeUnit{name: sb.String()}
+}
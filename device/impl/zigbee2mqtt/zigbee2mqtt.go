@@ -0,0 +1,163 @@
+package zigbee2mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+const (
+	deviceType                = "zigbee2mqtt"
+	friendlyNamePropertiesKey = "friendlyName"
+	topicPrefixPropertiesKey  = "topicPrefix"
+	attributesPropertiesKey   = "attributes"
+
+	defaultTopicPrefix = "zigbee2mqtt"
+	anyFriendlyName    = "*"
+	topicPropertyKey   = "inputTopic"
+)
+
+// nonStateTopics are the sub topics of a device which do not carry its state.
+var nonStateTopics = []string{"/availability", "/set", "/get"}
+
+// zigbee2mqttDevice decodes the json state published by zigbee2mqtt on <topicPrefix>/<friendlyName> into an event per
+// numeric or boolean attribute.
+type zigbee2mqttDevice struct {
+	friendlyNames []string
+	topicPrefix   string
+	attributes    []string
+}
+
+func (zd *zigbee2mqttDevice) Decode(_ context.Context, data *device.Data) ([]*device.Data, error) {
+	friendlyName, ok := zd.friendlyName(data.Properties)
+	if !ok {
+		return nil, nil
+	}
+
+	var state map[string]any
+	if err := json.Unmarshal(data.Data, &state); err != nil {
+		return nil, fmt.Errorf("zigbee2mqtt: failed to json decode state: friendly name: %s - %w", friendlyName, err)
+	}
+
+	attributes := make([]string, 0, len(state))
+	for attribute := range state {
+		attributes = append(attributes, attribute)
+	}
+	slices.Sort(attributes)
+
+	var result []*device.Data
+	for _, attribute := range attributes {
+		if ignoredAttributes[attribute] || (len(zd.attributes) != 0 && !slices.Contains(zd.attributes, attribute)) {
+			continue
+		}
+		value, ok := formatValue(state[attribute])
+		if !ok {
+			continue
+		}
+		unit := unitOf(attribute)
+
+		properties := make(map[string]interface{}, len(data.Properties)+6)
+		for k, v := range data.Properties {
+			properties[k] = v
+		}
+		properties["deviceName"] = friendlyName
+		properties["deviceType"] = deviceType
+		properties["attribute"] = attribute
+		properties["unit"] = unit.name
+		if unit.unitOfMeasurement != "" {
+			properties["unitOfMeasurement"] = unit.unitOfMeasurement
+		}
+		properties["value"] = value
+
+		result = append(result, &device.Data{
+			Data:       []byte(value),
+			Properties: properties,
+		})
+	}
+	return result, nil
+}
+
+// friendlyName returns the friendly name of the device the state was published for, if it is one of the configured ones.
+func (zd *zigbee2mqttDevice) friendlyName(properties map[string]interface{}) (string, bool) {
+	topic, ok := properties[topicPropertyKey].(string)
+	if !ok {
+		return "", false
+	}
+	friendlyName, ok := strings.CutPrefix(topic, zd.topicPrefix+"/")
+	if !ok || friendlyName == "" {
+		return "", false
+	}
+
+	if slices.Contains(zd.friendlyNames, friendlyName) {
+		return friendlyName, true
+	}
+	if !slices.Contains(zd.friendlyNames, anyFriendlyName) || strings.HasPrefix(friendlyName, "bridge/") {
+		return "", false
+	}
+	for _, suffix := range nonStateTopics {
+		if strings.HasSuffix(friendlyName, suffix) {
+			return "", false
+		}
+	}
+	return friendlyName, true
+}
+
+// formatValue formats numbers and booleans, ON and OFF states are reported as 1 and 0.
+func formatValue(value any) (string, bool) {
+	switch value := value.(type) {
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		if value {
+			return "1", true
+		}
+		return "0", true
+	case string:
+		switch strings.ToUpper(value) {
+		case "ON":
+			return "1", true
+		case "OFF":
+			return "0", true
+		}
+	}
+	return "", false
+}
+
+func init() {
+	err := device.RegisterDevice(deviceType, func(ctx context.Context, config *config.Device) (device.Device, error) {
+		friendlyNames := splitList(config.Properties[friendlyNamePropertiesKey])
+		if len(friendlyNames) == 0 {
+			friendlyNames = []string{config.Name}
+		}
+
+		topicPrefix := defaultTopicPrefix
+		if value, exists := config.Properties[topicPrefixPropertiesKey]; exists && value != "" {
+			topicPrefix = strings.TrimSuffix(value, "/")
+		}
+
+		return &zigbee2mqttDevice{
+			friendlyNames: friendlyNames,
+			topicPrefix:   topicPrefix,
+			attributes:    splitList(config.Properties[attributesPropertiesKey]),
+		}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func splitList(value string) []string {
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+package zigbee2mqtt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		topic      string
+		payload    string
+		expected   []map[string]interface{}
+	}{
+		{
+			name:       "temperature sensor",
+			properties: map[string]string{"friendlyName": "kitchen"},
+			topic:      "zigbee2mqtt/kitchen",
+			payload:    `{"battery":97,"humidity":41.23,"last_seen":1704067200000,"linkquality":120,"temperature":21.5,"update":{"state":"idle"}}`,
+			expected: []map[string]interface{}{
+				{"attribute": "battery", "unit": "Battery", "unitOfMeasurement": "%", "value": "97"},
+				{"attribute": "humidity", "unit": "Humidity", "unitOfMeasurement": "%", "value": "41.23"},
+				{"attribute": "linkquality", "unit": "LinkQuality", "unitOfMeasurement": "lqi", "value": "120"},
+				{"attribute": "temperature", "unit": "Temperature", "unitOfMeasurement": "°C", "value": "21.5"},
+			},
+		},
+		{
+			name:       "binary attributes",
+			properties: map[string]string{"friendlyName": "door, plug"},
+			topic:      "zigbee2mqtt/plug",
+			payload:    `{"child_lock":"UNLOCK","contact":false,"power_outage_count":3,"state":"ON"}`,
+			expected: []map[string]interface{}{
+				{"attribute": "contact", "unit": "Contact", "value": "0"},
+				{"attribute": "power_outage_count", "unit": "PowerOutageCount", "value": "3"},
+				{"attribute": "state", "unit": "State", "value": "1"},
+			},
+		},
+		{
+			name:       "selected attributes",
+			properties: map[string]string{"friendlyName": "*", "attributes": "occupancy"},
+			topic:      "zigbee2mqtt/hallway/motion",
+			payload:    `{"battery":100,"occupancy":true}`,
+			expected: []map[string]interface{}{
+				{"attribute": "occupancy", "unit": "Occupancy", "value": "1"},
+			},
+		},
+		{
+			name:       "other friendly name",
+			properties: map[string]string{"friendlyName": "kitchen"},
+			topic:      "zigbee2mqtt/garage",
+			payload:    `{"temperature":10}`,
+		},
+		{
+			name:       "availability topic",
+			properties: map[string]string{"friendlyName": "*"},
+			topic:      "zigbee2mqtt/kitchen/availability",
+			payload:    `{"state":"online"}`,
+		},
+		{
+			name:       "bridge topic",
+			properties: map[string]string{"friendlyName": "*"},
+			topic:      "zigbee2mqtt/bridge/state",
+			payload:    `{"state":"online"}`,
+		},
+		{
+			name:       "custom topic prefix",
+			properties: map[string]string{"friendlyName": "kitchen", "topicPrefix": "z2m/"},
+			topic:      "z2m/kitchen",
+			payload:    `{"temperature":-2}`,
+			expected: []map[string]interface{}{
+				{"attribute": "temperature", "unit": "Temperature", "unitOfMeasurement": "°C", "value": "-2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev, err := device.NewDevice(context.Background(), &config.Device{
+				Name:       "zigbee",
+				Type:       "zigbee2mqtt",
+				Properties: tt.properties,
+			})
+			require.NoError(t, err)
+
+			result, err := dev.Decode(context.Background(), &device.Data{
+				Data:       []byte(tt.payload),
+				Properties: map[string]interface{}{"inputTopic": tt.topic},
+			})
+			require.NoError(t, err)
+			require.Len(t, result, len(tt.expected))
+			for i, expected := range tt.expected {
+				for k, v := range expected {
+					assert.Equal(t, v, result[i].Properties[k], k)
+				}
+				assert.Equal(t, expected["value"], string(result[i].Data))
+				assert.Equal(t, "zigbee2mqtt", result[i].Properties["deviceType"])
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package device
+
+import (
+	"net"
+)
+
+// macAddressPropertyKeys are the input properties which carry the mac address of the advertiser, in order of preference.
+var macAddressPropertyKeys = []string{
+	"bleToMqttMacAddress",
+	"macAddress",
+}
+
+// MacAddress returns the normalized mac address of the advertiser reported by the input, if any.
+func MacAddress(properties map[string]interface{}) (string, bool) {
+	for _, key := range macAddressPropertyKeys {
+		value, ok := properties[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+
+		macAddress, err := net.ParseMAC(value)
+		if err != nil {
+			continue
+		}
+		return macAddress.String(), true
+	}
+	return "", false
+}
@@ -0,0 +1,65 @@
+package atc
+
+import (
+	"crypto/aes"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/pschlump/AesCCM"
+)
+
+const (
+	encryptionMicLength = 4
+)
+
+var (
+	ErrBindKeyRequired   = errors.New("bind key required")
+	serviceDataUuidBytes = []byte{0x1A, 0x18}
+)
+
+// decrypt - Decrypts the measurements of an encrypted advertisement, the data starts with the counter and ends with the mic
+// https://github.com/pvvx/ATC_MiThermometer/blob/master/src/ccm.c
+func decrypt(data []byte, macAddress string, bindKey []byte) ([]byte, error) {
+	if len(bindKey) == 0 {
+		return nil, ErrBindKeyRequired
+	}
+
+	if len(data) < 1+encryptionMicLength {
+		return nil, fmt.Errorf("invalid encrypted data length: %d", len(data))
+	}
+
+	mac, err := net.ParseMAC(macAddress)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mac address: %w", err)
+	}
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("invalid mac address length: %d", len(mac))
+	}
+
+	var nonce []byte
+	for i := len(mac) - 1; i >= 0; i-- {
+		nonce = append(nonce, mac[i]) // reverse mac
+	}
+	nonce = append(nonce, byte(len(data)+3), 0x16) // advertising data length and type
+	nonce = append(nonce, serviceDataUuidBytes...) // uuid
+	nonce = append(nonce, data[0])                 // counter
+
+	aesCipher, err := aes.NewCipher(bindKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initializes aes cipher: %w", err)
+	}
+
+	ccm, err := aesccm.NewCCM(aesCipher, encryptionMicLength, len(nonce))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize ccm cipher: %w", err)
+	}
+
+	// the ccm cipher decrypts in place, the advertisement is left untouched for the other devices
+	ciphertext := append([]byte(nil), data[1:]...)
+	decrypted, err := ccm.Open(nil, nonce, ciphertext, []byte{0x11})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data: %w", err)
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,56 @@
+package atc
+
+// Format is the layout of a custom firmware advertisement on the 0x181A environmental sensing service data
+// https://github.com/pvvx/ATC_MiThermometer#bluetooth-advertising-formats
+type Format byte
+
+const (
+	FormatUnknown Format = iota
+	FormatAtc1441
+	FormatAtc1441Encrypted
+	FormatPvvx
+	FormatPvvxEncrypted
+)
+
+const (
+	atc1441Length          = 13
+	atc1441EncryptedLength = 8
+	pvvxLength             = 15
+	pvvxEncryptedLength    = 11
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatAtc1441:
+		return "atc1441"
+	case FormatAtc1441Encrypted:
+		return "atc1441-encrypted"
+	case FormatPvvx:
+		return "pvvx"
+	case FormatPvvxEncrypted:
+		return "pvvx-encrypted"
+	default:
+		return "unknown"
+	}
+}
+
+// DetectFormat returns the format of the service data by its length.
+func DetectFormat(data []byte) Format {
+	switch len(data) {
+	case atc1441Length:
+		return FormatAtc1441
+	case atc1441EncryptedLength:
+		return FormatAtc1441Encrypted
+	case pvvxLength:
+		return FormatPvvx
+	case pvvxEncryptedLength:
+		return FormatPvvxEncrypted
+	default:
+		return FormatUnknown
+	}
+}
+
+// IsEncrypted reports whether the format carries encrypted measurements.
+func (f Format) IsEncrypted() bool {
+	return f == FormatAtc1441Encrypted || f == FormatPvvxEncrypted
+}
@@ -0,0 +1,97 @@
+package atc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// Parse decodes the service data of the format, macAddress and bindKey are used to decrypt the encrypted formats.
+func Parse(format Format, data []byte, macAddress string, bindKey []byte) (*SensorData, error) {
+	switch format {
+	case FormatAtc1441:
+		return parseAtc1441(data)
+	case FormatAtc1441Encrypted:
+		return parseAtc1441Encrypted(data, macAddress, bindKey)
+	case FormatPvvx:
+		return parsePvvx(data)
+	case FormatPvvxEncrypted:
+		return parsePvvxEncrypted(data, macAddress, bindKey)
+	default:
+		return nil, fmt.Errorf("unknown advertisement format, data length: %d", len(data))
+	}
+}
+
+// parseAtc1441 - mac (big endian), temperature x0.1 °C, humidity %, battery %, battery mV, counter, all big endian
+func parseAtc1441(data []byte) (*SensorData, error) {
+	if len(data) != atc1441Length {
+		return nil, fmt.Errorf("invalid atc1441 data length: %d", len(data))
+	}
+	return &SensorData{
+		Format:      FormatAtc1441,
+		MacAddress:  net.HardwareAddr(data[0:6]).String(),
+		Temperature: float64(int16(binary.BigEndian.Uint16(data[6:8]))) / 10.0,
+		Humidity:    float64(data[8]),
+		Battery:     data[9],
+		Voltage:     binary.BigEndian.Uint16(data[10:12]),
+		HasVoltage:  true,
+		Counter:     data[12],
+	}, nil
+}
+
+// parseAtc1441Encrypted - counter, encrypted temperature (x0.5 °C - 40), humidity (x0.5 %) and battery (bits 0-6) %, mic
+func parseAtc1441Encrypted(data []byte, macAddress string, bindKey []byte) (*SensorData, error) {
+	if len(data) != atc1441EncryptedLength {
+		return nil, fmt.Errorf("invalid encrypted atc1441 data length: %d", len(data))
+	}
+	decrypted, err := decrypt(data, macAddress, bindKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SensorData{
+		Format:      FormatAtc1441Encrypted,
+		Temperature: float64(decrypted[0])/2.0 - 40.0,
+		Humidity:    float64(decrypted[1]) / 2.0,
+		Battery:     decrypted[2] & 0x7f,
+		Counter:     data[0],
+	}, nil
+}
+
+// parsePvvx - mac (little endian), temperature x0.01 °C, humidity x0.01 %, battery mV, battery %, counter, flags, all little endian
+func parsePvvx(data []byte) (*SensorData, error) {
+	if len(data) != pvvxLength {
+		return nil, fmt.Errorf("invalid pvvx data length: %d", len(data))
+	}
+	mac := make(net.HardwareAddr, 6)
+	for i := range mac {
+		mac[i] = data[5-i]
+	}
+	return &SensorData{
+		Format:      FormatPvvx,
+		MacAddress:  mac.String(),
+		Temperature: float64(int16(binary.LittleEndian.Uint16(data[6:8]))) / 100.0,
+		Humidity:    float64(binary.LittleEndian.Uint16(data[8:10])) / 100.0,
+		Voltage:     binary.LittleEndian.Uint16(data[10:12]),
+		HasVoltage:  true,
+		Battery:     data[12],
+		Counter:     data[13],
+	}, nil
+}
+
+// parsePvvxEncrypted - counter, encrypted temperature x0.01 °C, humidity x0.01 %, battery % and flags, mic
+func parsePvvxEncrypted(data []byte, macAddress string, bindKey []byte) (*SensorData, error) {
+	if len(data) != pvvxEncryptedLength {
+		return nil, fmt.Errorf("invalid encrypted pvvx data length: %d", len(data))
+	}
+	decrypted, err := decrypt(data, macAddress, bindKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SensorData{
+		Format:      FormatPvvxEncrypted,
+		Temperature: float64(int16(binary.LittleEndian.Uint16(decrypted[0:2]))) / 100.0,
+		Humidity:    float64(binary.LittleEndian.Uint16(decrypted[2:4])) / 100.0,
+		Battery:     decrypted[4],
+		Counter:     data[0],
+	}, nil
+}
@@ -0,0 +1,119 @@
+package atc
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		bindKey     string
+		expected    *SensorData
+		expectedErr string
+	}{
+		{
+			name: "atc1441",
+			data: "a4c138abcdef00eb3c5a0b8c2a",
+			expected: &SensorData{
+				Format:      FormatAtc1441,
+				MacAddress:  "a4:c1:38:ab:cd:ef",
+				Temperature: 23.5,
+				Humidity:    60,
+				Battery:     90,
+				Voltage:     2956,
+				HasVoltage:  true,
+				Counter:     42,
+			},
+		},
+		{
+			name: "atc1441 negative temperature",
+			data: "a4c138abcdefffce3c5a0b8c2b",
+			expected: &SensorData{
+				Format:      FormatAtc1441,
+				MacAddress:  "a4:c1:38:ab:cd:ef",
+				Temperature: -5,
+				Humidity:    60,
+				Battery:     90,
+				Voltage:     2956,
+				HasVoltage:  true,
+				Counter:     43,
+			},
+		},
+		{
+			name: "pvvx",
+			data: "efcdab38c1a42e094c138c0b5a2a05",
+			expected: &SensorData{
+				Format:      FormatPvvx,
+				MacAddress:  "a4:c1:38:ab:cd:ef",
+				Temperature: 23.5,
+				Humidity:    49.4,
+				Battery:     90,
+				Voltage:     2956,
+				HasVoltage:  true,
+				Counter:     42,
+			},
+		},
+		{
+			name:    "pvvx encrypted",
+			data:    "2a504fe4ed89f7fdf45481",
+			bindKey: "b9ea895fac7eea6d30532432a516f3a3",
+			expected: &SensorData{
+				Format:      FormatPvvxEncrypted,
+				Temperature: 23.5,
+				Humidity:    49.4,
+				Battery:     90,
+				Counter:     42,
+			},
+		},
+		{
+			name:    "atc1441 encrypted",
+			data:    "2ac5ceff45d4402e",
+			bindKey: "b9ea895fac7eea6d30532432a516f3a3",
+			expected: &SensorData{
+				Format:      FormatAtc1441Encrypted,
+				Temperature: 23.5,
+				Humidity:    42.5,
+				Battery:     90,
+				Counter:     42,
+			},
+		},
+		{
+			name:        "encrypted without bind key",
+			data:        "2a504fe4ed89f7fdf45481",
+			expectedErr: "bind key required",
+		},
+		{
+			name:        "encrypted with wrong bind key",
+			data:        "2a504fe4ed89f7fdf45481",
+			bindKey:     "00000000000000000000000000000000",
+			expectedErr: "failed to decrypt data: AESCCM: Message authentication failed",
+		},
+		{
+			name:        "unknown format",
+			data:        "a4c138abcdef",
+			expectedErr: "unknown advertisement format, data length: 6",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := hex.DecodeString(tt.data)
+			assert.NoError(t, err)
+
+			bindKey, err := hex.DecodeString(tt.bindKey)
+			assert.NoError(t, err)
+
+			actual, err := Parse(DetectFormat(data), data, "a4:c1:38:ab:cd:ef", bindKey)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, actual)
+			assert.Equal(t, tt.data, hex.EncodeToString(data), "the data is not modified")
+		})
+	}
+}
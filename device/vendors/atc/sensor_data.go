@@ -0,0 +1,14 @@
+package atc
+
+type SensorData struct {
+	Format Format
+	// MacAddress is set by the unencrypted formats which carry the mac address of the sensor.
+	MacAddress  string
+	Temperature float64
+	Humidity    float64
+	Battery     byte
+	// Voltage is the battery voltage in millivolts, the encrypted formats do not carry it.
+	Voltage    uint16
+	HasVoltage bool
+	Counter    uint8
+}
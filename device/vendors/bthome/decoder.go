@@ -0,0 +1,33 @@
+package bthome
+
+import (
+	"fmt"
+)
+
+// Decoder parses the advertisements of a single device, decrypting them with its bind key and rejecting replayed counters.
+type Decoder struct {
+	macAddress   string
+	bindKey      []byte
+	counterGuard CounterGuard
+}
+
+func NewDecoder(macAddress string, bindKey []byte) *Decoder {
+	return &Decoder{
+		macAddress: macAddress,
+		bindKey:    bindKey,
+	}
+}
+
+func (d *Decoder) Decode(data []byte) (*SensorData, error) {
+	sensorData, err := Parse(data, d.macAddress, d.bindKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if sensorData.CapabilityFlags.Encryption {
+		if err := d.counterGuard.Check(sensorData.EncryptionCounter); err != nil {
+			return nil, fmt.Errorf("bthome: %w", err)
+		}
+	}
+	return sensorData, nil
+}
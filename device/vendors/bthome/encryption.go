@@ -0,0 +1,110 @@
+package bthome
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pschlump/AesCCM"
+)
+
+const (
+	encryptionCounterLength = 4
+	encryptionMicLength     = 4
+	// rebootCounterLimit is the highest counter a device which restarted counting after a reboot may send
+	rebootCounterLimit = 256
+	// rebootSilence is how long no advertisement has to be accepted before a restarted counter is accepted
+	rebootSilence = time.Minute
+)
+
+var (
+	ErrBindKeyRequired   = errors.New("bind key required")
+	ErrReplayedCounter   = errors.New("replayed encryption counter")
+	serviceDataUuidBytes = []byte{0xD2, 0xFC}
+)
+
+// decrypt - Decrypts the payload of an encrypted bthome advertisement, returning the plain objects and the encryption counter
+// https://bthome.io/encryption/
+func decrypt(data []byte, macAddress string, bindKey []byte) ([]byte, uint32, error) {
+	if len(bindKey) == 0 {
+		return nil, 0, ErrBindKeyRequired
+	}
+
+	if len(data) < 1+encryptionCounterLength+encryptionMicLength {
+		return nil, 0, fmt.Errorf("invalid encrypted data length: %d", len(data))
+	}
+
+	mac, err := net.ParseMAC(macAddress)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid mac address: %w", err)
+	}
+	if len(mac) != 6 {
+		return nil, 0, fmt.Errorf("invalid mac address length: %d", len(mac))
+	}
+
+	counterOffset := len(data) - encryptionCounterLength - encryptionMicLength
+	counter := data[counterOffset : counterOffset+encryptionCounterLength]
+
+	var ciphertext []byte
+	ciphertext = append(ciphertext, data[1:counterOffset]...)                        // payload
+	ciphertext = append(ciphertext, data[counterOffset+encryptionCounterLength:]...) // mic
+
+	var nonce []byte
+	nonce = append(nonce, mac...)                  // mac
+	nonce = append(nonce, serviceDataUuidBytes...) // uuid
+	nonce = append(nonce, data[0])                 // device data
+	nonce = append(nonce, counter...)              // counter
+
+	aesCipher, err := aes.NewCipher(bindKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to initializes aes cipher: %w", err)
+	}
+
+	ccm, err := aesccm.NewCCM(aesCipher, encryptionMicLength, len(nonce))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to initialize ccm cipher: %w", err)
+	}
+
+	decrypted, err := ccm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decrypt data: %w", err)
+	}
+
+	return decrypted, binary.LittleEndian.Uint32(counter), nil
+}
+
+// CounterGuard rejects encrypted advertisements whose counter did not increase since the last accepted one. The device
+// restarts counting after a reboot, so a counter below rebootCounterLimit is accepted once no advertisement has been
+// accepted for rebootSilence, a replay of an older advertisement is rejected as long as the device keeps advertising.
+type CounterGuard struct {
+	lock       sync.Mutex
+	seen       bool
+	counter    uint32
+	acceptedAt time.Time
+}
+
+func (cg *CounterGuard) Check(counter uint32) error {
+	return cg.check(counter, time.Now())
+}
+
+func (cg *CounterGuard) check(counter uint32, now time.Time) error {
+	cg.lock.Lock()
+	defer cg.lock.Unlock()
+
+	if cg.seen && counter <= cg.counter && !cg.rebooted(counter, now) {
+		return fmt.Errorf("%w: %d, last accepted: %d", ErrReplayedCounter, counter, cg.counter)
+	}
+	cg.seen = true
+	cg.counter = counter
+	cg.acceptedAt = now
+	return nil
+}
+
+// rebooted reports whether counter is the restarted counter of a rebooted device.
+func (cg *CounterGuard) rebooted(counter uint32, now time.Time) bool {
+	return counter < rebootCounterLimit && cg.counter >= rebootCounterLimit && now.Sub(cg.acceptedAt) >= rebootSilence
+}
@@ -0,0 +1,22 @@
+package bthome
+
+import (
+	"strconv"
+)
+
+type Measurement struct {
+	ObjectId byte    `json:"objectId"`
+	Type     string  `json:"type"`
+	Unit     string  `json:"unit,omitempty"`
+	Index    int     `json:"index"`
+	Value    float64 `json:"value"`
+	Text     string  `json:"text,omitempty"`
+}
+
+// FormattedValue returns the text of text, raw and event measurements, otherwise the numeric value.
+func (m Measurement) FormattedValue() string {
+	if m.Text != "" {
+		return m.Text
+	}
+	return strconv.FormatFloat(m.Value, 'f', -1, 64)
+}
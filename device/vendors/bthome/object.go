@@ -0,0 +1,167 @@
+package bthome
+
+type objectKind byte
+
+const (
+	objectKindSensor objectKind = iota
+	objectKindBinarySensor
+	objectKindButton
+	objectKindDimmer
+	objectKindText
+	objectKindRaw
+)
+
+// objectDefinition describes how an object id is encoded, variable length objects have a size of 0 and are prefixed by their length.
+type objectDefinition struct {
+	kind     objectKind
+	name     string
+	unit     string
+	size     int
+	signed   bool
+	factor   float64
+	decimals int
+}
+
+func sensor(name, unit string, size int, signed bool, factor float64, decimals int) objectDefinition {
+	return objectDefinition{
+		kind:     objectKindSensor,
+		name:     name,
+		unit:     unit,
+		size:     size,
+		signed:   signed,
+		factor:   factor,
+		decimals: decimals,
+	}
+}
+
+func binarySensor(name string) objectDefinition {
+	return objectDefinition{
+		kind:   objectKindBinarySensor,
+		name:   name,
+		size:   1,
+		factor: 1,
+	}
+}
+
+const (
+	packetIdObjectId         = 0x00
+	deviceTypeIdObjectId     = 0xF0
+	firmwareVersion4ObjectId = 0xF1
+	firmwareVersion3ObjectId = 0xF2
+)
+
+// objects - https://bthome.io/format/
+var objects = map[byte]objectDefinition{
+	// Sensor data
+	0x01: sensor("Battery", "%", 1, false, 1, 0),
+	0x02: sensor("Temperature", "°C", 2, true, 0.01, 2),
+	0x03: sensor("Humidity", "%", 2, false, 0.01, 2),
+	0x04: sensor("Pressure", "hPa", 3, false, 0.01, 2),
+	0x05: sensor("Illuminance", "lx", 3, false, 0.01, 2),
+	0x06: sensor("Mass", "kg", 2, false, 0.01, 2),
+	0x07: sensor("Mass", "lb", 2, false, 0.01, 2),
+	0x08: sensor("DewPoint", "°C", 2, true, 0.01, 2),
+	0x09: sensor("Count", "", 1, false, 1, 0),
+	0x0A: sensor("Energy", "kWh", 3, false, 0.001, 3),
+	0x0B: sensor("Power", "W", 3, false, 0.01, 2),
+	0x0C: sensor("Voltage", "V", 2, false, 0.001, 3),
+	0x0D: sensor("PM2.5", "µg/m³", 2, false, 1, 0),
+	0x0E: sensor("PM10", "µg/m³", 2, false, 1, 0),
+	0x12: sensor("CO2", "ppm", 2, false, 1, 0),
+	0x13: sensor("TVOC", "µg/m³", 2, false, 1, 0),
+	0x14: sensor("Moisture", "%", 2, false, 0.01, 2),
+	0x2E: sensor("Humidity", "%", 1, false, 1, 0),
+	0x2F: sensor("Moisture", "%", 1, false, 1, 0),
+	0x3D: sensor("Count", "", 2, false, 1, 0),
+	0x3E: sensor("Count", "", 4, false, 1, 0),
+	0x3F: sensor("Rotation", "°", 2, true, 0.1, 1),
+	0x40: sensor("Distance", "mm", 2, false, 1, 0),
+	0x41: sensor("Distance", "m", 2, false, 0.1, 1),
+	0x42: sensor("Duration", "s", 3, false, 0.001, 3),
+	0x43: sensor("Current", "A", 2, false, 0.001, 3),
+	0x44: sensor("Speed", "m/s", 2, false, 0.01, 2),
+	0x45: sensor("Temperature", "°C", 2, true, 0.1, 1),
+	0x46: sensor("UVIndex", "", 1, false, 0.1, 1),
+	0x47: sensor("Volume", "L", 2, false, 0.1, 1),
+	0x48: sensor("Volume", "mL", 2, false, 1, 0),
+	0x49: sensor("VolumeFlowRate", "m³/h", 2, false, 0.001, 3),
+	0x4A: sensor("Voltage", "V", 2, false, 0.1, 1),
+	0x4B: sensor("Gas", "m³", 3, false, 0.001, 3),
+	0x4C: sensor("Gas", "m³", 4, false, 0.001, 3),
+	0x4D: sensor("Energy", "kWh", 4, false, 0.001, 3),
+	0x4E: sensor("Volume", "L", 4, false, 0.001, 3),
+	0x4F: sensor("Water", "L", 4, false, 0.001, 3),
+	0x50: sensor("Timestamp", "s", 4, false, 1, 0),
+	0x51: sensor("Acceleration", "m/s²", 2, false, 0.001, 3),
+	0x52: sensor("Gyroscope", "°/s", 2, false, 0.001, 3),
+	0x55: sensor("VolumeStorage", "L", 4, false, 0.001, 3),
+	0x56: sensor("Conductivity", "µS/cm", 2, false, 1, 0),
+	0x57: sensor("Temperature", "°C", 1, true, 1, 0),
+	0x58: sensor("Temperature", "°C", 1, true, 0.35, 2),
+	0x59: sensor("Count", "", 1, true, 1, 0),
+	0x5A: sensor("Count", "", 2, true, 1, 0),
+	0x5B: sensor("Count", "", 4, true, 1, 0),
+	0x5C: sensor("Power", "W", 4, true, 0.01, 2),
+	0x5D: sensor("Current", "A", 2, true, 0.001, 3),
+	0x5E: sensor("Direction", "°", 2, false, 0.01, 2),
+	0x5F: sensor("Precipitation", "mm", 2, false, 0.1, 1),
+	0x60: sensor("Channel", "", 1, false, 1, 0),
+	0x61: sensor("RotationalSpeed", "rpm", 2, false, 1, 0),
+
+	// Binary sensor data
+	0x0F: binarySensor("Generic"),
+	0x10: binarySensor("PowerOn"),
+	0x11: binarySensor("Opening"),
+	0x15: binarySensor("BatteryLow"),
+	0x16: binarySensor("BatteryCharging"),
+	0x17: binarySensor("CarbonMonoxide"),
+	0x18: binarySensor("Cold"),
+	0x19: binarySensor("Connectivity"),
+	0x1A: binarySensor("Door"),
+	0x1B: binarySensor("GarageDoor"),
+	0x1C: binarySensor("GasDetected"),
+	0x1D: binarySensor("Heat"),
+	0x1E: binarySensor("Light"),
+	0x1F: binarySensor("Lock"),
+	0x20: binarySensor("MoistureDetected"),
+	0x21: binarySensor("Motion"),
+	0x22: binarySensor("Moving"),
+	0x23: binarySensor("Occupancy"),
+	0x24: binarySensor("Plug"),
+	0x25: binarySensor("Presence"),
+	0x26: binarySensor("Problem"),
+	0x27: binarySensor("Running"),
+	0x28: binarySensor("Safety"),
+	0x29: binarySensor("Smoke"),
+	0x2A: binarySensor("Sound"),
+	0x2B: binarySensor("Tamper"),
+	0x2C: binarySensor("Vibration"),
+	0x2D: binarySensor("Window"),
+
+	// Events
+	0x3A: {kind: objectKindButton, name: "Button", size: 1, factor: 1},
+	0x3C: {kind: objectKindDimmer, name: "Dimmer", size: 2, factor: 1},
+
+	// Variable length data
+	0x53: {kind: objectKindText, name: "Text"},
+	0x54: {kind: objectKindRaw, name: "Raw"},
+}
+
+// buttonEvents - https://bthome.io/format/#button
+var buttonEvents = map[byte]string{
+	0x00: "none",
+	0x01: "press",
+	0x02: "double_press",
+	0x03: "triple_press",
+	0x04: "long_press",
+	0x05: "long_double_press",
+	0x06: "long_triple_press",
+	0x80: "hold_press",
+}
+
+// dimmerEvents - https://bthome.io/format/#dimmer
+var dimmerEvents = map[byte]string{
+	0x00: "none",
+	0x01: "rotate_left",
+	0x02: "rotate_right",
+}
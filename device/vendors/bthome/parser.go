@@ -2,10 +2,11 @@ package bthome
 
 import (
 	"bytes"
-	"encoding/binary"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"math"
 )
 
 const (
@@ -14,9 +15,9 @@ const (
 	versionFlag            = (1 << 3) - 1
 )
 
-// Parse - Parses bthome encoded data
+// Parse - Parses bthome encoded data, encrypted data is decrypted using the bind key and the mac address of the advertiser
 // https://bthome.io/format/#sensor-data
-func Parse(data []byte) (*SensorData, error) {
+func Parse(data []byte, macAddress string, bindKey []byte) (*SensorData, error) {
 	if len(data) < 3 {
 		return nil, fmt.Errorf("bthome: invalid data length: %d", len(data))
 	}
@@ -31,19 +32,37 @@ func Parse(data []byte) (*SensorData, error) {
 		return nil, fmt.Errorf("bthome: unsupported bthome version: %d", capabilityFlags.Version)
 	}
 
-	var sensorData SensorData
-	sensorData.CapabilityFlags = capabilityFlags
+	sensorData := SensorData{
+		CapabilityFlags: capabilityFlags,
+	}
+	if capabilityFlags.Encryption {
+		decrypted, counter, err := decrypt(data, macAddress, bindKey)
+		if err != nil {
+			return nil, fmt.Errorf("bthome: failed to read encrypted payload: %w", err)
+		}
+		sensorData.EncryptionCounter = counter
+		r = bytes.NewReader(decrypted)
+	}
+
+	if err := readObjects(r, &sensorData); err != nil {
+		return nil, err
+	}
+	return &sensorData, nil
+}
+
+func readObjects(r *bytes.Reader, sensorData *SensorData) error {
+	indexByType := make(map[string]int)
 	for {
-		propertyType, err := r.ReadByte()
+		objectId, err := r.ReadByte()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
-				break
+				return nil
 			}
-			return nil, fmt.Errorf("bthome: failed to read property type: %d", propertyType)
+			return fmt.Errorf("bthome: failed to read property type: %d", objectId)
 		}
 
-		switch propertyType {
-		case 0x00: // packet id
+		switch objectId {
+		case packetIdObjectId:
 			/**
 			* https://bthome.io/format/#misc-data
 			* The packet id is optional and can be used to filter duplicate data.
@@ -55,63 +74,107 @@ func Parse(data []byte) (*SensorData, error) {
 			**/
 			packetId, err := r.ReadByte()
 			if err != nil {
-				return nil, err
+				return fmt.Errorf("bthome: failed to read packet id: %w", err)
 			}
+			sensorData.HasPacketId = true
 			sensorData.PacketId = packetId
-		case 0x01: // battery %
-			battery, err := r.ReadByte()
+			continue
+		case deviceTypeIdObjectId:
+			deviceTypeId, err := readUnsigned(r, 2)
 			if err != nil {
-				return nil, fmt.Errorf("bthome: failed to read battery: %w", err)
+				return fmt.Errorf("bthome: failed to read device type id: %w", err)
 			}
-			sensorData.BatteryPercent = battery
-		case 0x05: // illuminance lux
-			illuminance, err := readIlluminance(r)
-			if err != nil {
-				return nil, fmt.Errorf("bthome: failed to read illuminance: %w", err)
+			sensorData.DeviceTypeId = uint16(deviceTypeId)
+			continue
+		case firmwareVersion4ObjectId, firmwareVersion3ObjectId:
+			size := 4
+			if objectId == firmwareVersion3ObjectId {
+				size = 3
 			}
-			sensorData.IlluminanceLux = illuminance
-		case 0x21: // motion state
-			motion, err := r.ReadByte()
-			if err != nil {
-				return nil, fmt.Errorf("bthome: failed to read motion state: %w", err)
+			version := make([]byte, size)
+			if _, err := io.ReadFull(r, version); err != nil {
+				return fmt.Errorf("bthome: failed to read firmware version: %w", err)
 			}
-			sensorData.MotionState = motion
-		case 0x2D: // window state
-			windowState, err := r.ReadByte()
+			sensorData.FirmwareVersion = formatFirmwareVersion(version)
+			continue
+		}
+
+		definition, exists := objects[objectId]
+		if !exists {
+			return fmt.Errorf("bthome: unknown property type: %d", objectId)
+		}
+
+		measurement, err := readMeasurement(r, objectId, definition)
+		if err != nil {
+			return fmt.Errorf("bthome: failed to read %s: %w", definition.name, err)
+		}
+		measurement.Index = indexByType[measurement.Type]
+		indexByType[measurement.Type]++
+		sensorData.Measurements = append(sensorData.Measurements, measurement)
+	}
+}
+
+func readMeasurement(r *bytes.Reader, objectId byte, definition objectDefinition) (Measurement, error) {
+	measurement := Measurement{
+		ObjectId: objectId,
+		Type:     definition.name,
+		Unit:     definition.unit,
+	}
+
+	switch definition.kind {
+	case objectKindSensor, objectKindBinarySensor:
+		var value float64
+		if definition.signed {
+			signedValue, err := readSigned(r, definition.size)
 			if err != nil {
-				return nil, fmt.Errorf("bthome: failed to read window state: %w", err)
+				return measurement, err
 			}
-			sensorData.WindowState = windowState
-		case 0x2E: // humidity %
-			humidity, err := r.ReadByte()
+			value = float64(signedValue)
+		} else {
+			unsignedValue, err := readUnsigned(r, definition.size)
 			if err != nil {
-				return nil, fmt.Errorf("bthome: failed to read humidity: %w", err)
-			}
-			sensorData.HumidityPercent = humidity
-		case 0x3A: // button
-			var buttonEvent uint16
-			if err := binary.Read(r, binary.BigEndian, &buttonEvent); err != nil {
-				return nil, fmt.Errorf("bthome: failed to read button event: %w", err)
-			}
-			sensorData.ButtonEvent = buttonEvent
-		case 0x3F: // rotation °
-			var rotation int16
-			if err := binary.Read(r, binary.BigEndian, &rotation); err != nil {
-				return nil, fmt.Errorf("bthome: failed to read rotation: %w", err)
-			}
-			sensorData.RotationDegrees = float32(rotation) / 10.
-		case 0x45: // temperature °C
-			var temperature int16
-			if err := binary.Read(r, binary.BigEndian, &temperature); err != nil {
-				return nil, fmt.Errorf("bthome: failed to read temperature: %w", err)
+				return measurement, err
 			}
-			sensorData.TemperatureCelsius = float32(temperature) / 10.
-		default:
-			return nil, fmt.Errorf("bthome: unknown property type: %d", propertyType)
+			value = float64(unsignedValue)
 		}
+		measurement.Value = round(value*definition.factor, definition.decimals)
+	case objectKindButton:
+		event, err := r.ReadByte()
+		if err != nil {
+			return measurement, err
+		}
+		measurement.Value = float64(event)
+		measurement.Text = eventName(buttonEvents, event)
+	case objectKindDimmer:
+		event, err := r.ReadByte()
+		if err != nil {
+			return measurement, err
+		}
+		steps, err := r.ReadByte()
+		if err != nil {
+			return measurement, err
+		}
+		measurement.Value = float64(steps)
+		measurement.Text = eventName(dimmerEvents, event)
+	case objectKindText, objectKindRaw:
+		length, err := r.ReadByte()
+		if err != nil {
+			return measurement, err
+		}
+		value := make([]byte, length)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return measurement, err
+		}
+		measurement.Value = float64(length)
+		if definition.kind == objectKindText {
+			measurement.Text = string(value)
+		} else {
+			measurement.Text = hex.EncodeToString(value)
+		}
+	default:
+		return measurement, fmt.Errorf("unsupported object kind: %d", definition.kind)
 	}
-
-	return &sensorData, nil
+	return measurement, nil
 }
 
 func readCapabilities(capabilityFlags byte) CapabilityFlags {
@@ -125,19 +188,50 @@ func readCapabilities(capabilityFlags byte) CapabilityFlags {
 	}
 }
 
-func readIlluminance(r io.Reader) (float32, error) {
-	var component12 uint16
-	err := binary.Read(r, binary.BigEndian, &component12)
-	if err != nil {
+// readUnsigned reads a little endian unsigned integer of 1 to 4 bytes.
+func readUnsigned(r io.Reader, size int) (uint32, error) {
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r, buf); err != nil {
 		return 0, err
 	}
 
-	var component3 byte
-	err = binary.Read(r, binary.BigEndian, &component3)
+	var value uint32
+	for i := size - 1; i >= 0; i-- {
+		value = value<<8 | uint32(buf[i])
+	}
+	return value, nil
+}
+
+// readSigned reads a little endian two's complement integer of 1 to 4 bytes.
+func readSigned(r io.Reader, size int) (int32, error) {
+	value, err := readUnsigned(r, size)
 	if err != nil {
 		return 0, err
 	}
 
-	illuminance := uint32(component12)<<8 | uint32(component3)
-	return float32(illuminance) / 100., nil
+	shift := 32 - size*8
+	return int32(value<<shift) >> shift, nil
+}
+
+func round(value float64, decimals int) float64 {
+	pow := math.Pow10(decimals)
+	return math.Round(value*pow) / pow
+}
+
+func eventName(events map[byte]string, event byte) string {
+	if name, exists := events[event]; exists {
+		return name
+	}
+	return fmt.Sprintf("unknown_%d", event)
+}
+
+func formatFirmwareVersion(version []byte) string {
+	var buf bytes.Buffer
+	for i := len(version) - 1; i >= 0; i-- {
+		if buf.Len() != 0 {
+			buf.WriteByte('.')
+		}
+		_, _ = fmt.Fprintf(&buf, "%d", version[i])
+	}
+	return buf.String()
 }
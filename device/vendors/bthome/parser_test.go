@@ -2,14 +2,18 @@ package bthome
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParse(t *testing.T) {
 	tests := []struct {
 		name          string
 		data          []byte
+		macAddress    string
+		bindKey       []byte
 		expected      *SensorData
 		expectedError string
 	}{
@@ -32,7 +36,13 @@ func TestParse(t *testing.T) {
 			expectedError: "bthome: unknown property type: 152",
 		},
 		{
-			name: "packet id 43, battery 100%, illuminance 144192.00 lux, window 0, button event 0, rotation 0.0",
+			name:          "truncated property",
+			data:          []byte{0x44, 0x00, 0x01, 0x02, 0x00},
+			expected:      nil,
+			expectedError: "bthome: failed to read Temperature: unexpected EOF",
+		},
+		{
+			name: "packet id 43, battery 100%, illuminance 15.00 lux, window 0, rotation 0.0",
 			data: []byte{0x44, 0x00, 0x2b, 0x01, 0x64, 0x05, 0xdc, 0x05, 0x00, 0x2d, 0x00, 0x3f, 0x00, 0x00},
 			expected: &SensorData{
 				CapabilityFlags: CapabilityFlags{
@@ -40,40 +50,130 @@ func TestParse(t *testing.T) {
 					TriggerBasedDevice: true,
 					Version:            2,
 				},
-				PacketId:           43,
-				BatteryPercent:     100,
-				IlluminanceLux:     144192.00,
-				MotionState:        0,
-				WindowState:        0,
-				HumidityPercent:    0,
-				ButtonEvent:        0,
-				RotationDegrees:    0,
-				TemperatureCelsius: 0,
+				HasPacketId: true,
+				PacketId:    43,
+				Measurements: []Measurement{
+					{ObjectId: 0x01, Type: "Battery", Unit: "%", Value: 100},
+					{ObjectId: 0x05, Type: "Illuminance", Unit: "lx", Value: 15},
+					{ObjectId: 0x2d, Type: "Window", Value: 0},
+					{ObjectId: 0x3f, Type: "Rotation", Unit: "°", Value: 0},
+				},
 			},
 			expectedError: "",
 		},
 		{
-			name: "packet id 44, battery 100%, illuminance 13120.00 lux, window 1, button event 0, rotation 0.0",
-			data: []byte{0x44, 0x00, 0x2c, 0x01, 0x64, 0x05, 0x14, 0x05, 0x00, 0x2d, 0x01, 0x3f, 0x00, 0x00},
+			name: "packet id 44, battery 100%, illuminance 13.00 lux, window 1, rotation -12.5",
+			data: []byte{0x44, 0x00, 0x2c, 0x01, 0x64, 0x05, 0x14, 0x05, 0x00, 0x2d, 0x01, 0x3f, 0x83, 0xff},
 			expected: &SensorData{
 				CapabilityFlags: CapabilityFlags{
 					Encryption:         false,
 					TriggerBasedDevice: true,
 					Version:            2,
 				},
-				PacketId:        44,
-				BatteryPercent:  100,
-				IlluminanceLux:  13120.00,
-				WindowState:     1,
-				ButtonEvent:     0,
-				RotationDegrees: 0,
+				HasPacketId: true,
+				PacketId:    44,
+				Measurements: []Measurement{
+					{ObjectId: 0x01, Type: "Battery", Unit: "%", Value: 100},
+					{ObjectId: 0x05, Type: "Illuminance", Unit: "lx", Value: 13},
+					{ObjectId: 0x2d, Type: "Window", Value: 1},
+					{ObjectId: 0x3f, Type: "Rotation", Unit: "°", Value: -12.5},
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name: "temperature -5.55, humidity 50.55%, pressure 1008.83 hPa, co2 1250 ppm, voltage 3.074 V",
+			data: []byte{0x40, 0x02, 0xd5, 0xfd, 0x03, 0xbf, 0x13, 0x04, 0x13, 0x8a, 0x01, 0x12, 0xe2, 0x04, 0x0c, 0x02, 0x0c},
+			expected: &SensorData{
+				CapabilityFlags: CapabilityFlags{
+					Version: 2,
+				},
+				Measurements: []Measurement{
+					{ObjectId: 0x02, Type: "Temperature", Unit: "°C", Value: -5.55},
+					{ObjectId: 0x03, Type: "Humidity", Unit: "%", Value: 50.55},
+					{ObjectId: 0x04, Type: "Pressure", Unit: "hPa", Value: 1008.83},
+					{ObjectId: 0x12, Type: "CO2", Unit: "ppm", Value: 1250},
+					{ObjectId: 0x0c, Type: "Voltage", Unit: "V", Value: 3.074},
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name: "repeated buttons and temperatures",
+			data: []byte{0x44, 0x3a, 0x01, 0x3a, 0x00, 0x3a, 0x04, 0x3a, 0x80, 0x45, 0x11, 0x01, 0x58, 0x40},
+			expected: &SensorData{
+				CapabilityFlags: CapabilityFlags{
+					TriggerBasedDevice: true,
+					Version:            2,
+				},
+				Measurements: []Measurement{
+					{ObjectId: 0x3a, Type: "Button", Index: 0, Value: 1, Text: "press"},
+					{ObjectId: 0x3a, Type: "Button", Index: 1, Value: 0, Text: "none"},
+					{ObjectId: 0x3a, Type: "Button", Index: 2, Value: 4, Text: "long_press"},
+					{ObjectId: 0x3a, Type: "Button", Index: 3, Value: 128, Text: "hold_press"},
+					{ObjectId: 0x45, Type: "Temperature", Unit: "°C", Index: 0, Value: 27.3},
+					{ObjectId: 0x58, Type: "Temperature", Unit: "°C", Index: 1, Value: 22.4},
+				},
 			},
 			expectedError: "",
 		},
+		{
+			name: "dimmer, text, raw, energy, power, count and firmware version",
+			data: []byte{0x40, 0x3c, 0x02, 0x03, 0x53, 0x02, 0x68, 0x69, 0x54, 0x02, 0xbe, 0xef, 0x4d, 0x12, 0x13, 0x8a, 0x14, 0x5c, 0x02, 0xfc, 0xff, 0xff, 0x5b, 0x9c, 0xff, 0xff, 0xff, 0xf1, 0x00, 0x01, 0x02, 0x04, 0xf0, 0x01, 0x00},
+			expected: &SensorData{
+				CapabilityFlags: CapabilityFlags{
+					Version: 2,
+				},
+				DeviceTypeId:    1,
+				FirmwareVersion: "4.2.1.0",
+				Measurements: []Measurement{
+					{ObjectId: 0x3c, Type: "Dimmer", Value: 3, Text: "rotate_right"},
+					{ObjectId: 0x53, Type: "Text", Value: 2, Text: "hi"},
+					{ObjectId: 0x54, Type: "Raw", Value: 2, Text: "beef"},
+					{ObjectId: 0x4d, Type: "Energy", Unit: "kWh", Value: 344593.17},
+					{ObjectId: 0x5c, Type: "Power", Unit: "W", Value: -10.22},
+					{ObjectId: 0x5b, Type: "Count", Value: -100},
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name:       "encrypted temperature 25.06, humidity 50.55%",
+			data:       []byte{0x41, 0xa4, 0x72, 0x66, 0xc9, 0x5f, 0x73, 0x00, 0x11, 0x22, 0x33, 0x78, 0x23, 0x72, 0x14},
+			macAddress: "54:48:E6:8F:80:A5",
+			bindKey:    []byte{0x23, 0x1d, 0x39, 0xc1, 0xd7, 0xcc, 0x1a, 0xb1, 0xae, 0xe2, 0x24, 0xcd, 0x09, 0x6d, 0xb9, 0x32},
+			expected: &SensorData{
+				CapabilityFlags: CapabilityFlags{
+					Encryption: true,
+					Version:    2,
+				},
+				EncryptionCounter: 0x33221100,
+				Measurements: []Measurement{
+					{ObjectId: 0x02, Type: "Temperature", Unit: "°C", Value: 25.06},
+					{ObjectId: 0x03, Type: "Humidity", Unit: "%", Value: 50.55},
+				},
+			},
+			expectedError: "",
+		},
+		{
+			name:          "encrypted without bind key",
+			data:          []byte{0x41, 0xa4, 0x72, 0x66, 0xc9, 0x5f, 0x73, 0x00, 0x11, 0x22, 0x33, 0x78, 0x23, 0x72, 0x14},
+			macAddress:    "54:48:E6:8F:80:A5",
+			expected:      nil,
+			expectedError: "bthome: failed to read encrypted payload: bind key required",
+		},
+		{
+			name:          "encrypted with wrong mac address",
+			data:          []byte{0x41, 0xa4, 0x72, 0x66, 0xc9, 0x5f, 0x73, 0x00, 0x11, 0x22, 0x33, 0x78, 0x23, 0x72, 0x14},
+			macAddress:    "54:48:E6:8F:80:A6",
+			bindKey:       []byte{0x23, 0x1d, 0x39, 0xc1, 0xd7, 0xcc, 0x1a, 0xb1, 0xae, 0xe2, 0x24, 0xcd, 0x09, 0x6d, 0xb9, 0x32},
+			expected:      nil,
+			expectedError: "bthome: failed to read encrypted payload: failed to decrypt data: AESCCM: Message authentication failed",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			actual, err := Parse(test.data)
+			actual, err := Parse(test.data, test.macAddress, test.bindKey)
 			if test.expectedError != "" {
 				assert.EqualError(t, err, test.expectedError)
 			} else {
@@ -83,3 +183,30 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestCounterGuard(t *testing.T) {
+	var counterGuard CounterGuard
+	assert.NoError(t, counterGuard.Check(10))
+	assert.ErrorIs(t, counterGuard.Check(10), ErrReplayedCounter)
+	assert.ErrorIs(t, counterGuard.Check(9), ErrReplayedCounter)
+	assert.NoError(t, counterGuard.Check(11))
+}
+
+func TestCounterGuardReboot(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var counterGuard CounterGuard
+	require.NoError(t, counterGuard.check(5000, now))
+
+	// old advertisements are rejected however far back they were captured
+	assert.ErrorIs(t, counterGuard.check(3000, now.Add(time.Hour)), ErrReplayedCounter)
+	assert.ErrorIs(t, counterGuard.check(255, now.Add(time.Second)), ErrReplayedCounter)
+	assert.NoError(t, counterGuard.check(5001, now.Add(2*time.Second)))
+
+	// a low counter is taken as a reboot only after the device went silent
+	now = now.Add(2 * time.Second)
+	assert.ErrorIs(t, counterGuard.check(1, now.Add(rebootSilence-time.Second)), ErrReplayedCounter)
+	assert.NoError(t, counterGuard.check(2, now.Add(rebootSilence)))
+	assert.ErrorIs(t, counterGuard.check(2, now.Add(rebootSilence)), ErrReplayedCounter)
+	assert.ErrorIs(t, counterGuard.check(1, now.Add(2*rebootSilence)), ErrReplayedCounter)
+	assert.NoError(t, counterGuard.check(3, now.Add(2*rebootSilence)))
+}
@@ -1,14 +1,21 @@
 package bthome
 
 type SensorData struct {
-	CapabilityFlags    CapabilityFlags `json:"capabilityFlags"`
-	PacketId           byte            `json:"packetId,omitempty"`
-	BatteryPercent     byte            `json:"batteryPercent,omitempty"`
-	IlluminanceLux     float32         `json:"illuminanceLux,omitempty"`
-	MotionState        byte            `json:"motionState,omitempty"`
-	WindowState        byte            `json:"windowState,omitempty"`
-	HumidityPercent    byte            `json:"humidityPercent,omitempty"`
-	ButtonEvent        uint16          `json:"buttonEvent,omitempty"`
-	RotationDegrees    float32         `json:"rotationDegrees,omitempty"`
-	TemperatureCelsius float32         `json:"temperatureCelsius,omitempty"`
+	CapabilityFlags   CapabilityFlags `json:"capabilityFlags"`
+	EncryptionCounter uint32          `json:"encryptionCounter,omitempty"`
+	HasPacketId       bool            `json:"hasPacketId,omitempty"`
+	PacketId          byte            `json:"packetId,omitempty"`
+	DeviceTypeId      uint16          `json:"deviceTypeId,omitempty"`
+	FirmwareVersion   string          `json:"firmwareVersion,omitempty"`
+	Measurements      []Measurement   `json:"measurements,omitempty"`
+}
+
+// Find returns the first measurement of the given type.
+func (sd *SensorData) Find(measurementType string) (Measurement, bool) {
+	for _, measurement := range sd.Measurements {
+		if measurement.Type == measurementType {
+			return measurement, true
+		}
+	}
+	return Measurement{}, false
 }
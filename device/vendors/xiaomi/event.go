@@ -3,4 +3,6 @@ package xiaomi
 type Event interface {
 	isMiEvent()
 	EventType() EventType
+	// Measurements returns the values the event carries.
+	Measurements() []Measurement
 }
@@ -8,3 +8,7 @@ func (e *EventBattery) isMiEvent() {}
 func (e *EventBattery) EventType() EventType {
 	return EventTypeBattery
 }
+
+func (e *EventBattery) Measurements() []Measurement {
+	return []Measurement{{Type: "Battery", Unit: "%", Value: float64(e.Battery)}}
+}
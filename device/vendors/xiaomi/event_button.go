@@ -0,0 +1,47 @@
+package xiaomi
+
+type ButtonPress byte
+
+const (
+	ButtonPressSingle ButtonPress = 0
+	ButtonPressDouble ButtonPress = 1
+	ButtonPressLong   ButtonPress = 2
+	ButtonPressTriple ButtonPress = 3
+	ButtonPressRotate ButtonPress = 4
+)
+
+func (bp ButtonPress) String() string {
+	switch bp {
+	case ButtonPressSingle:
+		return "press"
+	case ButtonPressDouble:
+		return "double_press"
+	case ButtonPressLong:
+		return "long_press"
+	case ButtonPressTriple:
+		return "triple_press"
+	case ButtonPressRotate:
+		return "rotate"
+	default:
+		return "unknown"
+	}
+}
+
+// EventButton is sent by the remotes, buttons and dimmers, rotations carry the signed amount of steps as value.
+type EventButton struct {
+	Button byte
+	Value  byte
+	Press  ButtonPress
+}
+
+func (e *EventButton) isMiEvent() {}
+func (e *EventButton) EventType() EventType {
+	return EventTypeButton
+}
+func (e *EventButton) Measurements() []Measurement {
+	measurements := []Measurement{{Type: "Button", Value: float64(e.Button), Text: e.Press.String()}}
+	if e.Press == ButtonPressRotate {
+		measurements = append(measurements, Measurement{Type: "Rotation", Value: float64(int8(e.Value))})
+	}
+	return measurements
+}
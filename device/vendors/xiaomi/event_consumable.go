@@ -0,0 +1,14 @@
+package xiaomi
+
+// EventConsumable reports the remaining percentage of a consumable, e.g. the repellent of a mosquito repeller.
+type EventConsumable struct {
+	Remaining byte
+}
+
+func (e *EventConsumable) isMiEvent() {}
+func (e *EventConsumable) EventType() EventType {
+	return EventTypeConsumable
+}
+func (e *EventConsumable) Measurements() []Measurement {
+	return []Measurement{{Type: "Consumable", Unit: "%", Value: float64(e.Remaining)}}
+}
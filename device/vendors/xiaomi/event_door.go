@@ -0,0 +1,46 @@
+package xiaomi
+
+type DoorState byte
+
+const (
+	DoorStateOpen        DoorState = 0
+	DoorStateClosed      DoorState = 1
+	DoorStateOpenTimeout DoorState = 2
+	DoorStateDeviceReset DoorState = 3
+)
+
+func (ds DoorState) String() string {
+	switch ds {
+	case DoorStateOpen:
+		return "open"
+	case DoorStateClosed:
+		return "closed"
+	case DoorStateOpenTimeout:
+		return "open_timeout"
+	case DoorStateDeviceReset:
+		return "device_reset"
+	default:
+		return "unknown"
+	}
+}
+
+type EventDoor struct {
+	State DoorState
+}
+
+func (e *EventDoor) isMiEvent() {}
+func (e *EventDoor) EventType() EventType {
+	return EventTypeDoor
+}
+
+// Measurements reports the door as open (1) or closed (0) along with its state, a device reset carries no door value.
+func (e *EventDoor) Measurements() []Measurement {
+	measurements := []Measurement{{Type: "DoorState", Text: e.State.String()}}
+	switch e.State {
+	case DoorStateOpen, DoorStateOpenTimeout:
+		measurements = append(measurements, Measurement{Type: "Door", Value: 1})
+	case DoorStateClosed:
+		measurements = append(measurements, Measurement{Type: "Door", Value: 0})
+	}
+	return measurements
+}
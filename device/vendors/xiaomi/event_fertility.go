@@ -8,3 +8,7 @@ func (e *EventFertility) isMiEvent() {}
 func (e *EventFertility) EventType() EventType {
 	return EventTypeFertility
 }
+
+func (e *EventFertility) Measurements() []Measurement {
+	return []Measurement{{Type: "Conductivity", Unit: "µS/cm", Value: float64(e.Fertility)}}
+}
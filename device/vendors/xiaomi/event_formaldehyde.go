@@ -0,0 +1,13 @@
+package xiaomi
+
+type EventFormaldehyde struct {
+	Formaldehyde float64
+}
+
+func (e *EventFormaldehyde) isMiEvent() {}
+func (e *EventFormaldehyde) EventType() EventType {
+	return EventTypeFormaldehyde
+}
+func (e *EventFormaldehyde) Measurements() []Measurement {
+	return []Measurement{{Type: "Formaldehyde", Unit: "mg/m³", Value: e.Formaldehyde}}
+}
@@ -8,3 +8,7 @@ func (e *EventHumidity) isMiEvent() {}
 func (e *EventHumidity) EventType() EventType {
 	return EventTypeHumidity
 }
+
+func (e *EventHumidity) Measurements() []Measurement {
+	return []Measurement{{Type: "Humidity", Unit: "%", Value: e.Humidity}}
+}
@@ -8,3 +8,7 @@ func (e *EventIlluminance) isMiEvent() {}
 func (e *EventIlluminance) EventType() EventType {
 	return EventTypeIlluminance
 }
+
+func (e *EventIlluminance) Measurements() []Measurement {
+	return []Measurement{{Type: "Illuminance", Unit: "lx", Value: float64(e.Illuminance)}}
+}
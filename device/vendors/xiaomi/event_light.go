@@ -0,0 +1,14 @@
+package xiaomi
+
+// EventLight is sent by the door and motion sensors, light is reported when the ambient light is strong.
+type EventLight struct {
+	Light bool
+}
+
+func (e *EventLight) isMiEvent() {}
+func (e *EventLight) EventType() EventType {
+	return EventTypeLight
+}
+func (e *EventLight) Measurements() []Measurement {
+	return []Measurement{{Type: "Light", Value: boolValue(e.Light)}}
+}
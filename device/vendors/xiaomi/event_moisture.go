@@ -8,3 +8,7 @@ func (e *EventMoisture) isMiEvent() {}
 func (e *EventMoisture) EventType() EventType {
 	return EventTypeMoisture
 }
+
+func (e *EventMoisture) Measurements() []Measurement {
+	return []Measurement{{Type: "Moisture", Unit: "%", Value: float64(e.Moisture)}}
+}
@@ -0,0 +1,13 @@
+package xiaomi
+
+type EventMotion struct {
+	Motion bool
+}
+
+func (e *EventMotion) isMiEvent() {}
+func (e *EventMotion) EventType() EventType {
+	return EventTypeMotion
+}
+func (e *EventMotion) Measurements() []Measurement {
+	return []Measurement{{Type: "Motion", Value: boolValue(e.Motion)}}
+}
@@ -0,0 +1,17 @@
+package xiaomi
+
+// EventMotionWithIlluminance is sent by the motion sensors when motion is detected.
+type EventMotionWithIlluminance struct {
+	Illuminance float64
+}
+
+func (e *EventMotionWithIlluminance) isMiEvent() {}
+func (e *EventMotionWithIlluminance) EventType() EventType {
+	return EventTypeMotionWithIlluminance
+}
+func (e *EventMotionWithIlluminance) Measurements() []Measurement {
+	return []Measurement{
+		{Type: "Motion", Value: 1},
+		{Type: "Illuminance", Unit: "lx", Value: e.Illuminance},
+	}
+}
@@ -0,0 +1,17 @@
+package xiaomi
+
+// EventNoMotion is sent by the motion sensors when no motion was detected for the duration in seconds.
+type EventNoMotion struct {
+	Duration uint32
+}
+
+func (e *EventNoMotion) isMiEvent() {}
+func (e *EventNoMotion) EventType() EventType {
+	return EventTypeNoMotion
+}
+func (e *EventNoMotion) Measurements() []Measurement {
+	return []Measurement{
+		{Type: "Motion", Value: 0},
+		{Type: "NoMotionDuration", Unit: "s", Value: float64(e.Duration)},
+	}
+}
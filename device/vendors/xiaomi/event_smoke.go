@@ -0,0 +1,13 @@
+package xiaomi
+
+type EventSmoke struct {
+	Smoke bool
+}
+
+func (e *EventSmoke) isMiEvent() {}
+func (e *EventSmoke) EventType() EventType {
+	return EventTypeSmoke
+}
+func (e *EventSmoke) Measurements() []Measurement {
+	return []Measurement{{Type: "Smoke", Value: boolValue(e.Smoke)}}
+}
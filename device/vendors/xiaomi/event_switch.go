@@ -0,0 +1,13 @@
+package xiaomi
+
+type EventSwitch struct {
+	On bool
+}
+
+func (e *EventSwitch) isMiEvent() {}
+func (e *EventSwitch) EventType() EventType {
+	return EventTypeSwitch
+}
+func (e *EventSwitch) Measurements() []Measurement {
+	return []Measurement{{Type: "Switch", Value: boolValue(e.On)}}
+}
@@ -8,3 +8,7 @@ func (e *EventTemperature) isMiEvent() {}
 func (e *EventTemperature) EventType() EventType {
 	return EventTypeTemperature
 }
+
+func (e *EventTemperature) Measurements() []Measurement {
+	return []Measurement{{Type: "Temperature", Unit: "°C", Value: e.Temperature}}
+}
@@ -9,3 +9,10 @@ func (e *EventTemperatureAndHumidity) isMiEvent() {}
 func (e *EventTemperatureAndHumidity) EventType() EventType {
 	return EventTypeTemperatureAndHumidity
 }
+
+func (e *EventTemperatureAndHumidity) Measurements() []Measurement {
+	return []Measurement{
+		{Type: "Temperature", Unit: "°C", Value: e.Temperature},
+		{Type: "Humidity", Unit: "%", Value: e.Humidity},
+	}
+}
@@ -2,12 +2,30 @@ package xiaomi
 
 type EventType uint16
 
+// MiBeacon object ids, the 18435+ ids are the ones of the v5 spec used by the newer products.
 const (
-	EventTypeTemperature            EventType = 4100
-	EventTypeHumidity               EventType = 4102
-	EventTypeIlluminance            EventType = 4103
-	EventTypeMoisture               EventType = 4104
-	EventTypeFertility              EventType = 4105
-	EventTypeBattery                EventType = 4106
-	EventTypeTemperatureAndHumidity EventType = 4109
+	EventTypeMotion                  EventType = 3
+	EventTypeMotionWithIlluminance   EventType = 15
+	EventTypeButton                  EventType = 4097
+	EventTypeTemperature             EventType = 4100
+	EventTypeHumidity                EventType = 4102
+	EventTypeIlluminance             EventType = 4103
+	EventTypeMoisture                EventType = 4104
+	EventTypeFertility               EventType = 4105
+	EventTypeBattery                 EventType = 4106
+	EventTypeTemperatureAndHumidity  EventType = 4109
+	EventTypeFormaldehyde            EventType = 4112
+	EventTypeSwitch                  EventType = 4114
+	EventTypeConsumable              EventType = 4115
+	EventTypeWaterLeak               EventType = 4116
+	EventTypeSmoke                   EventType = 4117
+	EventTypeNoMotion                EventType = 4119
+	EventTypeLight                   EventType = 4120
+	EventTypeDoor                    EventType = 4121
+	EventTypeBatteryV5               EventType = 18435
+	EventTypeNoMotionV5              EventType = 18456
+	EventTypeMotionWithIlluminanceV5 EventType = 18952
+	EventTypeTemperatureV5           EventType = 19457
+	EventTypeHumidityV5              EventType = 19458
+	EventTypeHumidityFloatV5         EventType = 19464
 )
@@ -0,0 +1,13 @@
+package xiaomi
+
+type EventWaterLeak struct {
+	Leak bool
+}
+
+func (e *EventWaterLeak) isMiEvent() {}
+func (e *EventWaterLeak) EventType() EventType {
+	return EventTypeWaterLeak
+}
+func (e *EventWaterLeak) Measurements() []Measurement {
+	return []Measurement{{Type: "WaterLeak", Value: boolValue(e.Leak)}}
+}
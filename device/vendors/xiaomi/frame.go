@@ -10,4 +10,6 @@ type Frame struct {
 	EventType         uint16
 	EventLength       uint8
 	Event             Event
+	// Events holds the known objects of the frame, Event is the first of them, EventType and EventLength describe the first object.
+	Events []Event
 }
@@ -0,0 +1,27 @@
+package xiaomi
+
+import (
+	"strconv"
+)
+
+type Measurement struct {
+	Type  string
+	Unit  string
+	Value float64
+	Text  string
+}
+
+// FormattedValue returns the text of state measurements, otherwise the numeric value.
+func (m Measurement) FormattedValue() string {
+	if m.Text != "" {
+		return m.Text
+	}
+	return strconv.FormatFloat(m.Value, 'f', -1, 64)
+}
+
+func boolValue(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
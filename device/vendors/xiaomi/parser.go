@@ -9,12 +9,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 
 	"github.com/pschlump/AesCCM"
 )
 
 var (
 	ErrBindKeyRequired = errors.New("bind key required")
+
+	errUnknownEventType = errors.New("unknown event type")
 )
 
 func ParseBLEFrame(rawData []byte, bindKeyCallback func(mac string) ([]byte, error)) (*Frame, error) {
@@ -79,24 +82,48 @@ func ParseBLEFrame(rawData []byte, bindKeyCallback func(mac string) ([]byte, err
 
 	var eventType uint16
 	var eventLength uint8
-	var event Event
+	var events []Event
 	if frameControl.HasEvent {
-		eventType, err = parseEventType(eventReader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read event type: %w", err)
-		}
+		for objects := 0; ; objects++ {
+			objectType, err := parseEventType(eventReader)
+			if err != nil {
+				// the first object is required, the following ones are optional
+				if objects != 0 {
+					break
+				}
+				return nil, fmt.Errorf("failed to read event type: %w", err)
+			}
 
-		eventLength, err = parseEventLength(eventReader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read event length: %w", err)
-		}
+			objectLength, err := parseEventLength(eventReader)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read event length: %w", err)
+			}
 
-		event, err = parseEventData(eventType, eventReader)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read event data: %w", err)
+			eventData := make([]byte, objectLength)
+			if _, err := io.ReadFull(eventReader, eventData); err != nil {
+				return nil, fmt.Errorf("failed to read event data: %w", err)
+			}
+			if objects == 0 {
+				eventType = objectType
+				eventLength = objectLength
+			}
+
+			event, err := parseEventData(objectType, eventData)
+			if err != nil {
+				if errors.Is(err, errUnknownEventType) {
+					continue
+				}
+				return nil, fmt.Errorf("failed to read event data: %w", err)
+			}
+			events = append(events, event)
 		}
 	}
 
+	var event Event
+	if len(events) != 0 {
+		event = events[0]
+	}
+
 	return &Frame{
 		FrameControlFlags: frameControl,
 		Version:           version,
@@ -107,6 +134,7 @@ func ParseBLEFrame(rawData []byte, bindKeyCallback func(mac string) ([]byte, err
 		EventType:         eventType,
 		EventLength:       eventLength,
 		Event:             event,
+		Events:            events,
 	}, nil
 }
 
@@ -226,8 +254,43 @@ func parseEventLength(reader io.Reader) (byte, error) {
 	return eventLength, nil
 }
 
-func parseEventData(eventType uint16, reader io.Reader) (Event, error) {
+func parseEventData(eventType uint16, data []byte) (Event, error) {
+	reader := bytes.NewReader(data)
 	switch EventType(eventType) {
+	case EventTypeMotion:
+		var eventData uint8
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventMotion{
+			Motion: eventData != 0,
+		}, nil
+	case EventTypeMotionWithIlluminance:
+		illuminance, err := parseUint24(reader)
+		if err != nil {
+			return nil, err
+		}
+		return &EventMotionWithIlluminance{
+			Illuminance: float64(illuminance),
+		}, nil
+	case EventTypeMotionWithIlluminanceV5:
+		illuminance, err := parseFloat32(reader)
+		if err != nil {
+			return nil, err
+		}
+		return &EventMotionWithIlluminance{
+			Illuminance: illuminance,
+		}, nil
+	case EventTypeButton:
+		eventData := make([]byte, 3)
+		if _, err := io.ReadFull(reader, eventData); err != nil {
+			return nil, err
+		}
+		return &EventButton{
+			Button: eventData[0],
+			Value:  eventData[1],
+			Press:  ButtonPress(eventData[2]),
+		}, nil
 	case EventTypeTemperature:
 		var eventData int16
 		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
@@ -236,6 +299,14 @@ func parseEventData(eventType uint16, reader io.Reader) (Event, error) {
 		return &EventTemperature{
 			Temperature: float64(eventData) / 10.0,
 		}, nil
+	case EventTypeTemperatureV5:
+		temperature, err := parseFloat32(reader)
+		if err != nil {
+			return nil, err
+		}
+		return &EventTemperature{
+			Temperature: temperature,
+		}, nil
 	case EventTypeHumidity:
 		var eventData uint16
 		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
@@ -244,13 +315,29 @@ func parseEventData(eventType uint16, reader io.Reader) (Event, error) {
 		return &EventHumidity{
 			Humidity: float64(eventData) / 10.0,
 		}, nil
+	case EventTypeHumidityV5:
+		var eventData uint8
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventHumidity{
+			Humidity: float64(eventData),
+		}, nil
+	case EventTypeHumidityFloatV5:
+		humidity, err := parseFloat32(reader)
+		if err != nil {
+			return nil, err
+		}
+		return &EventHumidity{
+			Humidity: humidity,
+		}, nil
 	case EventTypeIlluminance:
-		eventData := make([]byte, 3)
-		if _, err := io.ReadFull(reader, eventData); err != nil {
+		illuminance, err := parseUint24(reader)
+		if err != nil {
 			return nil, err
 		}
 		return &EventIlluminance{
-			Illuminance: uint(eventData[0]) | uint(eventData[1])<<8 | uint(eventData[2])<<16,
+			Illuminance: illuminance,
 		}, nil
 	case EventTypeMoisture:
 		var eventData uint8
@@ -268,7 +355,7 @@ func parseEventData(eventType uint16, reader io.Reader) (Event, error) {
 		return &EventFertility{
 			Fertility: eventData,
 		}, nil
-	case EventTypeBattery:
+	case EventTypeBattery, EventTypeBatteryV5:
 		var eventData uint8
 		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
 			return nil, err
@@ -291,7 +378,96 @@ func parseEventData(eventType uint16, reader io.Reader) (Event, error) {
 			Temperature: float64(temperature) / 10.0,
 			Humidity:    float64(humidity) / 10.0,
 		}, nil
+	case EventTypeFormaldehyde:
+		var eventData uint16
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventFormaldehyde{
+			Formaldehyde: float64(eventData) / 100.0,
+		}, nil
+	case EventTypeSwitch:
+		var eventData uint8
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventSwitch{
+			On: eventData != 0,
+		}, nil
+	case EventTypeConsumable:
+		var eventData uint8
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventConsumable{
+			Remaining: eventData,
+		}, nil
+	case EventTypeWaterLeak:
+		var eventData uint8
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventWaterLeak{
+			Leak: eventData != 0,
+		}, nil
+	case EventTypeSmoke:
+		var eventData uint8
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventSmoke{
+			Smoke: eventData != 0,
+		}, nil
+	case EventTypeNoMotion:
+		var eventData uint32
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventNoMotion{
+			Duration: eventData,
+		}, nil
+	case EventTypeNoMotionV5:
+		var eventData uint16
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventNoMotion{
+			Duration: uint32(eventData),
+		}, nil
+	case EventTypeLight:
+		var eventData uint8
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventLight{
+			Light: eventData != 0,
+		}, nil
+	case EventTypeDoor:
+		var eventData uint8
+		if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+			return nil, err
+		}
+		return &EventDoor{
+			State: DoorState(eventData),
+		}, nil
 	default:
-		return nil, fmt.Errorf("unknown event type: %d", eventType)
+		return nil, fmt.Errorf("%w: %d", errUnknownEventType, eventType)
+	}
+}
+
+func parseUint24(reader io.Reader) (uint, error) {
+	eventData := make([]byte, 3)
+	if _, err := io.ReadFull(reader, eventData); err != nil {
+		return 0, err
+	}
+	return uint(eventData[0]) | uint(eventData[1])<<8 | uint(eventData[2])<<16, nil
+}
+
+// parseFloat32 reads the float values of the v5 objects rounded to two decimals.
+func parseFloat32(reader io.Reader) (float64, error) {
+	var eventData uint32
+	if err := binary.Read(reader, binary.LittleEndian, &eventData); err != nil {
+		return 0, err
 	}
+	return math.Round(float64(math.Float32frombits(eventData))*100) / 100, nil
 }
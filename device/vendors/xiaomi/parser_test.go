@@ -42,6 +42,11 @@ func TestParseBLEFrame(t *testing.T) {
 				Event: &EventHumidity{
 					Humidity: 30.8,
 				},
+				Events: []Event{
+					&EventHumidity{
+						Humidity: 30.8,
+					},
+				},
 			},
 			expectedErr: "",
 		},
@@ -65,6 +70,11 @@ func TestParseBLEFrame(t *testing.T) {
 				Event: &EventTemperature{
 					Temperature: 25.6,
 				},
+				Events: []Event{
+					&EventTemperature{
+						Temperature: 25.6,
+					},
+				},
 			},
 			expectedErr: "",
 		},
@@ -88,6 +98,11 @@ func TestParseBLEFrame(t *testing.T) {
 				Event: &EventBattery{
 					Battery: 100,
 				},
+				Events: []Event{
+					&EventBattery{
+						Battery: 100,
+					},
+				},
 			},
 			expectedErr: "",
 		},
@@ -113,6 +128,161 @@ func TestParseBLEFrame(t *testing.T) {
 			},
 			expectedErr: "",
 		},
+		{
+			name: "multiple objects",
+			data: "71209800128fe66d8d7cc40d041002e2000710036400000810012b091002f4019999010a0a10015f",
+			expected: &Frame{
+				FrameControlFlags: FrameControlFlags{
+					IsFactoryNew:    true,
+					HasMacAddress:   true,
+					HasCapabilities: true,
+					HasEvent:        true,
+				},
+				Version:      2,
+				ProductId:    152,
+				FrameCounter: 18,
+				Capabilities: CapabilityFlags{
+					Connectable: true,
+					Secure:      true,
+					IO:          true,
+				},
+				MacAddress:  "c47c8d6de68f",
+				EventType:   4100,
+				EventLength: 2,
+				Event: &EventTemperature{
+					Temperature: 22.6,
+				},
+				Events: []Event{
+					&EventTemperature{
+						Temperature: 22.6,
+					},
+					&EventIlluminance{
+						Illuminance: 100,
+					},
+					&EventMoisture{
+						Moisture: 43,
+					},
+					&EventFertility{
+						Fertility: 500,
+					},
+					&EventBattery{
+						Battery: 95,
+					},
+				},
+			},
+		},
+		{
+			name: "door and light events",
+			data: "50508b0901efcdab3bc1a419100100181001010a10015a",
+			expected: &Frame{
+				FrameControlFlags: FrameControlFlags{
+					HasMacAddress: true,
+					HasEvent:      true,
+				},
+				Version:      5,
+				ProductId:    2443,
+				FrameCounter: 1,
+				MacAddress:   "a4c13babcdef",
+				EventType:    4121,
+				EventLength:  1,
+				Event: &EventDoor{
+					State: DoorStateOpen,
+				},
+				Events: []Event{
+					&EventDoor{
+						State: DoorStateOpen,
+					},
+					&EventLight{
+						Light: true,
+					},
+					&EventBattery{
+						Battery: 90,
+					},
+				},
+			},
+		},
+		{
+			name: "v5 float objects",
+			data: "5050322802efcdab3bc1a4014c040000ac41084c0466663642184802780003480164",
+			expected: &Frame{
+				FrameControlFlags: FrameControlFlags{
+					HasMacAddress: true,
+					HasEvent:      true,
+				},
+				Version:      5,
+				ProductId:    10290,
+				FrameCounter: 2,
+				MacAddress:   "a4c13babcdef",
+				EventType:    19457,
+				EventLength:  4,
+				Event: &EventTemperature{
+					Temperature: 21.5,
+				},
+				Events: []Event{
+					&EventTemperature{
+						Temperature: 21.5,
+					},
+					&EventHumidity{
+						Humidity: 45.6,
+					},
+					&EventNoMotion{
+						Duration: 120,
+					},
+					&EventBattery{
+						Battery: 100,
+					},
+				},
+			},
+		},
+		{
+			name: "dimmer rotation",
+			data: "5050b60303efcdab3bc1a4011003000304",
+			expected: &Frame{
+				FrameControlFlags: FrameControlFlags{
+					HasMacAddress: true,
+					HasEvent:      true,
+				},
+				Version:      5,
+				ProductId:    950,
+				FrameCounter: 3,
+				MacAddress:   "a4c13babcdef",
+				EventType:    4097,
+				EventLength:  3,
+				Event: &EventButton{
+					Button: 0,
+					Value:  3,
+					Press:  ButtonPressRotate,
+				},
+				Events: []Event{
+					&EventButton{
+						Button: 0,
+						Value:  3,
+						Press:  ButtonPressRotate,
+					},
+				},
+			},
+		},
+		{
+			name: "only unknown objects",
+			data: "5050b60304efcdab3bc1a4999902abcd",
+			expected: &Frame{
+				FrameControlFlags: FrameControlFlags{
+					HasMacAddress: true,
+					HasEvent:      true,
+				},
+				Version:      5,
+				ProductId:    950,
+				FrameCounter: 4,
+				MacAddress:   "a4c13babcdef",
+				EventType:    39321,
+				EventLength:  2,
+			},
+		},
+		{
+			name:        "truncated object",
+			data:        "5050b60305efcdab3bc1a4041002e2",
+			expectedErr: "failed to read event data: unexpected EOF",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -1,5 +1,20 @@
 package input
 
+const (
+	// NamePropertyKey is the property holding the name of the input a message was received from.
+	NamePropertyKey = "inputName"
+	// MacAddressPropertyKey is the property holding the mac address of the advertiser.
+	MacAddressPropertyKey = "macAddress"
+	// RssiPropertyKey is the property holding the received signal strength of the advertisement in dBm.
+	RssiPropertyKey = "rssi"
+	// GatewayPropertyKey is the property holding the name of the gateway which received the advertisement.
+	GatewayPropertyKey = "gateway"
+	// GatewayCountPropertyKey is the property holding the number of gateways which received a merged advertisement.
+	GatewayCountPropertyKey = "gatewayCount"
+	// GatewayRssiPropertyKey is the property holding the rssi of a merged advertisement per gateway.
+	GatewayRssiPropertyKey = "gatewayRssi"
+)
+
 type Data struct {
 	Data       []byte
 	Properties map[string]interface{}
@@ -6,4 +6,8 @@ import (
 	"github.com/nikiforov-soft/yasp/config"
 )
 
-type Factory func(ctx context.Context, config *config.Input) (Input, error)
+// Factory creates an input from its options, which the factory decodes into its own config.
+type Factory func(ctx context.Context, options config.Options) (Input, error)
+
+// Validator reports the problems of the options of an input without creating it.
+type Validator func(options config.Options) error
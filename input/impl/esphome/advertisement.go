@@ -0,0 +1,121 @@
+package esphome
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Advertising data types, https://www.bluetooth.com/specifications/assigned-numbers/
+const (
+	adTypeShortLocalName    = 0x08
+	adTypeCompleteLocalName = 0x09
+	adTypeServiceData16     = 0x16
+	adTypeServiceData32     = 0x20
+	adTypeServiceData128    = 0x21
+	adTypeManufacturerData  = 0xff
+	bluetoothBaseUuidSuffix = "-0000-1000-8000-00805f9b34fb"
+)
+
+// advertisementData holds the service data keyed by 128-bit uuid and the manufacturer data keyed by company id.
+type advertisementData struct {
+	localName        string
+	serviceData      map[string][]byte
+	manufacturerData map[string][]byte
+}
+
+func newAdvertisementData() *advertisementData {
+	return &advertisementData{
+		serviceData:      make(map[string][]byte),
+		manufacturerData: make(map[string][]byte),
+	}
+}
+
+// parseAdvertisingData parses the length, type and value structures of raw advertising data, a truncated trailing
+// structure is ignored.
+func parseAdvertisingData(raw []byte) *advertisementData {
+	data := newAdvertisementData()
+	for len(raw) != 0 {
+		length := int(raw[0])
+		if length == 0 || length >= len(raw) {
+			break
+		}
+		adType, value := raw[1], raw[2:length+1]
+		raw = raw[length+1:]
+
+		switch adType {
+		case adTypeShortLocalName:
+			if data.localName == "" {
+				data.localName = string(value)
+			}
+		case adTypeCompleteLocalName:
+			data.localName = string(value)
+		case adTypeServiceData16:
+			if len(value) >= 2 {
+				data.serviceData[uuid32(uint32(binary.LittleEndian.Uint16(value)))] = value[2:]
+			}
+		case adTypeServiceData32:
+			if len(value) >= 4 {
+				data.serviceData[uuid32(binary.LittleEndian.Uint32(value))] = value[4:]
+			}
+		case adTypeServiceData128:
+			if len(value) >= 16 {
+				data.serviceData[uuid128(value[:16])] = value[16:]
+			}
+		case adTypeManufacturerData:
+			if len(value) >= 2 {
+				data.manufacturerData[companyId(binary.LittleEndian.Uint16(value))] = value[2:]
+			}
+		}
+	}
+	return data
+}
+
+// uuid32 returns the 128-bit form of a 16 or 32-bit uuid based on the bluetooth base uuid.
+func uuid32(uuid uint32) string {
+	return fmt.Sprintf("%08x%s", uuid, bluetoothBaseUuidSuffix)
+}
+
+// uuid128 formats the little endian 128-bit uuid of an advertisement.
+func uuid128(value []byte) string {
+	b := make([]byte, 16)
+	for i := range b {
+		b[i] = value[15-i]
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func companyId(id uint16) string {
+	return fmt.Sprintf("0x%04x", id)
+}
+
+// normalizeUuid returns the lower case 128-bit form of 0xfe95, fe95 and 0000fe95-0000-1000-8000-00805f9b34fb alike.
+func normalizeUuid(uuid string) string {
+	uuid = strings.ToLower(strings.TrimSpace(uuid))
+	short := strings.TrimPrefix(uuid, "0x")
+	if len(short) <= 8 {
+		if value, err := strconv.ParseUint(short, 16, 32); err == nil {
+			return uuid32(uint32(value))
+		}
+	}
+	return uuid
+}
+
+func normalizeCompanyId(id string) string {
+	value, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(id)), "0x"), 16, 16)
+	if err != nil {
+		return id
+	}
+	return companyId(uint16(value))
+}
+
+// macAddress formats the 48-bit address of an advertisement.
+func macAddress(address uint64) string {
+	b := make(net.HardwareAddr, 6)
+	for i := range b {
+		b[i] = byte(address >> (8 * (5 - i)))
+	}
+	return b.String()
+}
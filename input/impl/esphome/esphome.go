@@ -0,0 +1,343 @@
+package esphome
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+)
+
+const (
+	dialTimeout      = 10 * time.Second
+	handshakeTimeout = 30 * time.Second
+)
+
+var (
+	eventsProcessedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "input_events_processed",
+		Help:      "The amount of advertisements esphome input processed.",
+		Namespace: "yasp",
+		Subsystem: "esphome",
+	}, []string{"gateway"})
+)
+
+type espHomeInput struct {
+	config            *config.EspHomeInput
+	psk               []byte
+	serviceDataUuid   string
+	subscriptions     []*subscription
+	subscriptionsLock sync.Mutex
+}
+
+// subscription is a connection to the proxy which is reestablished until the input is closed.
+type subscription struct {
+	dataChan   chan *input.Data
+	cancelFunc context.CancelFunc
+	done       chan struct{}
+}
+
+func newEspHomeInput(_ context.Context, config *config.EspHomeInput) (input.Input, error) {
+	if config.Address == "" {
+		return nil, errors.New("esphome input: address is required")
+	}
+
+	ei := &espHomeInput{
+		config: config,
+	}
+	if config.EncryptionKey != "" {
+		psk, err := base64.StdEncoding.DecodeString(config.EncryptionKey)
+		if err != nil || len(psk) != noiseKeySize {
+			return nil, errors.New("esphome input: encryptionKey must be a base64 encoded 32 byte key")
+		}
+		ei.psk = psk
+	}
+	if config.ServiceDataUuid != "" {
+		ei.serviceDataUuid = normalizeUuid(config.ServiceDataUuid)
+	}
+	return ei, nil
+}
+
+func (ei *espHomeInput) Subscribe(ctx context.Context) (<-chan *input.Data, error) {
+	ei.subscriptionsLock.Lock()
+	defer ei.subscriptionsLock.Unlock()
+
+	subscriptionCtx, cancelFunc := context.WithCancel(ctx)
+	s := &subscription{
+		dataChan:   make(chan *input.Data),
+		cancelFunc: cancelFunc,
+		done:       make(chan struct{}),
+	}
+	ei.subscriptions = append(ei.subscriptions, s)
+
+	go func() {
+		defer close(s.done)
+		ei.run(subscriptionCtx, s.dataChan)
+	}()
+	return s.dataChan, nil
+}
+
+func (ei *espHomeInput) Close(_ context.Context) error {
+	ei.subscriptionsLock.Lock()
+	defer ei.subscriptionsLock.Unlock()
+
+	for _, s := range ei.subscriptions {
+		s.cancelFunc()
+		<-s.done
+		close(s.dataChan)
+	}
+	ei.subscriptions = nil
+	return nil
+}
+
+// run connects to the proxy and reconnects after the reconnect interval until ctx is done.
+func (ei *espHomeInput) run(ctx context.Context, dataChan chan<- *input.Data) {
+	for {
+		err := ei.connect(ctx, dataChan)
+		if ctx.Err() != nil {
+			return
+		}
+		logrus.
+			WithError(err).
+			WithField("address", ei.config.GetAddress()).
+			Error("esphome input: connection failed, reconnecting")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ei.config.GetReconnectInterval()):
+		}
+	}
+}
+
+// connect establishes the api connection, subscribes to the bluetooth advertisements and forwards them until the
+// connection fails or ctx is done.
+func (ei *espHomeInput) connect(ctx context.Context, dataChan chan<- *input.Data) error {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", ei.config.GetAddress())
+	if err != nil {
+		return fmt.Errorf("esphome input: failed to connect: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.Close()
+	})
+	defer stop()
+
+	_ = conn.SetDeadline(time.Now().Add(handshakeTimeout))
+	var fh frameHelper
+	var gateway string
+	if ei.psk != nil {
+		noiseFrameHelper, err := newNoiseFrameHelper(conn, ei.psk)
+		if err != nil {
+			return fmt.Errorf("esphome input: %w", err)
+		}
+		fh = noiseFrameHelper
+		gateway = noiseFrameHelper.serverName
+	} else {
+		fh = newPlaintextFrameHelper(conn)
+	}
+	c := &connection{frameHelper: fh}
+
+	if err := c.writeMessage(helloRequestType, encodeHelloRequest(ei.config.GetClientInfo())); err != nil {
+		return fmt.Errorf("esphome input: failed to send hello: %w", err)
+	}
+	message, err := c.readMessage(helloResponseType)
+	if err != nil {
+		return fmt.Errorf("esphome input: failed to receive hello: %w", err)
+	}
+	hello, err := decodeHelloResponse(message)
+	if err != nil {
+		return fmt.Errorf("esphome input: failed to decode hello: %w", err)
+	}
+	if hello.apiVersionMajor != apiVersionMajor {
+		return fmt.Errorf("esphome input: unsupported api version: %d", hello.apiVersionMajor)
+	}
+	if hello.name != "" {
+		gateway = hello.name
+	}
+	if gateway == "" {
+		gateway = ei.config.Address
+	}
+
+	if err := c.writeMessage(connectRequestType, encodeConnectRequest(ei.config.Password)); err != nil {
+		return fmt.Errorf("esphome input: failed to send connect: %w", err)
+	}
+	message, err = c.readMessage(connectResponseType)
+	if err != nil {
+		return fmt.Errorf("esphome input: failed to receive connect: %w", err)
+	}
+	if invalidPassword, err := decodeConnectResponse(message); err != nil {
+		return fmt.Errorf("esphome input: failed to decode connect: %w", err)
+	} else if invalidPassword {
+		return errors.New("esphome input: invalid password")
+	}
+
+	if err := c.writeMessage(subscribeBluetoothAdvertisementsType, encodeSubscribeBluetoothAdvertisements(rawAdvertisementsSubscriptionFlag)); err != nil {
+		return fmt.Errorf("esphome input: failed to subscribe to bluetooth advertisements: %w", err)
+	}
+	logrus.
+		WithField("gateway", gateway).
+		WithField("serverInfo", hello.serverInfo).
+		Info("esphome input: subscribed to bluetooth advertisements")
+
+	pingInterval := ei.config.GetPingInterval()
+	pingCtx, cancelPing := context.WithCancel(ctx)
+	defer cancelPing()
+	go c.ping(pingCtx, pingInterval)
+	for {
+		// the proxy answers the pings, so a silent connection is a dead one
+		_ = conn.SetDeadline(time.Now().Add(3 * pingInterval))
+		messageType, message, err := c.readAny()
+		if err != nil {
+			return fmt.Errorf("esphome input: failed to receive message: %w", err)
+		}
+
+		switch messageType {
+		case disconnectRequestType:
+			_ = c.writeMessage(disconnectResponseType, nil)
+			return errors.New("esphome input: proxy requested disconnect")
+		case bluetoothRawAdvertisementsResponseType:
+			advertisements, err := decodeRawAdvertisements(message)
+			if err != nil {
+				return fmt.Errorf("esphome input: failed to decode raw advertisements: %w", err)
+			}
+			for _, advertisement := range advertisements {
+				ei.forward(ctx, dataChan, gateway, advertisement, parseAdvertisingData(advertisement.data))
+			}
+		case bluetoothAdvertisementResponseType:
+			advertisement, advertisementData, err := decodeAdvertisement(message)
+			if err != nil {
+				return fmt.Errorf("esphome input: failed to decode advertisement: %w", err)
+			}
+			ei.forward(ctx, dataChan, gateway, advertisement, advertisementData)
+		}
+	}
+}
+
+// forward sends the advertisement to dataChan, the data is the service data of the configured uuid or the raw
+// advertising data, advertisements without the configured service data are skipped.
+func (ei *espHomeInput) forward(ctx context.Context, dataChan chan<- *input.Data, gateway string, advertisement *rawAdvertisement, advertisementData *advertisementData) {
+	data := advertisement.data
+	if ei.serviceDataUuid != "" {
+		serviceData, exists := advertisementData.serviceData[ei.serviceDataUuid]
+		if !exists {
+			return
+		}
+		data = serviceData
+	}
+
+	eventsProcessedCounter.WithLabelValues(gateway).Inc()
+	inputData := &input.Data{
+		Data: data,
+		Properties: map[string]interface{}{
+			input.MacAddressPropertyKey: macAddress(advertisement.address),
+			input.RssiPropertyKey:       int(advertisement.rssi),
+			input.GatewayPropertyKey:    gateway,
+			"esphomeAddressType":        advertisement.addressType,
+			"localName":                 advertisementData.localName,
+			"serviceData":               advertisementData.serviceData,
+			"manufacturerData":          advertisementData.manufacturerData,
+		},
+	}
+
+	select {
+	case <-ctx.Done():
+	case dataChan <- inputData:
+	}
+}
+
+// connection serializes the writes of the read loop and the pings, the noise nonce requires them to be ordered.
+type connection struct {
+	frameHelper frameHelper
+	writeLock   sync.Mutex
+}
+
+func (c *connection) writeMessage(messageType uint16, message []byte) error {
+	c.writeLock.Lock()
+	defer c.writeLock.Unlock()
+	return c.frameHelper.writeMessage(messageType, message)
+}
+
+// readAny reads the next message answering the pings of the proxy.
+func (c *connection) readAny() (uint16, []byte, error) {
+	for {
+		messageType, message, err := c.frameHelper.readMessage()
+		if err != nil {
+			return 0, nil, err
+		}
+		if messageType == pingRequestType {
+			if err := c.writeMessage(pingResponseType, nil); err != nil {
+				return 0, nil, err
+			}
+			continue
+		}
+		return messageType, message, nil
+	}
+}
+
+// readMessage reads messages until one of the expected type is received.
+func (c *connection) readMessage(expectedType uint16) ([]byte, error) {
+	for {
+		messageType, message, err := c.readAny()
+		if err != nil {
+			return nil, err
+		}
+		if messageType == disconnectRequestType {
+			_ = c.writeMessage(disconnectResponseType, nil)
+			return nil, errors.New("proxy requested disconnect")
+		}
+		if messageType == expectedType {
+			return message, nil
+		}
+	}
+}
+
+// ping keeps the connection alive until ctx is done or a ping fails, which fails the read loop as well.
+func (c *connection) ping(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.writeMessage(pingRequestType, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func init() {
+	err := input.RegisterInput("esphome", func(ctx context.Context, options config.Options) (input.Input, error) {
+		var espHomeConfig config.EspHomeInput
+		if err := options.Decode(&espHomeConfig); err != nil {
+			return nil, fmt.Errorf("esphome input: failed to decode options: %w", err)
+		}
+		return newEspHomeInput(ctx, &espHomeConfig)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	err = input.RegisterInputValidator("esphome", func(options config.Options) error {
+		var espHomeConfig config.EspHomeInput
+		if err := options.DecodeStrict(&espHomeConfig); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+		return espHomeConfig.Validate()
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,273 @@
+package esphome
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+)
+
+// fakeProxy is a native api server which sends a single raw advertisement once the client subscribed.
+type fakeProxy struct {
+	listener net.Listener
+	psk      []byte
+	password string
+	errs     chan error
+}
+
+func newFakeProxy(t *testing.T, psk []byte, password string) *fakeProxy {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	fp := &fakeProxy{
+		listener: listener,
+		psk:      psk,
+		password: password,
+		errs:     make(chan error, 16),
+	}
+	go fp.serve()
+	return fp
+}
+
+func (fp *fakeProxy) serve() {
+	for {
+		conn, err := fp.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			if err := fp.handle(conn); err != nil {
+				fp.errs <- err
+			}
+		}()
+	}
+}
+
+func (fp *fakeProxy) handle(conn net.Conn) error {
+	var fh frameHelper
+	if fp.psk != nil {
+		reader := bufio.NewReader(conn)
+		if _, err := readNoiseFrame(reader); err != nil {
+			return err
+		}
+		handshake, err := readNoiseFrame(reader)
+		if err != nil {
+			return err
+		}
+
+		hs, err := newHandshakeState(fp.psk)
+		if err != nil {
+			return err
+		}
+		if err := writeNoiseFrame(conn, []byte("\x01fake-proxy\x00a4:c1:38:00:00:00\x00")); err != nil {
+			return err
+		}
+		remoteEphemeral, err := hs.readInitiatorMessage(handshake[1:])
+		if err != nil {
+			return writeNoiseFrame(conn, []byte("\x01Handshake MAC failure"))
+		}
+		response, encrypt, decrypt, err := hs.writeResponderMessage(remoteEphemeral)
+		if err != nil {
+			return err
+		}
+		if err := writeNoiseFrame(conn, append([]byte{0x00}, response...)); err != nil {
+			return err
+		}
+		fh = &noiseFrameHelper{
+			conn:    conn,
+			reader:  reader,
+			encrypt: encrypt,
+			decrypt: decrypt,
+		}
+	} else {
+		fh = newPlaintextFrameHelper(conn)
+	}
+
+	if _, err := expectMessage(fh, helloRequestType); err != nil {
+		return err
+	}
+	var hello []byte
+	hello = appendVarintField(hello, 1, apiVersionMajor)
+	hello = appendVarintField(hello, 2, apiVersionMinor)
+	hello = appendBytesField(hello, 3, []byte("fake-proxy (esphome v2024.12.0)"))
+	hello = appendBytesField(hello, 4, []byte("living-room-proxy"))
+	if err := fh.writeMessage(helloResponseType, hello); err != nil {
+		return err
+	}
+
+	connect, err := expectMessage(fh, connectRequestType)
+	if err != nil {
+		return err
+	}
+	if string(connect) != string(encodeConnectRequest(fp.password)) {
+		return fh.writeMessage(connectResponseType, appendVarintField(nil, 1, 1))
+	}
+	if err := fh.writeMessage(connectResponseType, nil); err != nil {
+		return err
+	}
+
+	subscribe, err := expectMessage(fh, subscribeBluetoothAdvertisementsType)
+	if err != nil {
+		return err
+	}
+	if string(subscribe) != string(encodeSubscribeBluetoothAdvertisements(rawAdvertisementsSubscriptionFlag)) {
+		return assert.AnError
+	}
+
+	// the client has to answer pings while subscribed
+	if err := fh.writeMessage(pingRequestType, nil); err != nil {
+		return err
+	}
+	if _, err := expectMessage(fh, pingResponseType); err != nil {
+		return err
+	}
+
+	advertisingData := []byte{
+		0x02, 0x01, 0x06,
+		0x0a, 0x16, 0xd2, 0xfc, 0x40, 0x00, 0x01, 0x02, 0xca, 0x09, 0x01,
+		0x05, 0xff, 0x4c, 0x00, 0x02, 0x15,
+		0x06, 0x09, 'A', 'T', 'C', '_', '1',
+	}
+	var advertisement []byte
+	advertisement = appendVarintField(advertisement, 1, 0xa4c138abcdef)
+	advertisement = appendVarintField(advertisement, 2, 139) // zigzag encoded -70
+	advertisement = appendVarintField(advertisement, 3, 0)
+	advertisement = appendBytesField(advertisement, 4, advertisingData)
+	if err := fh.writeMessage(bluetoothRawAdvertisementsResponseType, appendBytesField(nil, 1, advertisement)); err != nil {
+		return err
+	}
+
+	// keeps the connection open until the client disconnects
+	for {
+		if _, _, err := fh.readMessage(); err != nil {
+			return nil
+		}
+	}
+}
+
+func expectMessage(fh frameHelper, expectedType uint16) ([]byte, error) {
+	messageType, message, err := fh.readMessage()
+	if err != nil {
+		return nil, err
+	}
+	if messageType != expectedType {
+		return nil, assert.AnError
+	}
+	return message, nil
+}
+
+func TestEspHomeInput(t *testing.T) {
+	psk := make([]byte, noiseKeySize)
+	_, _ = rand.Read(psk)
+	wrongPsk := make([]byte, noiseKeySize)
+	_, _ = rand.Read(wrongPsk)
+
+	tests := []struct {
+		name           string
+		serverPsk      []byte
+		clientPsk      []byte
+		serverPassword string
+		clientPassword string
+		expectedError  string
+	}{
+		{
+			name: "plaintext",
+		},
+		{
+			name:           "plaintext with password",
+			serverPassword: "secret",
+			clientPassword: "secret",
+		},
+		{
+			name:      "noise",
+			serverPsk: psk,
+			clientPsk: psk,
+		},
+		{
+			name:          "noise with wrong key",
+			serverPsk:     psk,
+			clientPsk:     wrongPsk,
+			expectedError: "esphome input: handshake failed: Handshake MAC failure",
+		},
+		{
+			name:           "invalid password",
+			serverPassword: "secret",
+			clientPassword: "wrong",
+			expectedError:  "esphome input: invalid password",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy := newFakeProxy(t, tt.serverPsk, tt.serverPassword)
+
+			espHomeConfig := &config.EspHomeInput{
+				Address:         proxy.listener.Addr().String(),
+				Password:        tt.clientPassword,
+				ServiceDataUuid: "0xfcd2",
+			}
+			if tt.clientPsk != nil {
+				espHomeConfig.EncryptionKey = base64.StdEncoding.EncodeToString(tt.clientPsk)
+			}
+			in, err := newEspHomeInput(context.Background(), espHomeConfig)
+			require.NoError(t, err)
+			ei := in.(*espHomeInput)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			if tt.expectedError != "" {
+				assert.EqualError(t, ei.connect(ctx, make(chan *input.Data)), tt.expectedError)
+				return
+			}
+
+			dataChan, err := in.Subscribe(ctx)
+			require.NoError(t, err)
+
+			select {
+			case data := <-dataChan:
+				assert.Equal(t, []byte{0x40, 0x00, 0x01, 0x02, 0xca, 0x09, 0x01}, data.Data)
+				assert.Equal(t, "a4:c1:38:ab:cd:ef", data.Properties["macAddress"])
+				assert.Equal(t, -70, data.Properties["rssi"])
+				assert.Equal(t, "living-room-proxy", data.Properties["gateway"])
+				assert.Equal(t, "ATC_1", data.Properties["localName"])
+				assert.Equal(t, map[string][]byte{
+					"0000fcd2-0000-1000-8000-00805f9b34fb": {0x40, 0x00, 0x01, 0x02, 0xca, 0x09, 0x01},
+				}, data.Properties["serviceData"])
+				assert.Equal(t, map[string][]byte{
+					"0x004c": {0x02, 0x15},
+				}, data.Properties["manufacturerData"])
+			case err := <-proxy.errs:
+				t.Fatal(err)
+			case <-ctx.Done():
+				t.Fatal("no advertisement received")
+			}
+			assert.NoError(t, in.Close(context.Background()))
+		})
+	}
+}
+
+func TestParseAdvertisingData(t *testing.T) {
+	data := parseAdvertisingData([]byte{
+		0x11, 0x21, 0xfb, 0x34, 0x9b, 0x5f, 0x80, 0x00, 0x00, 0x80, 0x00, 0x10, 0x00, 0x00, 0x95, 0xfe, 0x00, 0x00,
+		0x07, 0x20, 0x1a, 0x18, 0x00, 0x00, 0xaa, 0xbb,
+		0x09, 0x16, 0x95, 0xfe,
+	})
+
+	assert.Equal(t, map[string][]byte{
+		"0000fe95-0000-1000-8000-00805f9b34fb": {},
+		"0000181a-0000-1000-8000-00805f9b34fb": {0xaa, 0xbb},
+	}, data.serviceData)
+}
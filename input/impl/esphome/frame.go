@@ -0,0 +1,202 @@
+package esphome
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+const (
+	plaintextIndicator = 0x00
+	noiseIndicator     = 0x01
+	maxMessageSize     = 1 << 20
+)
+
+var (
+	errEncryptionRequired    = errors.New("the api requires encryption, configure the encryptionKey")
+	errEncryptionUnsupported = errors.New("the api does not use encryption, remove the encryptionKey")
+)
+
+// frameHelper reads and writes the framed messages of a native api connection.
+type frameHelper interface {
+	writeMessage(messageType uint16, message []byte) error
+	readMessage() (uint16, []byte, error)
+}
+
+// plaintextFrameHelper frames messages as 0x00, varint size, varint type and the message.
+type plaintextFrameHelper struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func newPlaintextFrameHelper(conn net.Conn) *plaintextFrameHelper {
+	return &plaintextFrameHelper{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+}
+
+func (pfh *plaintextFrameHelper) writeMessage(messageType uint16, message []byte) error {
+	frame := []byte{plaintextIndicator}
+	frame = binary.AppendUvarint(frame, uint64(len(message)))
+	frame = binary.AppendUvarint(frame, uint64(messageType))
+	_, err := pfh.conn.Write(append(frame, message...))
+	return err
+}
+
+func (pfh *plaintextFrameHelper) readMessage() (uint16, []byte, error) {
+	indicator, err := pfh.reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	switch indicator {
+	case plaintextIndicator:
+	case noiseIndicator:
+		return 0, nil, errEncryptionRequired
+	default:
+		return 0, nil, fmt.Errorf("invalid frame indicator: 0x%02x", indicator)
+	}
+
+	size, err := binary.ReadUvarint(pfh.reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	if size > maxMessageSize {
+		return 0, nil, fmt.Errorf("message too large: %d", size)
+	}
+	messageType, err := binary.ReadUvarint(pfh.reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	message := make([]byte, size)
+	if _, err := io.ReadFull(pfh.reader, message); err != nil {
+		return 0, nil, err
+	}
+	return uint16(messageType), message, nil
+}
+
+// noiseFrameHelper frames messages as 0x01, big endian uint16 size and the encrypted big endian uint16 type,
+// big endian uint16 size and the message.
+type noiseFrameHelper struct {
+	conn       net.Conn
+	reader     *bufio.Reader
+	encrypt    *cipherState
+	decrypt    *cipherState
+	serverName string
+}
+
+// newNoiseFrameHelper sends the client hello, runs the handshake and returns the frame helper of the encrypted connection.
+func newNoiseFrameHelper(conn net.Conn, psk []byte) (*noiseFrameHelper, error) {
+	nfh := &noiseFrameHelper{
+		conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+
+	hs, err := newHandshakeState(psk)
+	if err != nil {
+		return nil, err
+	}
+	handshakeMessage, err := hs.writeInitiatorMessage()
+	if err != nil {
+		return nil, err
+	}
+	if err := writeNoiseFrame(conn, nil, append([]byte{0x00}, handshakeMessage...)); err != nil {
+		return nil, err
+	}
+
+	serverHello, err := readNoiseFrame(nfh.reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(serverHello) == 0 || serverHello[0] != noiseIndicator {
+		return nil, fmt.Errorf("unsupported noise protocol: %x", serverHello)
+	}
+	if name, _, found := bytes.Cut(serverHello[1:], []byte{0x00}); found {
+		nfh.serverName = string(name)
+	}
+
+	handshakeResponse, err := readNoiseFrame(nfh.reader)
+	if err != nil {
+		return nil, err
+	}
+	if len(handshakeResponse) == 0 {
+		return nil, errors.New("empty handshake response")
+	}
+	if handshakeResponse[0] != 0x00 {
+		return nil, fmt.Errorf("handshake failed: %s", handshakeResponse[1:])
+	}
+	nfh.encrypt, nfh.decrypt, err = hs.readResponderMessage(handshakeResponse[1:])
+	if err != nil {
+		return nil, fmt.Errorf("handshake failed: %w", err)
+	}
+	return nfh, nil
+}
+
+func (nfh *noiseFrameHelper) writeMessage(messageType uint16, message []byte) error {
+	plaintext := make([]byte, 4, 4+len(message))
+	binary.BigEndian.PutUint16(plaintext, messageType)
+	binary.BigEndian.PutUint16(plaintext[2:], uint16(len(message)))
+	ciphertext, err := nfh.encrypt.encrypt(nil, append(plaintext, message...))
+	if err != nil {
+		return err
+	}
+	return writeNoiseFrame(nfh.conn, ciphertext)
+}
+
+func (nfh *noiseFrameHelper) readMessage() (uint16, []byte, error) {
+	frame, err := readNoiseFrame(nfh.reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	plaintext, err := nfh.decrypt.decrypt(nil, frame)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(plaintext) < 4 {
+		return 0, nil, errTruncatedMessage
+	}
+	size := int(binary.BigEndian.Uint16(plaintext[2:]))
+	if len(plaintext)-4 < size {
+		return 0, nil, errTruncatedMessage
+	}
+	return binary.BigEndian.Uint16(plaintext), plaintext[4 : 4+size], nil
+}
+
+// writeNoiseFrame writes every payload as its own frame in a single write.
+func writeNoiseFrame(conn net.Conn, payloads ...[]byte) error {
+	var frames []byte
+	for _, payload := range payloads {
+		if len(payload) > 0xffff {
+			return fmt.Errorf("frame too large: %d", len(payload))
+		}
+		frames = append(frames, noiseIndicator)
+		frames = binary.BigEndian.AppendUint16(frames, uint16(len(payload)))
+		frames = append(frames, payload...)
+	}
+	_, err := conn.Write(frames)
+	return err
+}
+
+func readNoiseFrame(reader *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 3)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+	switch header[0] {
+	case noiseIndicator:
+	case plaintextIndicator:
+		return nil, errEncryptionUnsupported
+	default:
+		return nil, fmt.Errorf("invalid frame indicator: 0x%02x", header[0])
+	}
+
+	frame := make([]byte, binary.BigEndian.Uint16(header[1:]))
+	if _, err := io.ReadFull(reader, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
@@ -0,0 +1,142 @@
+package esphome
+
+// Message types of the native api, https://github.com/esphome/esphome/blob/dev/esphome/components/api/api.proto
+const (
+	helloRequestType                       = 1
+	helloResponseType                      = 2
+	connectRequestType                     = 3
+	connectResponseType                    = 4
+	disconnectRequestType                  = 5
+	disconnectResponseType                 = 6
+	pingRequestType                        = 7
+	pingResponseType                       = 8
+	subscribeBluetoothAdvertisementsType   = 66
+	bluetoothAdvertisementResponseType     = 67
+	bluetoothRawAdvertisementsResponseType = 93
+	apiVersionMajor                        = 1
+	apiVersionMinor                        = 10
+	rawAdvertisementsSubscriptionFlag      = 1
+)
+
+func encodeHelloRequest(clientInfo string) []byte {
+	var b []byte
+	b = appendBytesField(b, 1, []byte(clientInfo))
+	b = appendVarintField(b, 2, apiVersionMajor)
+	return appendVarintField(b, 3, apiVersionMinor)
+}
+
+func encodeConnectRequest(password string) []byte {
+	if password == "" {
+		return nil
+	}
+	return appendBytesField(nil, 1, []byte(password))
+}
+
+func encodeSubscribeBluetoothAdvertisements(flags uint32) []byte {
+	return appendVarintField(nil, 1, uint64(flags))
+}
+
+type helloResponse struct {
+	apiVersionMajor uint32
+	serverInfo      string
+	name            string
+}
+
+func decodeHelloResponse(message []byte) (*helloResponse, error) {
+	var response helloResponse
+	err := decodeFields(message, func(field protoField) error {
+		switch field.number {
+		case 1:
+			response.apiVersionMajor = uint32(field.value)
+		case 3:
+			response.serverInfo = string(field.data)
+		case 4:
+			response.name = string(field.data)
+		}
+		return nil
+	})
+	return &response, err
+}
+
+func decodeConnectResponse(message []byte) (invalidPassword bool, err error) {
+	err = decodeFields(message, func(field protoField) error {
+		if field.number == 1 {
+			invalidPassword = field.value != 0
+		}
+		return nil
+	})
+	return invalidPassword, err
+}
+
+// rawAdvertisement is a received advertisement, data holds the raw advertising data structures.
+type rawAdvertisement struct {
+	address     uint64
+	rssi        int32
+	addressType uint32
+	data        []byte
+}
+
+func decodeRawAdvertisements(message []byte) ([]*rawAdvertisement, error) {
+	var advertisements []*rawAdvertisement
+	err := decodeFields(message, func(field protoField) error {
+		if field.number != 1 {
+			return nil
+		}
+		var advertisement rawAdvertisement
+		advertisements = append(advertisements, &advertisement)
+		return decodeFields(field.data, func(field protoField) error {
+			switch field.number {
+			case 1:
+				advertisement.address = field.value
+			case 2:
+				advertisement.rssi = zigzag(field.value)
+			case 3:
+				advertisement.addressType = uint32(field.value)
+			case 4:
+				advertisement.data = field.data
+			}
+			return nil
+		})
+	})
+	return advertisements, err
+}
+
+// decodeAdvertisement decodes the parsed advertisement sent by proxies which do not support raw advertisements.
+func decodeAdvertisement(message []byte) (*rawAdvertisement, *advertisementData, error) {
+	var advertisement rawAdvertisement
+	data := newAdvertisementData()
+	err := decodeFields(message, func(field protoField) error {
+		switch field.number {
+		case 1:
+			advertisement.address = field.value
+		case 2:
+			data.localName = string(field.data)
+		case 3:
+			advertisement.rssi = zigzag(field.value)
+		case 5, 6:
+			var uuid string
+			var value []byte
+			err := decodeFields(field.data, func(serviceField protoField) error {
+				switch serviceField.number {
+				case 1:
+					uuid = string(serviceField.data)
+				case 3:
+					value = serviceField.data
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if field.number == 5 {
+				data.serviceData[normalizeUuid(uuid)] = value
+			} else {
+				data.manufacturerData[normalizeCompanyId(uuid)] = value
+			}
+		case 7:
+			advertisement.addressType = uint32(field.value)
+		}
+		return nil
+	})
+	return &advertisement, data, err
+}
@@ -0,0 +1,241 @@
+package esphome
+
+import (
+	"crypto/ecdh"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// The encrypted native api uses Noise_NNpsk0_25519_ChaChaPoly_SHA256 with the base64 encoded api encryption key
+// as pre-shared key, https://noiseprotocol.org/noise.html
+const (
+	noiseProtocolName = "Noise_NNpsk0_25519_ChaChaPoly_SHA256"
+	noisePrologue     = "NoiseAPIInit\x00\x00"
+	noiseKeySize      = 32
+)
+
+var errNoiseDecrypt = errors.New("failed to decrypt message, the encryption key is likely wrong")
+
+// cipherState encrypts with chacha20-poly1305 using an incrementing nonce.
+type cipherState struct {
+	key    [noiseKeySize]byte
+	hasKey bool
+	nonce  uint64
+}
+
+func (cs *cipherState) initializeKey(key []byte) {
+	copy(cs.key[:], key)
+	cs.hasKey = true
+	cs.nonce = 0
+}
+
+func (cs *cipherState) nonceBytes() []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.LittleEndian.PutUint64(nonce[4:], cs.nonce)
+	return nonce
+}
+
+func (cs *cipherState) encrypt(ad, plaintext []byte) ([]byte, error) {
+	if !cs.hasKey {
+		return plaintext, nil
+	}
+	aead, err := chacha20poly1305.New(cs.key[:])
+	if err != nil {
+		return nil, err
+	}
+	ciphertext := aead.Seal(nil, cs.nonceBytes(), plaintext, ad)
+	cs.nonce++
+	return ciphertext, nil
+}
+
+func (cs *cipherState) decrypt(ad, ciphertext []byte) ([]byte, error) {
+	if !cs.hasKey {
+		return ciphertext, nil
+	}
+	aead, err := chacha20poly1305.New(cs.key[:])
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, cs.nonceBytes(), ciphertext, ad)
+	if err != nil {
+		return nil, errNoiseDecrypt
+	}
+	cs.nonce++
+	return plaintext, nil
+}
+
+// symmetricState holds the chaining key and handshake hash of the handshake.
+type symmetricState struct {
+	cipherState
+	chainingKey [sha256.Size]byte
+	hash        [sha256.Size]byte
+}
+
+func newSymmetricState() *symmetricState {
+	var ss symmetricState
+	if len(noiseProtocolName) <= sha256.Size {
+		copy(ss.hash[:], noiseProtocolName)
+	} else {
+		ss.hash = sha256.Sum256([]byte(noiseProtocolName))
+	}
+	ss.chainingKey = ss.hash
+	return &ss
+}
+
+func (ss *symmetricState) mixHash(data []byte) {
+	h := sha256.New()
+	h.Write(ss.hash[:])
+	h.Write(data)
+	copy(ss.hash[:], h.Sum(nil))
+}
+
+func (ss *symmetricState) mixKey(inputKeyMaterial []byte) {
+	outputs := hkdf(ss.chainingKey[:], inputKeyMaterial, 2)
+	copy(ss.chainingKey[:], outputs[0])
+	ss.initializeKey(outputs[1])
+}
+
+func (ss *symmetricState) mixKeyAndHash(inputKeyMaterial []byte) {
+	outputs := hkdf(ss.chainingKey[:], inputKeyMaterial, 3)
+	copy(ss.chainingKey[:], outputs[0])
+	ss.mixHash(outputs[1])
+	ss.initializeKey(outputs[2])
+}
+
+func (ss *symmetricState) encryptAndHash(plaintext []byte) ([]byte, error) {
+	ciphertext, err := ss.encrypt(ss.hash[:], plaintext)
+	if err != nil {
+		return nil, err
+	}
+	ss.mixHash(ciphertext)
+	return ciphertext, nil
+}
+
+func (ss *symmetricState) decryptAndHash(ciphertext []byte) ([]byte, error) {
+	plaintext, err := ss.decrypt(ss.hash[:], ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	ss.mixHash(ciphertext)
+	return plaintext, nil
+}
+
+// split returns the cipher states of the transport, the first one encrypts the messages of the initiator.
+func (ss *symmetricState) split() (*cipherState, *cipherState) {
+	outputs := hkdf(ss.chainingKey[:], nil, 2)
+	var initiator, responder cipherState
+	initiator.initializeKey(outputs[0])
+	responder.initializeKey(outputs[1])
+	return &initiator, &responder
+}
+
+// hkdf derives the outputs of the noise hkdf function.
+func hkdf(chainingKey, inputKeyMaterial []byte, outputCount int) [][]byte {
+	extract := hmac.New(sha256.New, chainingKey)
+	extract.Write(inputKeyMaterial)
+	tempKey := extract.Sum(nil)
+
+	outputs := make([][]byte, 0, outputCount)
+	var previous []byte
+	for i := 1; i <= outputCount; i++ {
+		expand := hmac.New(sha256.New, tempKey)
+		expand.Write(previous)
+		expand.Write([]byte{byte(i)})
+		previous = expand.Sum(nil)
+		outputs = append(outputs, previous)
+	}
+	return outputs
+}
+
+// handshakeState runs the NNpsk0 handshake, -> psk, e and <- e, ee.
+type handshakeState struct {
+	*symmetricState
+	psk       []byte
+	ephemeral *ecdh.PrivateKey
+}
+
+func newHandshakeState(psk []byte) (*handshakeState, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return initHandshakeState(psk, []byte(noisePrologue), ephemeral)
+}
+
+// initHandshakeState starts the handshake with the given prologue and ephemeral key.
+func initHandshakeState(psk, prologue []byte, ephemeral *ecdh.PrivateKey) (*handshakeState, error) {
+	if len(psk) != noiseKeySize {
+		return nil, fmt.Errorf("invalid encryption key length: %d", len(psk))
+	}
+
+	hs := &handshakeState{
+		symmetricState: newSymmetricState(),
+		psk:            psk,
+		ephemeral:      ephemeral,
+	}
+	hs.mixHash(prologue)
+	return hs, nil
+}
+
+// writeEphemeral writes the e token, in psk handshakes the ephemeral key is mixed into the key as well.
+func (hs *handshakeState) writeEphemeral() []byte {
+	publicKey := hs.ephemeral.PublicKey().Bytes()
+	hs.mixHash(publicKey)
+	hs.mixKey(publicKey)
+	return publicKey
+}
+
+func (hs *handshakeState) readEphemeral(message []byte) (*ecdh.PublicKey, []byte, error) {
+	if len(message) < noiseKeySize {
+		return nil, nil, errors.New("handshake message too short")
+	}
+	publicKey, err := ecdh.X25519().NewPublicKey(message[:noiseKeySize])
+	if err != nil {
+		return nil, nil, err
+	}
+	hs.mixHash(message[:noiseKeySize])
+	hs.mixKey(message[:noiseKeySize])
+	return publicKey, message[noiseKeySize:], nil
+}
+
+func (hs *handshakeState) mixDH(remoteEphemeral *ecdh.PublicKey) error {
+	sharedSecret, err := hs.ephemeral.ECDH(remoteEphemeral)
+	if err != nil {
+		return err
+	}
+	hs.mixKey(sharedSecret)
+	return nil
+}
+
+// writeInitiatorMessage returns the first handshake message of the initiator.
+func (hs *handshakeState) writeInitiatorMessage() ([]byte, error) {
+	hs.mixKeyAndHash(hs.psk)
+	message := hs.writeEphemeral()
+	payload, err := hs.encryptAndHash(nil)
+	if err != nil {
+		return nil, err
+	}
+	return append(message, payload...), nil
+}
+
+// readResponderMessage reads the handshake response on the initiator and returns its encrypt and decrypt cipher states.
+func (hs *handshakeState) readResponderMessage(message []byte) (*cipherState, *cipherState, error) {
+	remoteEphemeral, rest, err := hs.readEphemeral(message)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := hs.mixDH(remoteEphemeral); err != nil {
+		return nil, nil, err
+	}
+	if _, err := hs.decryptAndHash(rest); err != nil {
+		return nil, nil, err
+	}
+	initiator, responder := hs.split()
+	return initiator, responder, nil
+}
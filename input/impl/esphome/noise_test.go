@@ -0,0 +1,121 @@
+package esphome
+
+import (
+	"crypto/ecdh"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readInitiatorMessage reads the first handshake message on the responder.
+func (hs *handshakeState) readInitiatorMessage(message []byte) (*ecdh.PublicKey, error) {
+	hs.mixKeyAndHash(hs.psk)
+	remoteEphemeral, rest, err := hs.readEphemeral(message)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := hs.decryptAndHash(rest); err != nil {
+		return nil, err
+	}
+	return remoteEphemeral, nil
+}
+
+// writeResponderMessage returns the handshake response of the responder and its transport cipher states.
+func (hs *handshakeState) writeResponderMessage(remoteEphemeral *ecdh.PublicKey) ([]byte, *cipherState, *cipherState, error) {
+	message := hs.writeEphemeral()
+	if err := hs.mixDH(remoteEphemeral); err != nil {
+		return nil, nil, nil, err
+	}
+	payload, err := hs.encryptAndHash(nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	initiator, responder := hs.split()
+	return append(message, payload...), responder, initiator, nil
+}
+
+// TestHandshake runs the Noise_NNpsk0_25519_ChaChaPoly_SHA256 test vectors of the cacophony format published with
+// github.com/flynn/noise (vectors.txt), the initiator messages and transport cipher states have to match them exactly.
+func TestHandshake(t *testing.T) {
+	const (
+		initiatorEphemeral = "202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f"
+		responderEphemeral = "4142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60"
+		psk                = "2176657279736563726574766572797365637265747665727973656372657421"
+		initiatorPayload   = "79656c6c6f777375626d6172696e65"
+		responderPayload   = "7375626d6172696e6579656c6c6f77"
+	)
+
+	tests := []struct {
+		name                string
+		prologue            string
+		initiatorMessage    string
+		responderMessage    string
+		initiatorCiphertext string
+		responderCiphertext string
+	}{
+		{
+			name:                "empty prologue",
+			initiatorMessage:    "358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd166254e7136508cb8178281204abd62e9f2a3e",
+			responderMessage:    "64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d48466922f3b7824001193c077abd8b7a73030",
+			initiatorCiphertext: "b349a522c145762c7c737ac1d1425ce1fb25c7cca626177ee4ceed3cd6fb3d",
+			responderCiphertext: "b41e24399dc3f1ad2faf82868700e4bf31bb89f6616e1d6a92802bb8ad80d6",
+		},
+		{
+			name:                "prologue",
+			prologue:            "6e6f74736563726574",
+			initiatorMessage:    "358072d6365880d1aeea329adf9121383851ed21a28e3b75e965d0d2cd1662546e96a20116b68fd776478e81d11779ca",
+			responderMessage:    "64b101b1d0be5a8704bd078f9895001fc03e8e9f9522f188dd128d9846d4846666f51bc44f88917daa53fb4529499b55",
+			initiatorCiphertext: "b349a522c145762c7c737ac1d1425ce1fb25c7cca626177ee4ceed3cd6fb3d",
+			responderCiphertext: "b41e24399dc3f1ad2faf82868700e4bf31bb89f6616e1d6a92802bb8ad80d6",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initiator, err := initHandshakeState(decodeHex(t, psk), decodeHex(t, tt.prologue), privateKey(t, initiatorEphemeral))
+			require.NoError(t, err)
+			responder, err := initHandshakeState(decodeHex(t, psk), decodeHex(t, tt.prologue), privateKey(t, responderEphemeral))
+			require.NoError(t, err)
+
+			initiatorMessage, err := initiator.writeInitiatorMessage()
+			require.NoError(t, err)
+			assert.Equal(t, tt.initiatorMessage, hex.EncodeToString(initiatorMessage))
+
+			remoteEphemeral, err := responder.readInitiatorMessage(initiatorMessage)
+			require.NoError(t, err)
+			responderMessage, responderEncrypt, responderDecrypt, err := responder.writeResponderMessage(remoteEphemeral)
+			require.NoError(t, err)
+			assert.Equal(t, tt.responderMessage, hex.EncodeToString(responderMessage))
+
+			encrypt, decrypt, err := initiator.readResponderMessage(decodeHex(t, tt.responderMessage))
+			require.NoError(t, err)
+
+			ciphertext, err := encrypt.encrypt(nil, decodeHex(t, initiatorPayload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.initiatorCiphertext, hex.EncodeToString(ciphertext))
+			plaintext, err := responderDecrypt.decrypt(nil, ciphertext)
+			require.NoError(t, err)
+			assert.Equal(t, initiatorPayload, hex.EncodeToString(plaintext))
+
+			ciphertext, err = responderEncrypt.encrypt(nil, decodeHex(t, responderPayload))
+			require.NoError(t, err)
+			assert.Equal(t, tt.responderCiphertext, hex.EncodeToString(ciphertext))
+			plaintext, err = decrypt.decrypt(nil, decodeHex(t, tt.responderCiphertext))
+			require.NoError(t, err)
+			assert.Equal(t, responderPayload, hex.EncodeToString(plaintext))
+		})
+	}
+}
+
+func decodeHex(t *testing.T, value string) []byte {
+	decoded, err := hex.DecodeString(value)
+	require.NoError(t, err)
+	return decoded
+}
+
+func privateKey(t *testing.T, value string) *ecdh.PrivateKey {
+	key, err := ecdh.X25519().NewPrivateKey(decodeHex(t, value))
+	require.NoError(t, err)
+	return key
+}
@@ -0,0 +1,87 @@
+package esphome
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The native api messages are protocol buffers, only the few fields used by the input are encoded and decoded by hand.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncatedMessage = errors.New("truncated message")
+
+func appendVarintField(b []byte, field int, value uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireVarint)
+	return binary.AppendUvarint(b, value)
+}
+
+func appendBytesField(b []byte, field int, value []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+// protoField is a decoded field, value holds varints and fixed values, data holds length delimited values.
+type protoField struct {
+	number int
+	value  uint64
+	data   []byte
+}
+
+// decodeFields calls fn with every field of the message in order.
+func decodeFields(message []byte, fn func(field protoField) error) error {
+	for len(message) != 0 {
+		key, n := binary.Uvarint(message)
+		if n <= 0 {
+			return errTruncatedMessage
+		}
+		message = message[n:]
+
+		field := protoField{number: int(key >> 3)}
+		switch key & 7 {
+		case wireVarint:
+			field.value, n = binary.Uvarint(message)
+			if n <= 0 {
+				return errTruncatedMessage
+			}
+			message = message[n:]
+		case wireFixed64:
+			if len(message) < 8 {
+				return errTruncatedMessage
+			}
+			field.value = binary.LittleEndian.Uint64(message)
+			message = message[8:]
+		case wireBytes:
+			length, n := binary.Uvarint(message)
+			if n <= 0 || uint64(len(message)-n) < length {
+				return errTruncatedMessage
+			}
+			field.data = message[n : n+int(length)]
+			message = message[n+int(length):]
+		case wireFixed32:
+			if len(message) < 4 {
+				return errTruncatedMessage
+			}
+			field.value = uint64(binary.LittleEndian.Uint32(message))
+			message = message[4:]
+		default:
+			return fmt.Errorf("unsupported wire type %d of field %d", key&7, field.number)
+		}
+
+		if err := fn(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// zigzag decodes a sint32 value.
+func zigzag(value uint64) int32 {
+	return int32(uint32(value>>1) ^ -uint32(value&1))
+}
@@ -1,6 +1,7 @@
 package impl
 
 import (
+	_ "github.com/nikiforov-soft/yasp/input/impl/esphome"
 	_ "github.com/nikiforov-soft/yasp/input/impl/memphis"
 	_ "github.com/nikiforov-soft/yasp/input/impl/mqtt"
 )
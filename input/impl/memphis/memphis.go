@@ -31,11 +31,15 @@ type memphisInput struct {
 	consumersLock      sync.Mutex
 	activeChannels     []chan *input.Data
 	activeChannelsLock sync.Mutex
+	// closed unblocks the message handler waiting for a reader that went away, so Close does not deadlock.
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
 func newMemphisInput(_ context.Context, config *config.MemphisInput) (input.Input, error) {
 	return &memphisInput{
 		config: config,
+		closed: make(chan struct{}),
 	}, nil
 }
 
@@ -71,6 +75,10 @@ func (mi *memphisInput) Subscribe(ctx context.Context) (<-chan *input.Data, erro
 }
 
 func (mi *memphisInput) Close(_ context.Context) error {
+	mi.closeOnce.Do(func() {
+		close(mi.closed)
+	})
+
 	mi.activeChannelsLock.Lock()
 	defer mi.activeChannelsLock.Unlock()
 
@@ -97,6 +105,12 @@ func (mi *memphisInput) messageHandler(messages []*memphis.Msg, err error, ctx c
 
 	mi.activeChannelsLock.Lock()
 	defer mi.activeChannelsLock.Unlock()
+	select {
+	case <-mi.closed:
+		// the channels are closed already
+		return
+	default:
+	}
 
 	for _, message := range messages {
 		logrus.
@@ -138,7 +152,11 @@ func (mi *memphisInput) messageHandler(messages []*memphis.Msg, err error, ctx c
 		}
 
 		for _, channel := range mi.activeChannels {
-			channel <- data
+			select {
+			case channel <- data:
+			case <-mi.closed:
+				return
+			}
 		}
 
 		eventsProcessedCounter.WithLabelValues(mi.config.Station).Inc()
@@ -146,8 +164,23 @@ func (mi *memphisInput) messageHandler(messages []*memphis.Msg, err error, ctx c
 }
 
 func init() {
-	err := input.RegisterInput("memphis", func(ctx context.Context, config *config.Input) (input.Input, error) {
-		return newMemphisInput(ctx, config.Memphis)
+	err := input.RegisterInput("memphis", func(ctx context.Context, options config.Options) (input.Input, error) {
+		var memphisConfig config.MemphisInput
+		if err := options.Decode(&memphisConfig); err != nil {
+			return nil, fmt.Errorf("memphis input: failed to decode options: %w", err)
+		}
+		return newMemphisInput(ctx, &memphisConfig)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	err = input.RegisterInputValidator("memphis", func(options config.Options) error {
+		var memphisConfig config.MemphisInput
+		if err := options.DecodeStrict(&memphisConfig); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+		return memphisConfig.Validate()
 	})
 	if err != nil {
 		panic(err)
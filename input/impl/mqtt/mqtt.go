@@ -31,11 +31,15 @@ type mqttInput struct {
 	connectionManagersLock sync.Mutex
 	activeChannels         []chan *input.Data
 	activeChannelsLock     sync.Mutex
+	// closed unblocks the message handler waiting for a reader that went away, so Close does not deadlock.
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
 func newMqttInput(_ context.Context, config *config.MqttInput) (input.Input, error) {
 	return &mqttInput{
 		config: config,
+		closed: make(chan struct{}),
 	}, nil
 }
 
@@ -107,6 +111,10 @@ func (mi *mqttInput) Subscribe(ctx context.Context) (<-chan *input.Data, error)
 }
 
 func (mi *mqttInput) Close(ctx context.Context) error {
+	mi.closeOnce.Do(func() {
+		close(mi.closed)
+	})
+
 	mi.activeChannelsLock.Lock()
 	defer mi.activeChannelsLock.Unlock()
 
@@ -128,6 +136,12 @@ func (mi *mqttInput) Close(ctx context.Context) error {
 func (mi *mqttInput) messageHandler(publish *paho.Publish) {
 	mi.activeChannelsLock.Lock()
 	defer mi.activeChannelsLock.Unlock()
+	select {
+	case <-mi.closed:
+		// the channels are closed already
+		return
+	default:
+	}
 
 	eventsProcessedCounter.WithLabelValues(publish.Topic).Inc()
 
@@ -137,7 +151,7 @@ func (mi *mqttInput) messageHandler(publish *paho.Publish) {
 		Debug("input received")
 
 	for _, channel := range mi.activeChannels {
-		channel <- &input.Data{
+		data := &input.Data{
 			Data: publish.Payload,
 			Properties: map[string]interface{}{
 				"inputId":         publish.PacketID,
@@ -147,12 +161,32 @@ func (mi *mqttInput) messageHandler(publish *paho.Publish) {
 				"inputProperties": publish.Properties,
 			},
 		}
+		select {
+		case channel <- data:
+		case <-mi.closed:
+			return
+		}
 	}
 }
 
 func init() {
-	err := input.RegisterInput("mqtt", func(ctx context.Context, config *config.Input) (input.Input, error) {
-		return newMqttInput(ctx, config.Mqtt)
+	err := input.RegisterInput("mqtt", func(ctx context.Context, options config.Options) (input.Input, error) {
+		var mqttConfig config.MqttInput
+		if err := options.Decode(&mqttConfig); err != nil {
+			return nil, fmt.Errorf("mqtt input: failed to decode options: %w", err)
+		}
+		return newMqttInput(ctx, &mqttConfig)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	err = input.RegisterInputValidator("mqtt", func(options config.Options) error {
+		var mqttConfig config.MqttInput
+		if err := options.DecodeStrict(&mqttConfig); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+		return mqttConfig.Validate()
 	})
 	if err != nil {
 		panic(err)
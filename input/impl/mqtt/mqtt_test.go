@@ -0,0 +1,44 @@
+package mqtt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+)
+
+func TestCloseWithBlockedMessageHandler(t *testing.T) {
+	in, err := newMqttInput(context.Background(), &config.MqttInput{})
+	assert.NoError(t, err)
+	mi := in.(*mqttInput)
+	// nobody reads the channel anymore, like a sensor group stopped by a reload
+	mi.activeChannels = append(mi.activeChannels, make(chan *input.Data))
+
+	handled := make(chan struct{})
+	go func() {
+		defer close(handled)
+		mi.messageHandler(&paho.Publish{Topic: "ble/kitchen", Payload: []byte("payload")})
+	}()
+
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		assert.NoError(t, mi.Close(context.Background()))
+	}()
+
+	for _, done := range []chan struct{}{handled, closed} {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("close deadlocked with the message handler")
+		}
+	}
+
+	// messages arriving after close are dropped instead of sent on a closed channel
+	mi.messageHandler(&paho.Publish{Topic: "ble/kitchen", Payload: []byte("payload")})
+}
@@ -14,10 +14,11 @@ var ErrInputNotFound = errors.New("input not found")
 
 var (
 	inputs     = make(map[string]Factory)
+	validators = make(map[string]Validator)
 	inputsLock sync.RWMutex
 )
 
-func NewInput(ctx context.Context, inputName string, config *config.Input) (Input, error) {
+func NewInput(ctx context.Context, inputName string, options config.Options) (Input, error) {
 	inputsLock.RLock()
 	defer inputsLock.RUnlock()
 
@@ -25,7 +26,15 @@ func NewInput(ctx context.Context, inputName string, config *config.Input) (Inpu
 	if inputFactory == nil {
 		return nil, fmt.Errorf("%w: %s", ErrInputNotFound, inputName)
 	}
-	return inputFactory(ctx, config)
+	return inputFactory(ctx, options)
+}
+
+func HasInput(inputName string) bool {
+	inputsLock.RLock()
+	defer inputsLock.RUnlock()
+
+	_, exists := inputs[strings.ToLower(inputName)]
+	return exists
 }
 
 func RegisterInput(inputName string, inputFactory Factory) error {
@@ -39,3 +48,32 @@ func RegisterInput(inputName string, inputFactory Factory) error {
 	inputs[key] = inputFactory
 	return nil
 }
+
+// ValidateInput reports the problems of the options of the input without creating it, the options of an input without a
+// registered validator are not checked.
+func ValidateInput(inputName string, options config.Options) error {
+	inputsLock.RLock()
+	defer inputsLock.RUnlock()
+
+	key := strings.ToLower(inputName)
+	if _, exists := inputs[key]; !exists {
+		return fmt.Errorf("%w: %s", ErrInputNotFound, inputName)
+	}
+	validator := validators[key]
+	if validator == nil {
+		return nil
+	}
+	return validator(options)
+}
+
+func RegisterInputValidator(inputName string, validator Validator) error {
+	inputsLock.Lock()
+	defer inputsLock.Unlock()
+
+	key := strings.ToLower(inputName)
+	if _, exists := validators[key]; exists {
+		return fmt.Errorf("input validator name: %s already exists", inputName)
+	}
+	validators[key] = validator
+	return nil
+}
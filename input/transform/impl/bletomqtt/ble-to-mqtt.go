@@ -15,6 +15,7 @@ import (
 
 const (
 	serviceDataKey = "serviceDataKey"
+	gatewayKey     = "gateway"
 )
 
 type InputEventData struct {
@@ -23,15 +24,18 @@ type InputEventData struct {
 	MacAddress  string            `json:"mac_address"`
 	LocalName   string            `json:"local_name"`
 	ServiceData map[string]string `json:"service_data"`
+	Rssi        *int              `json:"rssi"`
+	Gateway     string            `json:"gateway"`
 }
 
 type bleToMqtt struct {
-	config *config.Transform
-	sdk    string
+	config  *config.Transform
+	sdk     string
+	gateway string
 }
 
 func (btm *bleToMqtt) Transform(_ context.Context, data *input.Data) (*input.Data, error) {
-	var inputEventData *InputEventData
+	var inputEventData InputEventData
 	if err := json.Unmarshal(data.Data, &inputEventData); err != nil {
 		return nil, fmt.Errorf("ble-to-mqtt transform: failed to json decode input event data: %w", err)
 	}
@@ -46,7 +50,7 @@ func (btm *bleToMqtt) Transform(_ context.Context, data *input.Data) (*input.Dat
 			return nil, fmt.Errorf("ble-to-mqtt transform: failed to hex decode service data: %w", err)
 		}
 
-		properties := make(map[string]interface{}, len(data.Properties)+4)
+		properties := make(map[string]interface{}, len(data.Properties)+8)
 		for k, v := range data.Properties {
 			properties[k] = v
 		}
@@ -54,6 +58,14 @@ func (btm *bleToMqtt) Transform(_ context.Context, data *input.Data) (*input.Dat
 		properties["bleToMqttEvent"] = inputEventData.Event
 		properties["bleToMqttMacAddress"] = inputEventData.MacAddress
 		properties["bleToMqttLocalName"] = inputEventData.LocalName
+		properties[input.MacAddressPropertyKey] = inputEventData.MacAddress
+		if inputEventData.Rssi != nil {
+			properties["bleToMqttRssi"] = *inputEventData.Rssi
+			properties[input.RssiPropertyKey] = *inputEventData.Rssi
+		}
+		if gateway := btm.gatewayName(&inputEventData, data.Properties); gateway != "" {
+			properties[input.GatewayPropertyKey] = gateway
+		}
 		return &input.Data{
 			Data:       payload,
 			Properties: properties,
@@ -62,6 +74,18 @@ func (btm *bleToMqtt) Transform(_ context.Context, data *input.Data) (*input.Dat
 	return nil, nil
 }
 
+// gatewayName returns the gateway reported in the event, the gateway configured on the transform or the name of the input.
+func (btm *bleToMqtt) gatewayName(inputEventData *InputEventData, properties map[string]interface{}) string {
+	if inputEventData.Gateway != "" {
+		return inputEventData.Gateway
+	}
+	if btm.gateway != "" {
+		return btm.gateway
+	}
+	inputName, _ := properties[input.NamePropertyKey].(string)
+	return inputName
+}
+
 func init() {
 	err := transform.RegisterTransform("ble-to-mqtt", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
 		sdk, exists := config.Properties[serviceDataKey]
@@ -69,8 +93,9 @@ func init() {
 			return nil, fmt.Errorf("ble-to-mqtt transform: missing config property: %s", serviceDataKey)
 		}
 		return &bleToMqtt{
-			config: config,
-			sdk:    sdk,
+			config:  config,
+			sdk:     sdk,
+			gateway: config.Properties[gatewayKey],
 		}, nil
 	})
 	if err != nil {
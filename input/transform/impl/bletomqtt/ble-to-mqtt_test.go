@@ -0,0 +1,81 @@
+package bletomqtt
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+func TestBleToMqtt(t *testing.T) {
+	tests := []struct {
+		name               string
+		payload            string
+		expectedData       []byte
+		expectedProperties map[string]interface{}
+		dropped            bool
+		expectedError      string
+	}{
+		{
+			name:         "service data",
+			payload:      `{"id":"1","event":"advertisement","mac_address":"a4:c1:38:ab:cd:ef","local_name":"ATC","service_data":{"181a":"a4c138abcdef"},"rssi":-70,"gateway":"esp32"}`,
+			expectedData: []byte{0xa4, 0xc1, 0x38, 0xab, 0xcd, 0xef},
+			expectedProperties: map[string]interface{}{
+				"inputName":           "ble",
+				"bleToMqttId":         "1",
+				"bleToMqttEvent":      "advertisement",
+				"bleToMqttMacAddress": "a4:c1:38:ab:cd:ef",
+				"bleToMqttLocalName":  "ATC",
+				"bleToMqttRssi":       -70,
+				"macAddress":          "a4:c1:38:ab:cd:ef",
+				"rssi":                -70,
+				"gateway":             "esp32",
+			},
+		},
+		{
+			name:    "other service data",
+			payload: `{"mac_address":"a4:c1:38:ab:cd:ef","service_data":{"fe95":"00"}}`,
+			dropped: true,
+		},
+		{
+			name:    "null payload",
+			payload: `null`,
+			dropped: true,
+		},
+		{
+			name:          "invalid service data",
+			payload:       `{"mac_address":"a4:c1:38:ab:cd:ef","service_data":{"181a":"zz"}}`,
+			expectedError: "ble-to-mqtt transform: failed to hex decode service data: encoding/hex: invalid byte: U+007A 'z'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := transform.NewTransform(context.Background(), &config.Transform{
+				Name:       "ble-to-mqtt",
+				Properties: map[string]string{"serviceDataKey": "181a"},
+			})
+			require.NoError(t, err)
+
+			data, err := tr.Transform(context.Background(), &input.Data{
+				Data:       []byte(tt.payload),
+				Properties: map[string]interface{}{"inputName": "ble"},
+			})
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.dropped {
+				assert.Nil(t, data)
+				return
+			}
+			assert.Equal(t, tt.expectedData, data.Data)
+			assert.Equal(t, tt.expectedProperties, data.Properties)
+		})
+	}
+}
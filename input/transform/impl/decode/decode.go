@@ -0,0 +1,121 @@
+package decode
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+const (
+	encodingKey = "encoding"
+	sourceKey   = "source"
+	maxSizeKey  = "maxSize"
+
+	defaultMaxSize = 4 << 20
+)
+
+// decoders decode the value, the decompressing ones fail once the decoded value exceeds maxSize bytes.
+var decoders = map[string]func(value []byte, maxSize int64) ([]byte, error){
+	"hex": func(value []byte, _ int64) ([]byte, error) {
+		return hex.DecodeString(strings.TrimSpace(string(value)))
+	},
+	"base64": func(value []byte, _ int64) ([]byte, error) {
+		return base64.StdEncoding.DecodeString(strings.TrimSpace(string(value)))
+	},
+	"base64url": func(value []byte, _ int64) ([]byte, error) {
+		return base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(string(value)), "="))
+	},
+	"gzip": func(value []byte, maxSize int64) ([]byte, error) {
+		reader, err := gzip.NewReader(bytes.NewReader(value))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		decoded, err := io.ReadAll(io.LimitReader(reader, maxSize+1))
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(decoded)) > maxSize {
+			return nil, fmt.Errorf("decoded data exceeds %d bytes", maxSize)
+		}
+		return decoded, nil
+	},
+}
+
+// decode replaces the data with the data, or the source property, decoded by the comma separated encodings in order.
+type decode struct {
+	encodings []string
+	source    string
+	maxSize   int64
+}
+
+func (d *decode) Transform(_ context.Context, data *input.Data) (*input.Data, error) {
+	value := data.Data
+	if d.source != "" {
+		switch sourceValue := data.Properties[d.source].(type) {
+		case string:
+			value = []byte(sourceValue)
+		case []byte:
+			value = sourceValue
+		default:
+			return nil, fmt.Errorf("decode transform: missing source property: %s", d.source)
+		}
+	}
+
+	for _, encoding := range d.encodings {
+		decoded, err := decoders[encoding](value, d.maxSize)
+		if err != nil {
+			return nil, fmt.Errorf("decode transform: failed to %s decode data: %w", encoding, err)
+		}
+		value = decoded
+	}
+	return &input.Data{
+		Data:       value,
+		Properties: make(map[string]interface{}),
+	}, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("decode", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		encodingValue, exists := config.Properties[encodingKey]
+		if !exists {
+			return nil, fmt.Errorf("decode transform: missing config property: %s", encodingKey)
+		}
+
+		var encodings []string
+		for _, encoding := range strings.Split(encodingValue, ",") {
+			encoding = strings.ToLower(strings.TrimSpace(encoding))
+			if _, exists := decoders[encoding]; !exists {
+				return nil, fmt.Errorf("decode transform: unsupported encoding: %s", encoding)
+			}
+			encodings = append(encodings, encoding)
+		}
+
+		maxSize := int64(defaultMaxSize)
+		if maxSizeValue, exists := config.Properties[maxSizeKey]; exists {
+			value, err := strconv.ParseInt(maxSizeValue, 10, 64)
+			if err != nil || value <= 0 {
+				return nil, fmt.Errorf("decode transform: invalid %s property value: %s", maxSizeKey, maxSizeValue)
+			}
+			maxSize = value
+		}
+		return &decode{
+			encodings: encodings,
+			source:    config.Properties[sourceKey],
+			maxSize:   maxSize,
+		}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
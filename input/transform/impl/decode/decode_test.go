@@ -0,0 +1,115 @@
+package decode
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+func TestDecode(t *testing.T) {
+	var gzipped bytes.Buffer
+	writer := gzip.NewWriter(&gzipped)
+	_, _ = writer.Write([]byte("compressed"))
+	require.NoError(t, writer.Close())
+
+	var bomb bytes.Buffer
+	writer = gzip.NewWriter(&bomb)
+	_, _ = writer.Write(make([]byte, defaultMaxSize+1))
+	require.NoError(t, writer.Close())
+
+	tests := []struct {
+		name          string
+		properties    map[string]string
+		data          input.Data
+		expected      []byte
+		expectedError string
+	}{
+		{
+			name:       "hex",
+			properties: map[string]string{"encoding": "hex"},
+			data:       input.Data{Data: []byte("30585b\n")},
+			expected:   []byte{0x30, 0x58, 0x5b},
+		},
+		{
+			name:       "base64 source property",
+			properties: map[string]string{"encoding": "base64", "source": "payload"},
+			data:       input.Data{Properties: map[string]interface{}{"payload": "aGVsbG8="}},
+			expected:   []byte("hello"),
+		},
+		{
+			name:       "base64url",
+			properties: map[string]string{"encoding": "base64url"},
+			data:       input.Data{Data: []byte("_-8=")},
+			expected:   []byte{0xff, 0xef},
+		},
+		{
+			name:       "base64 then gzip",
+			properties: map[string]string{"encoding": "base64, gzip"},
+			data:       input.Data{Data: []byte(base64.StdEncoding.EncodeToString(gzipped.Bytes()))},
+			expected:   []byte("compressed"),
+		},
+		{
+			name:       "gzip within max size",
+			properties: map[string]string{"encoding": "gzip", "maxSize": "10"},
+			data:       input.Data{Data: gzipped.Bytes()},
+			expected:   []byte("compressed"),
+		},
+		{
+			name:          "gzip exceeding max size",
+			properties:    map[string]string{"encoding": "gzip", "maxSize": "9"},
+			data:          input.Data{Data: gzipped.Bytes()},
+			expectedError: "decode transform: failed to gzip decode data: decoded data exceeds 9 bytes",
+		},
+		{
+			name:          "gzip exceeding default max size",
+			properties:    map[string]string{"encoding": "gzip"},
+			data:          input.Data{Data: bomb.Bytes()},
+			expectedError: "decode transform: failed to gzip decode data: decoded data exceeds 4194304 bytes",
+		},
+		{
+			name:          "invalid max size",
+			properties:    map[string]string{"encoding": "gzip", "maxSize": "4MB"},
+			expectedError: "decode transform: invalid maxSize property value: 4MB",
+		},
+		{
+			name:          "invalid hex",
+			properties:    map[string]string{"encoding": "hex"},
+			data:          input.Data{Data: []byte("xyz")},
+			expectedError: "decode transform: failed to hex decode data: encoding/hex: invalid byte: U+0078 'x'",
+		},
+		{
+			name:          "unsupported encoding",
+			properties:    map[string]string{"encoding": "rot13"},
+			expectedError: "decode transform: unsupported encoding: rot13",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := transform.NewTransform(context.Background(), &config.Transform{
+				Name:       "decode",
+				Properties: tt.properties,
+			})
+			if err == nil {
+				var data *input.Data
+				data, err = tr.Transform(context.Background(), &tt.data)
+				if err == nil {
+					assert.Equal(t, tt.expected, data.Data)
+				}
+			}
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -2,4 +2,9 @@ package impl
 
 import (
 	_ "github.com/nikiforov-soft/yasp/input/transform/impl/bletomqtt"
+	_ "github.com/nikiforov-soft/yasp/input/transform/impl/decode"
+	_ "github.com/nikiforov-soft/yasp/input/transform/impl/jsonextract"
+	_ "github.com/nikiforov-soft/yasp/input/transform/impl/openmqttgateway"
+	_ "github.com/nikiforov-soft/yasp/input/transform/impl/regex"
+	_ "github.com/nikiforov-soft/yasp/input/transform/impl/topicsplit"
 )
@@ -0,0 +1,84 @@
+package jsonextract
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+const (
+	dataKey = "data"
+)
+
+// jsonExtract replaces the data with the value at the data path and sets every other configured property to the
+// value at its path, an empty data path keeps the payload, events without a value at the data path are dropped,
+// missing properties are skipped.
+type jsonExtract struct {
+	dataPath   []pathSegment
+	properties map[string][]pathSegment
+}
+
+func (je *jsonExtract) Transform(_ context.Context, data *input.Data) (*input.Data, error) {
+	var payload any
+	if err := json.Unmarshal(data.Data, &payload); err != nil {
+		return nil, fmt.Errorf("json-extract transform: failed to json decode data: %w", err)
+	}
+
+	transformData := &input.Data{
+		Data:       data.Data,
+		Properties: make(map[string]interface{}, len(je.properties)),
+	}
+	if len(je.dataPath) != 0 {
+		value, exists := lookup(payload, je.dataPath)
+		if !exists {
+			return nil, nil
+		}
+		if stringValue, ok := value.(string); ok {
+			transformData.Data = []byte(stringValue)
+		} else {
+			valueBytes, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("json-extract transform: failed to json encode data: %w", err)
+			}
+			transformData.Data = valueBytes
+		}
+	}
+
+	for property, path := range je.properties {
+		if value, exists := lookup(payload, path); exists {
+			transformData.Properties[property] = value
+		}
+	}
+	return transformData, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("json-extract", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		if len(config.Properties) == 0 {
+			return nil, fmt.Errorf("json-extract transform: no paths configured")
+		}
+
+		je := &jsonExtract{
+			properties: make(map[string][]pathSegment, len(config.Properties)),
+		}
+		for property, pathValue := range config.Properties {
+			path, err := parsePath(pathValue)
+			if err != nil {
+				return nil, fmt.Errorf("json-extract transform: invalid %s path: %w", property, err)
+			}
+			if property == dataKey {
+				je.dataPath = path
+				continue
+			}
+			je.properties[property] = path
+		}
+		return je, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,94 @@
+package jsonextract
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+const payload = `{"Time":"2024-01-01T00:00:00","AM2301":{"Temperature":21.5,"Humidity":40},"ids":["a","b"],"dotted.key":true}`
+
+func TestJsonExtract(t *testing.T) {
+	tests := []struct {
+		name               string
+		properties         map[string]string
+		expectedData       string
+		expectedProperties map[string]interface{}
+		dropped            bool
+		expectedError      string
+	}{
+		{
+			name:               "number as data",
+			properties:         map[string]string{"data": ".AM2301.Temperature"},
+			expectedData:       "21.5",
+			expectedProperties: map[string]interface{}{},
+		},
+		{
+			name:               "object as data",
+			properties:         map[string]string{"data": "AM2301"},
+			expectedData:       `{"Humidity":40,"Temperature":21.5}`,
+			expectedProperties: map[string]interface{}{},
+		},
+		{
+			name:               "whole payload",
+			properties:         map[string]string{"data": "", "time": ".Time"},
+			expectedData:       payload,
+			expectedProperties: map[string]interface{}{"time": "2024-01-01T00:00:00"},
+		},
+		{
+			name: "properties",
+			properties: map[string]string{
+				"humidity": ".AM2301.Humidity",
+				"firstId":  ".ids[0]",
+				"lastId":   ".ids[-1]",
+				"dotted":   `.["dotted.key"]`,
+				"missing":  ".AM2301.Pressure",
+			},
+			expectedData: payload,
+			expectedProperties: map[string]interface{}{
+				"humidity": float64(40),
+				"firstId":  "a",
+				"lastId":   "b",
+				"dotted":   true,
+			},
+		},
+		{
+			name:       "missing data",
+			properties: map[string]string{"data": ".DS18B20.Temperature"},
+			dropped:    true,
+		},
+		{
+			name:          "invalid path",
+			properties:    map[string]string{"data": ".ids[first]"},
+			expectedError: "json-extract transform: invalid data path: invalid index first in path: .ids[first]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := transform.NewTransform(context.Background(), &config.Transform{
+				Name:       "json-extract",
+				Properties: tt.properties,
+			})
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			data, err := tr.Transform(context.Background(), &input.Data{Data: []byte(payload)})
+			require.NoError(t, err)
+			if tt.dropped {
+				assert.Nil(t, data)
+				return
+			}
+			assert.Equal(t, tt.expectedData, string(data.Data))
+			assert.Equal(t, tt.expectedProperties, data.Properties)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package jsonextract
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is a single object key or array index of a path.
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parsePath parses a jq like path such as .sensor.values[0].temperature, the leading dot is optional.
+func parsePath(path string) ([]pathSegment, error) {
+	var segments []pathSegment
+	rest := strings.TrimPrefix(strings.TrimSpace(path), ".")
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated index in path: %s", path)
+			}
+			indexValue := rest[1:end]
+			if key, err := strconv.Unquote(indexValue); err == nil {
+				segments = append(segments, pathSegment{key: key})
+			} else {
+				index, err := strconv.Atoi(indexValue)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %s in path: %s", indexValue, path)
+				}
+				segments = append(segments, pathSegment{index: index, isIndex: true})
+			}
+			rest = strings.TrimPrefix(rest[end+1:], ".")
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in path: %s", path)
+			}
+			segments = append(segments, pathSegment{key: rest[:end]})
+			rest = strings.TrimPrefix(rest[end:], ".")
+		}
+	}
+	return segments, nil
+}
+
+// lookup returns the value at path within the decoded json value, negative indexes count from the end of an array.
+func lookup(value any, path []pathSegment) (any, bool) {
+	for _, segment := range path {
+		switch current := value.(type) {
+		case map[string]any:
+			if segment.isIndex {
+				return nil, false
+			}
+			var exists bool
+			if value, exists = current[segment.key]; !exists {
+				return nil, false
+			}
+		case []any:
+			if !segment.isIndex {
+				return nil, false
+			}
+			index := segment.index
+			if index < 0 {
+				index += len(current)
+			}
+			if index < 0 || index >= len(current) {
+				return nil, false
+			}
+			value = current[index]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
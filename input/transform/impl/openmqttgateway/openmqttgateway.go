@@ -0,0 +1,159 @@
+package openmqttgateway
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+const (
+	serviceDataUuidKey = "serviceDataUuid"
+	sourceKey          = "source"
+	gatewayKey         = "gateway"
+
+	sourceServiceData      = "servicedata"
+	sourceManufacturerData = "manufacturerdata"
+
+	topicPropertyKey = "inputTopic"
+	bleTopicSegment  = "BTtoMQTT"
+)
+
+// InputEventData is an advertisement published by OpenMQTTGateway or Theengs Gateway on home/<gateway>/BTtoMQTT/<MAC>.
+type InputEventData struct {
+	Id               string `json:"id"`
+	Name             string `json:"name"`
+	Rssi             *int   `json:"rssi"`
+	ServiceData      string `json:"servicedata"`
+	ServiceDataUuid  string `json:"servicedatauuid"`
+	ManufacturerData string `json:"manufacturerdata"`
+}
+
+// openMqttGateway replaces the data with the raw service data, or manufacturer data, of the advertisement.
+type openMqttGateway struct {
+	serviceDataUuid string
+	source          string
+	gateway         string
+}
+
+func (omg *openMqttGateway) Transform(_ context.Context, data *input.Data) (*input.Data, error) {
+	var inputEventData InputEventData
+	if err := json.Unmarshal(data.Data, &inputEventData); err != nil {
+		return nil, fmt.Errorf("openmqttgateway transform: failed to json decode input event data: %w", err)
+	}
+
+	logrus.
+		WithField("payload", inputEventData).
+		Debug("input transformed")
+
+	if omg.serviceDataUuid != "" && normalizeUuid(inputEventData.ServiceDataUuid) != omg.serviceDataUuid {
+		return nil, nil
+	}
+
+	payloadValue := inputEventData.ServiceData
+	if omg.source == sourceManufacturerData {
+		payloadValue = inputEventData.ManufacturerData
+	}
+	if payloadValue == "" {
+		return nil, nil
+	}
+	payload, err := hex.DecodeString(payloadValue)
+	if err != nil {
+		return nil, fmt.Errorf("openmqttgateway transform: failed to hex decode %s: %w", omg.source, err)
+	}
+
+	topic, _ := data.Properties[topicPropertyKey].(string)
+	macAddress, ok := macAddress(inputEventData.Id, topic)
+	if !ok {
+		return nil, fmt.Errorf("openmqttgateway transform: failed to resolve mac address of advertisement: %s", inputEventData.Id)
+	}
+
+	properties := make(map[string]interface{}, 8)
+	properties["omgId"] = inputEventData.Id
+	properties["omgName"] = inputEventData.Name
+	properties["omgServiceDataUuid"] = inputEventData.ServiceDataUuid
+	properties[input.MacAddressPropertyKey] = macAddress
+	if inputEventData.Rssi != nil {
+		properties["omgRssi"] = *inputEventData.Rssi
+		properties[input.RssiPropertyKey] = *inputEventData.Rssi
+	}
+	if gateway := omg.gatewayName(topic, data.Properties); gateway != "" {
+		properties[input.GatewayPropertyKey] = gateway
+	}
+	return &input.Data{
+		Data:       payload,
+		Properties: properties,
+	}, nil
+}
+
+// gatewayName returns the gateway segment of the topic, the gateway configured on the transform or the name of the input.
+func (omg *openMqttGateway) gatewayName(topic string, properties map[string]interface{}) string {
+	segments := strings.Split(topic, "/")
+	for i := 1; i < len(segments); i++ {
+		if segments[i] == bleTopicSegment {
+			return segments[i-1]
+		}
+	}
+	if omg.gateway != "" {
+		return omg.gateway
+	}
+	inputName, _ := properties[input.NamePropertyKey].(string)
+	return inputName
+}
+
+// macAddress returns the mac address of the advertisement id, or of the last topic segment which omits the colons.
+func macAddress(id, topic string) (string, bool) {
+	if parsed, err := net.ParseMAC(id); err == nil {
+		return parsed.String(), true
+	}
+
+	lastSegment := topic[strings.LastIndexByte(topic, '/')+1:]
+	raw, err := hex.DecodeString(lastSegment)
+	if err != nil || len(raw) != 6 {
+		return "", false
+	}
+	return net.HardwareAddr(raw).String(), true
+}
+
+// normalizeUuid returns the lower case 16-bit uuid of 0xfe95, fe95 and 0000fe95-0000-1000-8000-00805f9b34fb alike.
+func normalizeUuid(uuid string) string {
+	uuid = strings.ToLower(strings.TrimSpace(uuid))
+	uuid = strings.TrimPrefix(uuid, "0x")
+	if len(uuid) == 36 && strings.HasSuffix(uuid, "-0000-1000-8000-00805f9b34fb") && strings.HasPrefix(uuid, "0000") {
+		return uuid[4:8]
+	}
+	return uuid
+}
+
+func init() {
+	factory := func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		source := sourceServiceData
+		if value, exists := config.Properties[sourceKey]; exists {
+			source = strings.ToLower(value)
+		}
+		switch source {
+		case sourceServiceData, sourceManufacturerData:
+		default:
+			return nil, fmt.Errorf("openmqttgateway transform: unsupported %s: %s", sourceKey, source)
+		}
+
+		return &openMqttGateway{
+			serviceDataUuid: normalizeUuid(config.Properties[serviceDataUuidKey]),
+			source:          source,
+			gateway:         config.Properties[gatewayKey],
+		}, nil
+	}
+	for _, name := range []string{"openmqttgateway", "theengs"} {
+		if err := transform.RegisterTransform(name, factory); err != nil {
+			panic(err)
+		}
+	}
+}
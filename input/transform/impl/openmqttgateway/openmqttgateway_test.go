@@ -0,0 +1,121 @@
+package openmqttgateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+func TestOpenMqttGateway(t *testing.T) {
+	tests := []struct {
+		name               string
+		transformName      string
+		properties         map[string]string
+		topic              string
+		payload            string
+		expectedData       []byte
+		expectedProperties map[string]interface{}
+		dropped            bool
+		expectedError      string
+	}{
+		{
+			name:          "openmqttgateway service data",
+			transformName: "openmqttgateway",
+			properties:    map[string]string{"serviceDataUuid": "0000fe95-0000-1000-8000-00805f9b34fb"},
+			topic:         "home/OMG_ESP32_BLE/BTtoMQTT/A4C138ABCDEF",
+			payload:       `{"id":"A4:C1:38:AB:CD:EF","name":"LYWSD03MMC","rssi":-72,"servicedata":"30585b05","servicedatauuid":"0xfe95"}`,
+			expectedData:  []byte{0x30, 0x58, 0x5b, 0x05},
+			expectedProperties: map[string]interface{}{
+				"omgId":              "A4:C1:38:AB:CD:EF",
+				"omgName":            "LYWSD03MMC",
+				"omgServiceDataUuid": "0xfe95",
+				"omgRssi":            -72,
+				"macAddress":         "a4:c1:38:ab:cd:ef",
+				"rssi":               -72,
+				"gateway":            "OMG_ESP32_BLE",
+			},
+		},
+		{
+			name:          "theengs manufacturer data",
+			transformName: "theengs",
+			properties:    map[string]string{"source": "manufacturerdata", "gateway": "attic"},
+			topic:         "theengs/A4C138ABCDEF",
+			payload:       `{"id":"not a mac","manufacturerdata":"a90b0102"}`,
+			expectedData:  []byte{0xa9, 0x0b, 0x01, 0x02},
+			expectedProperties: map[string]interface{}{
+				"omgId":              "not a mac",
+				"omgName":            "",
+				"omgServiceDataUuid": "",
+				"macAddress":         "a4:c1:38:ab:cd:ef",
+				"gateway":            "attic",
+			},
+		},
+		{
+			name:          "other service data uuid",
+			transformName: "openmqttgateway",
+			properties:    map[string]string{"serviceDataUuid": "fcd2"},
+			topic:         "home/OMG_ESP32_BLE/BTtoMQTT/A4C138ABCDEF",
+			payload:       `{"id":"A4:C1:38:AB:CD:EF","servicedata":"30585b05","servicedatauuid":"0xfe95"}`,
+			dropped:       true,
+		},
+		{
+			name:          "no service data",
+			transformName: "openmqttgateway",
+			topic:         "home/OMG_ESP32_BLE/BTtoMQTT/A4C138ABCDEF",
+			payload:       `{"id":"A4:C1:38:AB:CD:EF","rssi":-80}`,
+			dropped:       true,
+		},
+		{
+			name:          "null payload",
+			transformName: "openmqttgateway",
+			topic:         "home/OMG_ESP32_BLE/BTtoMQTT/A4C138ABCDEF",
+			payload:       `null`,
+			dropped:       true,
+		},
+		{
+			name:          "invalid payload",
+			transformName: "openmqttgateway",
+			topic:         "home/OMG_ESP32_BLE/BTtoMQTT/A4C138ABCDEF",
+			payload:       `[]`,
+			expectedError: "openmqttgateway transform: failed to json decode input event data: json: cannot unmarshal array into Go value of type openmqttgateway.InputEventData",
+		},
+		{
+			name:          "unknown mac address",
+			transformName: "openmqttgateway",
+			topic:         "home/OMG_ESP32_BLE/BTtoMQTT",
+			payload:       `{"id":"unknown","servicedata":"00"}`,
+			expectedError: "openmqttgateway transform: failed to resolve mac address of advertisement: unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := transform.NewTransform(context.Background(), &config.Transform{
+				Name:       tt.transformName,
+				Properties: tt.properties,
+			})
+			require.NoError(t, err)
+
+			data, err := tr.Transform(context.Background(), &input.Data{
+				Data:       []byte(tt.payload),
+				Properties: map[string]interface{}{"inputTopic": tt.topic},
+			})
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			if tt.dropped {
+				assert.Nil(t, data)
+				return
+			}
+			assert.Equal(t, tt.expectedData, data.Data)
+			assert.Equal(t, tt.expectedProperties, data.Properties)
+		})
+	}
+}
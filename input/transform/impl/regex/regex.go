@@ -0,0 +1,87 @@
+package regex
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+const (
+	patternKey = "pattern"
+	sourceKey  = "source"
+	dataKey    = "data"
+)
+
+// regex matches the pattern against the data, or the source property, and sets a property for every named capture
+// group, the group named by the data property replaces the data, events which do not match are dropped.
+type regex struct {
+	pattern   *regexp.Regexp
+	source    string
+	dataGroup int
+}
+
+func (r *regex) Transform(_ context.Context, data *input.Data) (*input.Data, error) {
+	value := data.Data
+	if r.source != "" {
+		switch sourceValue := data.Properties[r.source].(type) {
+		case string:
+			value = []byte(sourceValue)
+		case []byte:
+			value = sourceValue
+		default:
+			return nil, fmt.Errorf("regex transform: missing source property: %s", r.source)
+		}
+	}
+
+	match := r.pattern.FindSubmatch(value)
+	if match == nil {
+		return nil, nil
+	}
+
+	transformData := &input.Data{
+		Data:       data.Data,
+		Properties: make(map[string]interface{}, len(match)),
+	}
+	for i, name := range r.pattern.SubexpNames() {
+		if name == "" || match[i] == nil {
+			continue
+		}
+		transformData.Properties[name] = string(match[i])
+	}
+	if r.dataGroup > 0 {
+		transformData.Data = match[r.dataGroup]
+	}
+	return transformData, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("regex", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		patternValue, exists := config.Properties[patternKey]
+		if !exists {
+			return nil, fmt.Errorf("regex transform: missing config property: %s", patternKey)
+		}
+		pattern, err := regexp.Compile(patternValue)
+		if err != nil {
+			return nil, fmt.Errorf("regex transform: invalid pattern: %w", err)
+		}
+
+		r := &regex{
+			pattern: pattern,
+			source:  config.Properties[sourceKey],
+		}
+		if dataGroup, exists := config.Properties[dataKey]; exists {
+			r.dataGroup = pattern.SubexpIndex(dataGroup)
+			if r.dataGroup == -1 {
+				return nil, fmt.Errorf("regex transform: pattern has no capture group named: %s", dataGroup)
+			}
+		}
+		return r, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,70 @@
+package regex
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+func TestRegex(t *testing.T) {
+	tests := []struct {
+		name               string
+		properties         map[string]string
+		data               input.Data
+		expectedData       string
+		expectedProperties map[string]interface{}
+		dropped            bool
+	}{
+		{
+			name:               "named groups",
+			properties:         map[string]string{"pattern": `^(?P<unit>\w+)=(?P<value>[\d.]+)$`},
+			data:               input.Data{Data: []byte("temperature=21.5")},
+			expectedData:       "temperature=21.5",
+			expectedProperties: map[string]interface{}{"unit": "temperature", "value": "21.5"},
+		},
+		{
+			name:               "group as data",
+			properties:         map[string]string{"pattern": `^(?P<unit>\w+)=(?P<value>[\d.]+)$`, "data": "value"},
+			data:               input.Data{Data: []byte("humidity=40")},
+			expectedData:       "40",
+			expectedProperties: map[string]interface{}{"unit": "humidity", "value": "40"},
+		},
+		{
+			name:               "source property",
+			properties:         map[string]string{"pattern": `^zigbee2mqtt/(?P<deviceName>[^/]+)$`, "source": "inputTopic"},
+			data:               input.Data{Data: []byte("{}"), Properties: map[string]interface{}{"inputTopic": "zigbee2mqtt/kitchen"}},
+			expectedData:       "{}",
+			expectedProperties: map[string]interface{}{"deviceName": "kitchen"},
+		},
+		{
+			name:       "no match",
+			properties: map[string]string{"pattern": `^\d+$`},
+			data:       input.Data{Data: []byte("text")},
+			dropped:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := transform.NewTransform(context.Background(), &config.Transform{
+				Name:       "regex",
+				Properties: tt.properties,
+			})
+			require.NoError(t, err)
+
+			data, err := tr.Transform(context.Background(), &tt.data)
+			require.NoError(t, err)
+			if tt.dropped {
+				assert.Nil(t, data)
+				return
+			}
+			assert.Equal(t, tt.expectedData, string(data.Data))
+			assert.Equal(t, tt.expectedProperties, data.Properties)
+		})
+	}
+}
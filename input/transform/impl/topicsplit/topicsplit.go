@@ -0,0 +1,87 @@
+package topicsplit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+const (
+	segmentsKey  = "segments"
+	sourceKey    = "source"
+	separatorKey = "separator"
+
+	defaultSource    = "inputTopic"
+	defaultSeparator = "/"
+	skipSegment      = "_"
+)
+
+// topicSplit splits the topic of the message into its segments and sets the property named by the comma separated
+// segments for each of them, segments named _ or left empty are skipped, a trailing segment named with a * suffix
+// receives the rest of the topic.
+type topicSplit struct {
+	segments  []string
+	source    string
+	separator string
+}
+
+func (ts *topicSplit) Transform(_ context.Context, data *input.Data) (*input.Data, error) {
+	topic, ok := data.Properties[ts.source].(string)
+	if !ok {
+		return nil, fmt.Errorf("topic-split transform: missing source property: %s", ts.source)
+	}
+
+	topicSegments := strings.Split(topic, ts.separator)
+	transformData := &input.Data{
+		Data:       data.Data,
+		Properties: make(map[string]interface{}, len(ts.segments)),
+	}
+	for i, name := range ts.segments {
+		if i >= len(topicSegments) {
+			break
+		}
+		if name == "" || name == skipSegment {
+			continue
+		}
+		if rest, ok := strings.CutSuffix(name, "*"); ok {
+			transformData.Properties[rest] = strings.Join(topicSegments[i:], ts.separator)
+			break
+		}
+		transformData.Properties[name] = topicSegments[i]
+	}
+	return transformData, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("topic-split", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		segmentsValue, exists := config.Properties[segmentsKey]
+		if !exists {
+			return nil, fmt.Errorf("topic-split transform: missing config property: %s", segmentsKey)
+		}
+
+		segments := strings.Split(segmentsValue, ",")
+		for i := range segments {
+			segments[i] = strings.TrimSpace(segments[i])
+		}
+
+		ts := &topicSplit{
+			segments:  segments,
+			source:    defaultSource,
+			separator: defaultSeparator,
+		}
+		if source := config.Properties[sourceKey]; source != "" {
+			ts.source = source
+		}
+		if separator := config.Properties[separatorKey]; separator != "" {
+			ts.separator = separator
+		}
+		return ts, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
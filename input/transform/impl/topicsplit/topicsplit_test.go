@@ -0,0 +1,62 @@
+package topicsplit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/input/transform"
+)
+
+func TestTopicSplit(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		topic      string
+		expected   map[string]interface{}
+	}{
+		{
+			name:       "named segments",
+			properties: map[string]string{"segments": "_,deviceName,unit"},
+			topic:      "tele/kitchen/SENSOR",
+			expected:   map[string]interface{}{"deviceName": "kitchen", "unit": "SENSOR"},
+		},
+		{
+			name:       "rest of topic",
+			properties: map[string]string{"segments": "gateway,,path*"},
+			topic:      "home/OMG_ESP32_BLE/BTtoMQTT/A4C138ABCDEF",
+			expected:   map[string]interface{}{"gateway": "home", "path": "BTtoMQTT/A4C138ABCDEF"},
+		},
+		{
+			name:       "short topic",
+			properties: map[string]string{"segments": "a,b,c"},
+			topic:      "x",
+			expected:   map[string]interface{}{"a": "x"},
+		},
+		{
+			name:       "custom separator",
+			properties: map[string]string{"segments": "site,room", "separator": "."},
+			topic:      "office.lobby",
+			expected:   map[string]interface{}{"site": "office", "room": "lobby"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := transform.NewTransform(context.Background(), &config.Transform{
+				Name:       "topic-split",
+				Properties: tt.properties,
+			})
+			require.NoError(t, err)
+
+			data, err := tr.Transform(context.Background(), &input.Data{
+				Properties: map[string]interface{}{"inputTopic": tt.topic},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, data.Properties)
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package spool
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	segmentFileExtension = ".seg"
+	recordHeaderLength   = 12 // unix nano timestamp + payload length
+	defaultSegmentSize   = 4 << 20
+)
+
+var (
+	openSpools     = make(map[string]*Spool)
+	openSpoolsLock sync.Mutex
+)
+
+type Options struct {
+	// MaxSize is the maximum size in bytes of all segments, the oldest segments are dropped once exceeded.
+	MaxSize int64
+	// MaxAge is the maximum age of a record, older records are dropped instead of replayed.
+	MaxAge time.Duration
+	// SegmentSize is the size in bytes after which a new segment file is started.
+	SegmentSize int64
+}
+
+type segment struct {
+	id   uint64
+	size int64
+}
+
+// Spool is an append-only log of records stored in segment files within a directory, records are replayed oldest first
+// and removed once delivered, so every record is delivered at least once.
+type Spool struct {
+	directory  string
+	options    Options
+	references int
+	segments   []*segment
+	writer     *os.File
+	reader     *os.File
+	readerId   uint64
+	headOffset int64
+	lock       sync.Mutex
+	replayLock sync.Mutex
+}
+
+// Open opens the spool stored in directory, opening the same directory again returns the already open spool
+// with the new options applied, it stays open until every Open is paired with a Close.
+func Open(directory string, options Options) (*Spool, error) {
+	absDirectory, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, fmt.Errorf("spool: failed to resolve absolute path of %s: %w", directory, err)
+	}
+
+	openSpoolsLock.Lock()
+	defer openSpoolsLock.Unlock()
+
+	if s, exists := openSpools[absDirectory]; exists {
+		s.lock.Lock()
+		s.options = options
+		s.references++
+		s.lock.Unlock()
+		return s, nil
+	}
+
+	if err := os.MkdirAll(absDirectory, 0o755); err != nil {
+		return nil, fmt.Errorf("spool: failed to create directory %s: %w", absDirectory, err)
+	}
+
+	entries, err := os.ReadDir(absDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("spool: failed to read directory %s: %w", absDirectory, err)
+	}
+
+	s := &Spool{
+		directory:  absDirectory,
+		options:    options,
+		references: 1,
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentFileExtension) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(name, segmentFileExtension), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("spool: failed to stat segment %s: %w", name, err)
+		}
+		s.segments = append(s.segments, &segment{
+			id:   id,
+			size: info.Size(),
+		})
+	}
+	slices.SortFunc(s.segments, func(a, b *segment) int {
+		switch {
+		case a.id < b.id:
+			return -1
+		case a.id > b.id:
+			return 1
+		}
+		return 0
+	})
+
+	openSpools[absDirectory] = s
+	return s, nil
+}
+
+// Append writes record to the newest segment, it returns the amount of records dropped to stay within the maximum size.
+func (s *Spool) Append(record []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	buf := make([]byte, recordHeaderLength+len(record))
+	binary.LittleEndian.PutUint64(buf, uint64(time.Now().UnixNano()))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(len(record)))
+	copy(buf[recordHeaderLength:], record)
+
+	segmentSize := s.options.SegmentSize
+	if segmentSize <= 0 {
+		segmentSize = defaultSegmentSize
+	}
+	if s.writer == nil || s.segments[len(s.segments)-1].size+int64(len(buf)) > segmentSize {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.writer.Write(buf)
+	s.segments[len(s.segments)-1].size += int64(n)
+	if err != nil {
+		return 0, fmt.Errorf("spool: failed to write record: %w", err)
+	}
+
+	var dropped int
+	for s.options.MaxSize > 0 && s.size() > s.options.MaxSize && len(s.segments) > 1 {
+		records, err := s.removeHead()
+		dropped += records
+		if err != nil {
+			return dropped, err
+		}
+	}
+	return dropped, nil
+}
+
+// Replay calls fn with the spooled records oldest first until fn fails or the spool is drained, records older than
+// the maximum age are dropped without calling fn. It returns the amount of dropped records.
+func (s *Spool) Replay(fn func(record []byte) error) (int, error) {
+	s.replayLock.Lock()
+	defer s.replayLock.Unlock()
+
+	var expired int
+	for {
+		s.lock.Lock()
+		record, timestamp, position, ok, err := s.peek()
+		maxAge := s.options.MaxAge
+		s.lock.Unlock()
+		if err != nil || !ok {
+			return expired, err
+		}
+
+		if maxAge > 0 && time.Since(timestamp) > maxAge {
+			expired++
+		} else if err := fn(record); err != nil {
+			return expired, err
+		}
+
+		s.lock.Lock()
+		s.advance(position)
+		s.lock.Unlock()
+	}
+}
+
+// Pending reports whether the spool holds records which were not replayed yet.
+func (s *Spool) Pending() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.size()-s.headOffset > 0
+}
+
+// Size returns the size in bytes of all segments.
+func (s *Spool) Size() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.size()
+}
+
+func (s *Spool) Close() error {
+	openSpoolsLock.Lock()
+	defer openSpoolsLock.Unlock()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.references--
+	if s.references > 0 {
+		return nil
+	}
+	delete(openSpools, s.directory)
+
+	var errs []error
+	if s.writer != nil {
+		errs = append(errs, s.writer.Close())
+		s.writer = nil
+	}
+	if s.reader != nil {
+		errs = append(errs, s.reader.Close())
+		s.reader = nil
+	}
+	return errors.Join(errs...)
+}
+
+type recordPosition struct {
+	segmentId uint64
+	offset    int64
+	next      int64
+}
+
+// peek reads the oldest record which was not replayed yet, segments which were fully replayed are removed.
+func (s *Spool) peek() ([]byte, time.Time, recordPosition, bool, error) {
+	for len(s.segments) != 0 {
+		head := s.segments[0]
+		isWriting := s.writer != nil && len(s.segments) == 1
+		if s.headOffset >= head.size {
+			if isWriting {
+				break
+			}
+			if _, err := s.removeHead(); err != nil {
+				return nil, time.Time{}, recordPosition{}, false, err
+			}
+			continue
+		}
+
+		if s.reader == nil || s.readerId != head.id {
+			if s.reader != nil {
+				_ = s.reader.Close()
+			}
+			reader, err := os.Open(s.segmentPath(head.id))
+			if err != nil {
+				return nil, time.Time{}, recordPosition{}, false, fmt.Errorf("spool: failed to open segment: %w", err)
+			}
+			s.reader = reader
+			s.readerId = head.id
+		}
+
+		record, timestamp, err := readRecord(s.reader, s.headOffset)
+		if err != nil {
+			if isWriting || !errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, time.Time{}, recordPosition{}, false, err
+			}
+			// a truncated record is left behind when the previous process stopped while writing, skip the segment rest.
+			s.headOffset = head.size
+			continue
+		}
+
+		return record, timestamp, recordPosition{
+			segmentId: head.id,
+			offset:    s.headOffset,
+			next:      s.headOffset + recordHeaderLength + int64(len(record)),
+		}, true, nil
+	}
+	return nil, time.Time{}, recordPosition{}, false, nil
+}
+
+// advance marks the record at position as replayed unless its segment was dropped in the meantime.
+func (s *Spool) advance(position recordPosition) {
+	if len(s.segments) == 0 || s.segments[0].id != position.segmentId || s.headOffset != position.offset {
+		return
+	}
+	s.headOffset = position.next
+}
+
+func (s *Spool) rotate() error {
+	if s.writer != nil {
+		if err := s.writer.Close(); err != nil {
+			return fmt.Errorf("spool: failed to close segment: %w", err)
+		}
+		s.writer = nil
+	}
+
+	id := uint64(1)
+	if len(s.segments) != 0 {
+		id = s.segments[len(s.segments)-1].id + 1
+	}
+
+	writer, err := os.OpenFile(s.segmentPath(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("spool: failed to create segment: %w", err)
+	}
+	s.writer = writer
+	s.segments = append(s.segments, &segment{
+		id: id,
+	})
+	return nil
+}
+
+// removeHead deletes the oldest segment, returning the amount of records in it which were not replayed.
+func (s *Spool) removeHead() (int, error) {
+	head := s.segments[0]
+	if s.writer != nil && len(s.segments) == 1 {
+		if err := s.writer.Close(); err != nil {
+			return 0, fmt.Errorf("spool: failed to close segment: %w", err)
+		}
+		s.writer = nil
+	}
+	if s.reader != nil && s.readerId == head.id {
+		_ = s.reader.Close()
+		s.reader = nil
+	}
+
+	records := countRecords(s.segmentPath(head.id), s.headOffset)
+	s.segments = s.segments[1:]
+	s.headOffset = 0
+	if err := os.Remove(s.segmentPath(head.id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return records, fmt.Errorf("spool: failed to remove segment: %w", err)
+	}
+	return records, nil
+}
+
+func (s *Spool) size() int64 {
+	var size int64
+	for _, seg := range s.segments {
+		size += seg.size
+	}
+	return size
+}
+
+func (s *Spool) segmentPath(id uint64) string {
+	return filepath.Join(s.directory, fmt.Sprintf("%020d%s", id, segmentFileExtension))
+}
+
+func readRecord(r io.ReaderAt, offset int64) ([]byte, time.Time, error) {
+	header := make([]byte, recordHeaderLength)
+	if _, err := r.ReadAt(header, offset); err != nil {
+		return nil, time.Time{}, readError(err)
+	}
+	timestamp := time.Unix(0, int64(binary.LittleEndian.Uint64(header)))
+	record := make([]byte, binary.LittleEndian.Uint32(header[8:]))
+	if _, err := r.ReadAt(record, offset+recordHeaderLength); err != nil {
+		return nil, time.Time{}, readError(err)
+	}
+	return record, timestamp, nil
+}
+
+func readError(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("spool: failed to read record: %w", err)
+}
+
+func countRecords(path string, offset int64) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	var records int
+	header := make([]byte, recordHeaderLength)
+	for {
+		if _, err := file.ReadAt(header, offset); err != nil {
+			return records
+		}
+		records++
+		offset += recordHeaderLength + int64(binary.LittleEndian.Uint32(header[8:]))
+	}
+}
@@ -0,0 +1,91 @@
+package spool
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func collect(t *testing.T, s *Spool, failAfter int) []string {
+	var records []string
+	_, err := s.Replay(func(record []byte) error {
+		if failAfter >= 0 && len(records) == failAfter {
+			return errors.New("output unavailable")
+		}
+		records = append(records, string(record))
+		return nil
+	})
+	if failAfter < 0 {
+		require.NoError(t, err)
+	}
+	return records
+}
+
+func TestSpoolReplay(t *testing.T) {
+	directory := t.TempDir()
+	s, err := Open(directory, Options{SegmentSize: 32})
+	require.NoError(t, err)
+
+	for _, record := range []string{"one", "two", "three", "four"} {
+		_, err := s.Append([]byte(record))
+		require.NoError(t, err)
+	}
+	assert.True(t, s.Pending())
+
+	assert.Equal(t, []string{"one", "two"}, collect(t, s, 2))
+	assert.Equal(t, []string{"three", "four"}, collect(t, s, -1))
+	assert.False(t, s.Pending())
+	require.NoError(t, s.Close())
+}
+
+func TestSpoolReopen(t *testing.T) {
+	directory := t.TempDir()
+	s, err := Open(directory, Options{})
+	require.NoError(t, err)
+	_, err = s.Append([]byte("one"))
+	require.NoError(t, err)
+	require.NoError(t, s.Close())
+
+	s, err = Open(directory, Options{})
+	require.NoError(t, err)
+	_, err = s.Append([]byte("two"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, collect(t, s, -1))
+	require.NoError(t, s.Close())
+}
+
+func TestSpoolMaxSize(t *testing.T) {
+	s, err := Open(t.TempDir(), Options{MaxSize: 40, SegmentSize: 16})
+	require.NoError(t, err)
+
+	var dropped int
+	for _, record := range []string{"one", "two", "three", "four"} {
+		recordsDropped, err := s.Append([]byte(record))
+		require.NoError(t, err)
+		dropped += recordsDropped
+	}
+	assert.Equal(t, 2, dropped)
+	assert.LessOrEqual(t, s.Size(), int64(40))
+	assert.Equal(t, []string{"three", "four"}, collect(t, s, -1))
+	require.NoError(t, s.Close())
+}
+
+func TestSpoolMaxAge(t *testing.T) {
+	s, err := Open(t.TempDir(), Options{MaxAge: time.Millisecond})
+	require.NoError(t, err)
+
+	_, err = s.Append([]byte("one"))
+	require.NoError(t, err)
+	time.Sleep(5 * time.Millisecond)
+
+	expired, err := s.Replay(func(record []byte) error {
+		t.Fatalf("unexpected record: %s", record)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	require.NoError(t, s.Close())
+}
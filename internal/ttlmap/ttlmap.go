@@ -0,0 +1,56 @@
+package ttlmap
+
+import "time"
+
+type entry[V any] struct {
+	value    V
+	storedAt time.Time
+}
+
+// Map holds a value per key with the time it was stored at. The keys which were not stored within the ttl are pruned
+// once the map grows beyond its size, so the keys of devices that went away are not kept forever. It is not safe for
+// concurrent use.
+type Map[K comparable, V any] struct {
+	ttl     time.Duration
+	size    int
+	pruneAt int
+	entries map[K]entry[V]
+}
+
+func New[K comparable, V any](ttl time.Duration, size int) *Map[K, V] {
+	return &Map[K, V]{
+		ttl:     ttl,
+		size:    size,
+		pruneAt: size,
+		entries: make(map[K]entry[V]),
+	}
+}
+
+// Load returns the value of key and the time it was stored at.
+func (m *Map[K, V]) Load(key K) (V, time.Time, bool) {
+	e, ok := m.entries[key]
+	return e.value, e.storedAt, ok
+}
+
+// Store stores value for key at now and prunes the expired keys once the map holds more keys than its size.
+func (m *Map[K, V]) Store(key K, value V, now time.Time) {
+	m.entries[key] = entry[V]{
+		value:    value,
+		storedAt: now,
+	}
+	if len(m.entries) <= m.pruneAt {
+		return
+	}
+
+	for k, e := range m.entries {
+		if now.Sub(e.storedAt) >= m.ttl {
+			delete(m.entries, k)
+		}
+	}
+	// the keys still in use are not scanned again until the map doubled
+	m.pruneAt = max(m.size, 2*len(m.entries))
+}
+
+func (m *Map[K, V]) Len() int {
+	return len(m.entries)
+}
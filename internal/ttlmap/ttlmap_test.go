@@ -0,0 +1,54 @@
+package ttlmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMap(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := New[string, int](time.Minute, 2)
+
+	m.Store("kitchen", 1, now)
+	m.Store("garage", 2, now.Add(30*time.Second))
+	value, storedAt, ok := m.Load("kitchen")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, now, storedAt)
+
+	// expired keys are kept until the map grows beyond its size
+	m.Store("kitchen", 3, now.Add(time.Minute))
+	assert.Equal(t, 2, m.Len())
+
+	m.Store("attic", 4, now.Add(90*time.Second))
+	assert.Equal(t, 2, m.Len())
+	_, _, ok = m.Load("garage")
+	assert.False(t, ok)
+	value, _, ok = m.Load("kitchen")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+}
+
+func TestMapKeysInUse(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := New[int, int](time.Minute, 2)
+	for i := range 10 {
+		m.Store(i, i, now)
+	}
+	assert.Equal(t, 10, m.Len())
+
+	// the keys in use are only scanned again once the map doubled since the last prune
+	now = now.Add(time.Minute)
+	for i := 10; i < 14; i++ {
+		m.Store(i, i, now)
+	}
+	assert.Equal(t, 14, m.Len())
+	for i := 14; i < 20; i++ {
+		m.Store(i, i, now)
+	}
+	assert.Equal(t, 10, m.Len())
+	_, _, ok := m.Load(0)
+	assert.False(t, ok)
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -33,14 +35,24 @@ var (
 )
 
 var (
-	configPath string
-	verbose    bool
+	configPath     string
+	verbose        bool
+	watchInterval  time.Duration
+	validateConfig bool
 )
 
 func main() {
+	args := os.Args[1:]
+	if len(args) != 0 && args[0] == "validate" {
+		validateConfig = true
+		args = args[1:]
+	}
+
 	flag.StringVar(&configPath, "config", "./config.yaml", "Path to the config.yaml")
 	flag.BoolVar(&verbose, "verbose", false, "Enables debug logging")
-	flag.Parse()
+	flag.DurationVar(&watchInterval, "watchInterval", 5*time.Second, "Interval for polling the config for changes, 0 disables watching (SIGHUP still reloads)")
+	flag.BoolVar(&validateConfig, "validate", validateConfig, "Validates the config, reports every problem found and exits, also available as the validate command")
+	_ = flag.CommandLine.Parse(args)
 
 	logrus.SetFormatter(&logrus.JSONFormatter{
 		FieldMap: logrus.FieldMap{
@@ -54,6 +66,10 @@ func main() {
 		logrus.SetLevel(logrus.DebugLevel)
 	}
 
+	if validateConfig {
+		os.Exit(validate())
+	}
+
 	logrus.
 		WithField("version", version).
 		WithField("commit", commit).
@@ -96,7 +112,25 @@ func main() {
 	shutdownChan := make(chan os.Signal, 1)
 	signal.Notify(shutdownChan, syscall.SIGTERM, syscall.SIGINT)
 
-	<-shutdownChan
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+
+	watchCtx, watchCtxCancel := context.WithCancel(context.Background())
+	defer watchCtxCancel()
+	configChanges := config.Watch(watchCtx, configPath, watchInterval)
+
+	for running := true; running; {
+		select {
+		case <-shutdownChan:
+			running = false
+		case <-reloadChan:
+			logrus.Info("received SIGHUP, reloading configuration...")
+			reload(metricsService, processService)
+		case <-configChanges:
+			logrus.Info("configuration changed, reloading...")
+			reload(metricsService, processService)
+		}
+	}
 	logrus.Info("Shutting down...")
 
 	logrus.Info("Shutting down process service...")
@@ -109,3 +143,47 @@ func main() {
 		logrus.WithError(err).Error("failed to shutdown metrics service")
 	}
 }
+
+func reload(metricsService metrics.Service, processService process.Service) {
+	conf, err := config.Load(configPath)
+	if err != nil {
+		logrus.
+			WithError(err).
+			Error("failed to reload config, keeping the current configuration")
+		return
+	}
+
+	metricsService.Reload(conf.Metrics)
+	if err := processService.Reload(conf.Sensors); err != nil {
+		logrus.
+			WithError(err).
+			Error("failed to reload some sensors")
+		return
+	}
+	logrus.Info("configuration reloaded")
+}
+
+// validate loads the config strictly and reports every problem found, it returns the process exit code.
+func validate() int {
+	conf, err := config.LoadStrict(configPath)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "failed to load config: %s\n", err)
+		return 1
+	}
+
+	err = errors.Join(
+		conf.Validate(),
+		process.Validate(context.Background(), &conf.Metrics, conf.Sensors),
+	)
+	if err != nil {
+		problems := strings.Split(err.Error(), "\n")
+		_, _ = fmt.Fprintf(os.Stderr, "config %s has %d problem(s):\n", configPath, len(problems))
+		for _, problem := range problems {
+			_, _ = fmt.Fprintf(os.Stderr, "  - %s\n", problem)
+		}
+		return 1
+	}
+
+	_, _ = fmt.Fprintf(os.Stdout, "config %s is valid\n", configPath)
+	return 0
+}
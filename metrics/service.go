@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -23,6 +26,7 @@ var (
 
 type Service interface {
 	Observe(key Key, value float64, labels prometheus.Labels) error
+	Reload(config config.Metrics)
 	ListenAndServe() error
 	Shutdown(ctx context.Context) error
 }
@@ -31,9 +35,10 @@ type service struct {
 	tlsCertificateFile    string
 	tlsPrivateKey         string
 	endpoint              string
-	stalenessInterval     time.Duration
+	listenAddr            string
 	listening             atomic.Bool
 	server                *http.Server
+	stalenessInterval     time.Duration
 	metricsMapping        []*config.MetricsMapping
 	collectorByMetric     map[string]*collector
 	collectorByMetricLock sync.Mutex
@@ -51,6 +56,7 @@ func NewService(config config.Metrics) Service {
 		tlsCertificateFile: config.TLS.CertificateFile,
 		tlsPrivateKey:      config.TLS.PrivateKeyFile,
 		endpoint:           config.Endpoint,
+		listenAddr:         config.ListenAddr,
 		stalenessInterval:  config.StalenessInterval,
 		server:             server,
 		metricsMapping:     config.MetricsMapping,
@@ -64,6 +70,21 @@ func (s *service) Observe(key Key, value float64, labels prometheus.Labels) erro
 		WithField("value", value).
 		Debug("observing metric value changes")
 
+	c, err := s.lookupCollector(key, labels)
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		return nil
+	}
+
+	return c.Observe(value, labels)
+}
+
+func (s *service) lookupCollector(key Key, labels prometheus.Labels) (*collector, error) {
+	s.collectorByMetricLock.Lock()
+	defer s.collectorByMetricLock.Unlock()
+
 	var mapping *config.MetricsMapping
 	for _, m := range s.metricsMapping {
 		if strings.EqualFold(m.Name, key.Name) &&
@@ -75,19 +96,63 @@ func (s *service) Observe(key Key, value float64, labels prometheus.Labels) erro
 	}
 	if mapping == nil {
 		logrus.WithField("name", key).Warn("metrics mapping not found")
-		return nil
+		return nil, nil
 	}
 
 	if err := validateLabels(key, mapping, labels); err != nil {
-		return fmt.Errorf("metrics: failed to validate labels: %w", err)
+		return nil, fmt.Errorf("metrics: failed to validate labels: %w", err)
 	}
 
 	c, err := s.computeCollector(mapping)
 	if err != nil {
-		return fmt.Errorf("metrics: failed to get collector: %w", err)
+		return nil, fmt.Errorf("metrics: failed to get collector: %w", err)
 	}
+	return c, nil
+}
 
-	return c.Observe(value, labels)
+// Reload replaces the metrics mappings, closing only the collectors whose mapping was removed or changed
+// so unchanged series survive a configuration reload.
+func (s *service) Reload(config config.Metrics) {
+	if config.ListenAddr != s.listenAddr || config.Endpoint != s.endpoint || config.TLS.CertificateFile != s.tlsCertificateFile || config.TLS.PrivateKeyFile != s.tlsPrivateKey {
+		logrus.Warn("metrics: listener configuration changes require a restart to take effect")
+	}
+
+	s.collectorByMetricLock.Lock()
+	defer s.collectorByMetricLock.Unlock()
+
+	stalenessChanged := config.StalenessInterval != s.stalenessInterval
+	for name, c := range s.collectorByMetric {
+		var keep bool
+		for _, mapping := range config.MetricsMapping {
+			if !stalenessChanged && reflect.DeepEqual(mapping, c.metricsMapping) {
+				keep = true
+				break
+			}
+		}
+		if keep {
+			continue
+		}
+
+		for _, mapping := range config.MetricsMapping {
+			if mapping.Name == name && !sameDimensions(mapping, c.metricsMapping) {
+				logrus.WithField("name", name).Warn("metrics: description and label changes require a restart to take effect")
+			}
+		}
+		logrus.WithField("name", name).Info("metrics: closing collector of changed metrics mapping")
+		c.Close()
+		delete(s.collectorByMetric, name)
+	}
+
+	s.stalenessInterval = config.StalenessInterval
+	s.metricsMapping = config.MetricsMapping
+}
+
+// sameDimensions reports whether the mappings describe the same help and labels, which prometheus requires to stay
+// consistent for the lifetime of the process.
+func sameDimensions(a, b *config.MetricsMapping) bool {
+	return a.Description == b.Description &&
+		slices.Equal(a.Labels, b.Labels) &&
+		maps.Equal(a.ConstLabels, b.ConstLabels)
 }
 
 func (s *service) ListenAndServe() error {
@@ -118,9 +183,6 @@ func (s *service) Shutdown(ctx context.Context) error {
 }
 
 func (s *service) computeCollector(mapping *config.MetricsMapping) (*collector, error) {
-	s.collectorByMetricLock.Lock()
-	defer s.collectorByMetricLock.Unlock()
-
 	c, exists := s.collectorByMetric[mapping.Name]
 	if !exists {
 		var err error
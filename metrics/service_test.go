@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+)
+
+func mapping(name, metricType string) *config.MetricsMapping {
+	return &config.MetricsMapping{
+		Name:        name,
+		Description: "Reload test metric",
+		Namespace:   "reload_test",
+		Labels:      []string{"device"},
+		Type:        metricType,
+	}
+}
+
+func TestServiceReload(t *testing.T) {
+	s := NewService(config.Metrics{
+		Endpoint: "/metrics",
+		MetricsMapping: []*config.MetricsMapping{
+			mapping("unchanged", "gauge"),
+			mapping("changed", "gauge"),
+			mapping("removed", "gauge"),
+		},
+	}).(*service)
+
+	labels := prometheus.Labels{"device": "kitchen"}
+	for _, name := range []string{"unchanged", "changed", "removed"} {
+		require.NoError(t, s.Observe(Key{Name: name, Namespace: "reload_test"}, 1, labels))
+	}
+	unchanged := s.collectorByMetric["unchanged"]
+	changed := s.collectorByMetric["changed"]
+	require.NotNil(t, unchanged)
+	require.NotNil(t, changed)
+
+	s.Reload(config.Metrics{
+		Endpoint: "/metrics",
+		MetricsMapping: []*config.MetricsMapping{
+			mapping("unchanged", "gauge"),
+			mapping("changed", "counter"),
+		},
+	})
+
+	assert.Same(t, unchanged, s.collectorByMetric["unchanged"])
+	assert.NotContains(t, s.collectorByMetric, "changed")
+	assert.NotContains(t, s.collectorByMetric, "removed")
+	assert.Error(t, changed.closeCtx.Err())
+
+	// the closed collector is unregistered, so the changed mapping registers a new one
+	require.NoError(t, s.Observe(Key{Name: "changed", Namespace: "reload_test"}, 2, labels))
+	assert.NotSame(t, changed, s.collectorByMetric["changed"])
+	assert.Equal(t, "counter", s.collectorByMetric["changed"].metricsMapping.Type)
+
+	// observing a removed mapping is ignored
+	require.NoError(t, s.Observe(Key{Name: "removed", Namespace: "reload_test"}, 1, labels))
+	assert.NotContains(t, s.collectorByMetric, "removed")
+
+	// a staleness interval change recreates every collector
+	s.Reload(config.Metrics{
+		Endpoint: "/metrics",
+		MetricsMapping: []*config.MetricsMapping{
+			mapping("unchanged", "gauge"),
+			mapping("changed", "counter"),
+		},
+		StalenessInterval: 1,
+	})
+	assert.Empty(t, s.collectorByMetric)
+}
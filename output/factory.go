@@ -7,4 +7,8 @@ import (
 	"github.com/nikiforov-soft/yasp/metrics"
 )
 
-type Factory func(ctx context.Context, config *config.Output, metricsService metrics.Service) (Output, error)
+// Factory creates an output from its options, which the factory decodes into its own config.
+type Factory func(ctx context.Context, options config.Options, metricsService metrics.Service) (Output, error)
+
+// Validator reports the problems of the options of an output without creating it.
+type Validator func(options config.Options, metricsConfig *config.Metrics) error
@@ -0,0 +1,279 @@
+package homeassistant
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/device/vendors/bthome"
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+// entityClass describes a sensor entity, or a binary sensor entity when the on and off payloads are set.
+type entityClass struct {
+	deviceClass string
+	unit        string
+	stateClass  string
+	payloadOn   string
+	payloadOff  string
+}
+
+func binaryClass(deviceClass, payloadOn, payloadOff string) entityClass {
+	return entityClass{deviceClass: deviceClass, payloadOn: payloadOn, payloadOff: payloadOff}
+}
+
+// component returns the home assistant integration of the entity.
+func (ec entityClass) component() string {
+	if ec.payloadOn != "" {
+		return "binary_sensor"
+	}
+	return "sensor"
+}
+
+type deviceInfo struct {
+	key          string
+	name         string
+	model        string
+	manufacturer string
+	macAddress   string
+}
+
+type entity struct {
+	key   string
+	name  string
+	value string
+	class entityClass
+}
+
+// measurementClasses maps the measurement types reported by the devices to home assistant sensor classes, the devices
+// report the binary measurements as 1 and 0
+// https://www.home-assistant.io/integrations/sensor/#device-class
+// https://www.home-assistant.io/integrations/binary_sensor/#device-class
+var measurementClasses = map[string]entityClass{
+	"Temperature":   {deviceClass: "temperature", unit: "°C", stateClass: "measurement"},
+	"DewPoint":      {deviceClass: "temperature", unit: "°C", stateClass: "measurement"},
+	"Humidity":      {deviceClass: "humidity", unit: "%", stateClass: "measurement"},
+	"Battery":       {deviceClass: "battery", unit: "%", stateClass: "measurement"},
+	"Pressure":      {deviceClass: "atmospheric_pressure", unit: "hPa", stateClass: "measurement"},
+	"Illuminance":   {deviceClass: "illuminance", unit: "lx", stateClass: "measurement"},
+	"Moisture":      {deviceClass: "moisture", unit: "%", stateClass: "measurement"},
+	"CO2":           {deviceClass: "carbon_dioxide", unit: "ppm", stateClass: "measurement"},
+	"TVOC":          {deviceClass: "volatile_organic_compounds", unit: "µg/m³", stateClass: "measurement"},
+	"PM2.5":         {deviceClass: "pm25", unit: "µg/m³", stateClass: "measurement"},
+	"PM10":          {deviceClass: "pm10", unit: "µg/m³", stateClass: "measurement"},
+	"Voltage":       {deviceClass: "voltage", unit: "V", stateClass: "measurement"},
+	"Current":       {deviceClass: "current", unit: "A", stateClass: "measurement"},
+	"Power":         {deviceClass: "power", unit: "W", stateClass: "measurement"},
+	"Energy":        {deviceClass: "energy", unit: "kWh", stateClass: "total_increasing"},
+	"Gas":           {deviceClass: "gas", unit: "m³", stateClass: "total_increasing"},
+	"Water":         {deviceClass: "water", unit: "L", stateClass: "total_increasing"},
+	"Volume":        {deviceClass: "volume", stateClass: "measurement"},
+	"VolumeStorage": {deviceClass: "volume_storage", unit: "L", stateClass: "measurement"},
+	"Distance":      {deviceClass: "distance", stateClass: "measurement"},
+	"Speed":         {deviceClass: "speed", unit: "m/s", stateClass: "measurement"},
+	"Duration":      {deviceClass: "duration", unit: "s", stateClass: "measurement"},
+	"Mass":          {deviceClass: "weight", stateClass: "measurement"},
+	"Precipitation": {deviceClass: "precipitation", unit: "mm", stateClass: "measurement"},
+	"Conductivity":  {deviceClass: "conductivity", unit: "µS/cm", stateClass: "measurement"},
+	"Count":         {stateClass: "measurement"},
+	"Rotation":      {unit: "°", stateClass: "measurement"},
+	"Motion":        binaryClass("motion", "1", "0"),
+	"Door":          binaryClass("door", "1", "0"),
+	"Window":        binaryClass("window", "1", "0"),
+	"WaterLeak":     binaryClass("moisture", "1", "0"),
+	"Occupancy":     binaryClass("occupancy", "1", "0"),
+	"Smoke":         binaryClass("smoke", "1", "0"),
+	// zigbee2mqtt reports 1 while the contact is closed, home assistant door sensors are on while open
+	"Contact": binaryClass("door", "0", "1"),
+}
+
+var manufacturers = map[string]string{
+	"LYWSD03MMC":  "Xiaomi",
+	"bthome":      "BTHome",
+	"mccgq02hl":   "Xiaomi",
+	"mi-flora":    "Xiaomi",
+	"mibeacon":    "Xiaomi",
+	"p1p2":        "Hitachi",
+	"p1p2-daikin": "Daikin",
+	"zigbee2mqtt": "Zigbee2MQTT",
+}
+
+// entities derives the home assistant device and its entities from the properties of a decoded device event.
+func entities(data *output.Data) (*deviceInfo, []entity, error) {
+	if propertyString(data.Properties, "type") == "p1p2" {
+		return p1p2Entities(data)
+	}
+
+	deviceName := propertyString(data.Properties, "deviceName")
+	deviceType := propertyString(data.Properties, "deviceType")
+	if deviceName == "" {
+		return nil, nil, fmt.Errorf("homeassistant output: deviceName property is required")
+	}
+
+	device := &deviceInfo{
+		key:          objectId(deviceName),
+		name:         deviceName,
+		model:        deviceType,
+		manufacturer: manufacturers[deviceType],
+		macAddress:   propertyString(data.Properties, "deviceMacAddress"),
+	}
+	if device.macAddress != "" {
+		device.key = objectId(device.macAddress)
+	}
+
+	if measurements, ok := data.Properties["measurements"].([]bthome.Measurement); ok {
+		if device.manufacturer == "" {
+			device.manufacturer = "Shelly"
+		}
+		result := make([]entity, 0, len(measurements))
+		for _, measurement := range measurements {
+			result = append(result, measurementEntity(measurement.Type, measurement.Unit, measurement.Index, measurement.FormattedValue()))
+		}
+		return device, result, nil
+	}
+
+	unit := propertyString(data.Properties, "unit")
+	value := propertyString(data.Properties, "value")
+	if unit == "" || value == "" {
+		return nil, nil, fmt.Errorf("homeassistant output: unit and value properties are required: device name: %s", deviceName)
+	}
+	index, _ := strconv.Atoi(propertyString(data.Properties, "measurementIndex"))
+	return device, []entity{
+		measurementEntity(unit, propertyString(data.Properties, "unitOfMeasurement"), index, value),
+	}, nil
+}
+
+func measurementEntity(measurementType, unit string, index int, value string) entity {
+	class := measurementClasses[measurementType]
+	if unit != "" {
+		class.unit = unit
+	}
+
+	e := entity{
+		key:   objectId(measurementType),
+		name:  measurementType,
+		value: value,
+		class: class,
+	}
+	if index > 0 {
+		e.key = fmt.Sprintf("%s_%d", e.key, index+1)
+		e.name = fmt.Sprintf("%s %d", e.name, index+1)
+	}
+	return e
+}
+
+func p1p2Entities(data *output.Data) (*deviceInfo, []entity, error) {
+	bridge := propertyString(data.Properties, "bridge")
+	if bridge == "" {
+		return nil, nil, fmt.Errorf("homeassistant output: bridge property is required for p1p2")
+	}
+
+	device := &deviceInfo{
+		key:          objectId("p1p2_" + bridge),
+		name:         "P1P2 " + bridge,
+		model:        "p1p2",
+		manufacturer: manufacturers["p1p2"],
+	}
+	if propertyString(data.Properties, "vendor") == "daikin" {
+		device.manufacturer = manufacturers["p1p2-daikin"]
+		return device, propertyEntities(data, daikinEntityProperties), nil
+	}
+	// multiple indoor units can share a bridge, each of them is exposed as a separate device
+	if unitAddress := propertyString(data.Properties, "unitAddress"); unitAddress != "" {
+		device.key = objectId("p1p2_" + bridge + "_" + unitAddress)
+		device.name = "P1P2 " + bridge + " unit " + unitAddress
+	}
+	return device, propertyEntities(data, hitachiEntityProperties), nil
+}
+
+// hitachiEntityProperties are the hitachi message values exposed as entities, the measured values are only sent by the units.
+var hitachiEntityProperties = []entityProperty{
+	{property: "temperature", name: "Temperature", class: measurementClasses["Temperature"]},
+	{property: "mode", name: "Mode"},
+	{property: "fanSpeed", key: "fan_speed", name: "Fan speed"},
+	{property: "status", name: "Running", class: binaryClass("running", "true", "false")},
+	{property: "errorCode", key: "error_code", name: "Error code"},
+	{property: "louverPosition", name: "Louver position"},
+	{property: "swing", name: "Swing"},
+	{property: "roomTemperature", name: "Room temperature", class: measurementClasses["Temperature"]},
+	{property: "outdoorTemperature", name: "Outdoor temperature", class: measurementClasses["Temperature"]},
+	{property: "filterSign", name: "Filter sign", class: binaryClass("problem", "true", "false")},
+	{property: "defrost", name: "Defrost", class: binaryClass("", "true", "false")},
+}
+
+// daikinEntityProperties are the daikin packet values exposed as entities, a packet carries a subset of them.
+var daikinEntityProperties = []entityProperty{
+	{property: "operatingMode", name: "Operating mode"},
+	{property: "heatingPower", name: "Heating"},
+	{property: "dhwPower", name: "DHW"},
+	{property: "dhwActive", name: "DHW active"},
+	{property: "quietMode", name: "Quiet mode"},
+	{property: "compressorRunning", name: "Compressor"},
+	{property: "circulationPumpRunning", name: "Circulation pump"},
+	{property: "targetRoomTemperature", name: "Target room temperature", class: measurementClasses["Temperature"]},
+	{property: "targetDhwTemperature", name: "Target DHW temperature", class: measurementClasses["Temperature"]},
+	{property: "targetLeavingWaterHeatingTemperature", name: "Target leaving water heating temperature", class: measurementClasses["Temperature"]},
+	{property: "targetLeavingWaterCoolingTemperature", name: "Target leaving water cooling temperature", class: measurementClasses["Temperature"]},
+	{property: "roomTemperature", name: "Room temperature", class: measurementClasses["Temperature"]},
+	{property: "outsideTemperature", name: "Outside temperature", class: measurementClasses["Temperature"]},
+	{property: "leavingWaterTemperature", name: "Leaving water temperature", class: measurementClasses["Temperature"]},
+	{property: "returnWaterTemperature", name: "Return water temperature", class: measurementClasses["Temperature"]},
+	{property: "dhwTemperature", name: "DHW temperature", class: measurementClasses["Temperature"]},
+	{property: "refrigerantTemperature", name: "Refrigerant temperature", class: measurementClasses["Temperature"]},
+	{property: "flow", name: "Flow", class: entityClass{deviceClass: "volume_flow_rate", unit: "L/min", stateClass: "measurement"}},
+}
+
+// entityProperty maps a device event property to an entity, the key defaults to the object id of the property.
+type entityProperty struct {
+	property string
+	key      string
+	name     string
+	class    entityClass
+}
+
+// propertyEntities returns an entity per property present in the event.
+func propertyEntities(data *output.Data, properties []entityProperty) []entity {
+	var result []entity
+	for _, property := range properties {
+		value := propertyString(data.Properties, property.property)
+		if value == "" {
+			continue
+		}
+		key := property.key
+		if key == "" {
+			key = objectId(property.property)
+		}
+		result = append(result, entity{
+			key:   key,
+			name:  property.name,
+			value: value,
+			class: property.class,
+		})
+	}
+	return result
+}
+
+func propertyString(properties map[string]interface{}, key string) string {
+	value, exists := properties[key]
+	if !exists || value == nil {
+		return ""
+	}
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
+
+// objectId turns value into a home assistant object id, which may only contain lowercase letters, digits and underscores.
+func objectId(value string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(value) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune('_')
+		}
+	}
+	return sb.String()
+}
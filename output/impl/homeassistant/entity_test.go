@@ -0,0 +1,232 @@
+package homeassistant
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device/vendors/bthome"
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+// discovered is the discovery topic and config of an entity along with its state.
+type discovered struct {
+	topic  string
+	config discoveryConfig
+	state  string
+}
+
+func TestEntities(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]interface{}
+		expected   []discovered
+	}{
+		{
+			name: "bthome event",
+			properties: map[string]interface{}{
+				"deviceName":       "Door Sensor",
+				"deviceType":       "bthome",
+				"deviceMacAddress": "38:39:8f:00:00:01",
+				"measurements": []bthome.Measurement{
+					{Type: "Battery", Unit: "%", Value: 98},
+					{Type: "Window", Value: 1},
+					{Type: "Temperature", Unit: "°C", Index: 1, Value: 21.5},
+				},
+			},
+			expected: []discovered{
+				{
+					topic: "homeassistant/sensor/yasp_38_39_8f_00_00_01_battery/config",
+					config: discoveryConfig{
+						Name:              "Battery",
+						UniqueId:          "yasp_38_39_8f_00_00_01_battery",
+						StateTopic:        "yasp/38_39_8f_00_00_01/battery/state",
+						AvailabilityTopic: "yasp/38_39_8f_00_00_01/availability",
+						DeviceClass:       "battery",
+						UnitOfMeasurement: "%",
+						StateClass:        "measurement",
+					},
+					state: "98",
+				},
+				{
+					topic: "homeassistant/binary_sensor/yasp_38_39_8f_00_00_01_window/config",
+					config: discoveryConfig{
+						Name:              "Window",
+						UniqueId:          "yasp_38_39_8f_00_00_01_window",
+						StateTopic:        "yasp/38_39_8f_00_00_01/window/state",
+						AvailabilityTopic: "yasp/38_39_8f_00_00_01/availability",
+						DeviceClass:       "window",
+						PayloadOn:         "1",
+						PayloadOff:        "0",
+					},
+					state: "1",
+				},
+				{
+					topic: "homeassistant/sensor/yasp_38_39_8f_00_00_01_temperature_2/config",
+					config: discoveryConfig{
+						Name:              "Temperature 2",
+						UniqueId:          "yasp_38_39_8f_00_00_01_temperature_2",
+						StateTopic:        "yasp/38_39_8f_00_00_01/temperature_2/state",
+						AvailabilityTopic: "yasp/38_39_8f_00_00_01/availability",
+						DeviceClass:       "temperature",
+						UnitOfMeasurement: "°C",
+						StateClass:        "measurement",
+					},
+					state: "21.5",
+				},
+			},
+		},
+		{
+			name: "per measurement event",
+			properties: map[string]interface{}{
+				"deviceName": "Hallway Motion",
+				"deviceType": "zigbee2mqtt",
+				"unit":       "Occupancy",
+				"value":      "0",
+			},
+			expected: []discovered{
+				{
+					topic: "homeassistant/binary_sensor/yasp_hallway_motion_occupancy/config",
+					config: discoveryConfig{
+						Name:              "Occupancy",
+						UniqueId:          "yasp_hallway_motion_occupancy",
+						StateTopic:        "yasp/hallway_motion/occupancy/state",
+						AvailabilityTopic: "yasp/hallway_motion/availability",
+						DeviceClass:       "occupancy",
+						PayloadOn:         "1",
+						PayloadOff:        "0",
+					},
+					state: "0",
+				},
+			},
+		},
+		{
+			name: "per measurement contact event",
+			properties: map[string]interface{}{
+				"deviceName": "Front Door",
+				"deviceType": "zigbee2mqtt",
+				"unit":       "Contact",
+				"value":      "1",
+			},
+			expected: []discovered{
+				{
+					topic: "homeassistant/binary_sensor/yasp_front_door_contact/config",
+					config: discoveryConfig{
+						Name:              "Contact",
+						UniqueId:          "yasp_front_door_contact",
+						StateTopic:        "yasp/front_door/contact/state",
+						AvailabilityTopic: "yasp/front_door/availability",
+						DeviceClass:       "door",
+						PayloadOn:         "0",
+						PayloadOff:        "1",
+					},
+					state: "1",
+				},
+			},
+		},
+		{
+			name: "p1p2 event",
+			properties: map[string]interface{}{
+				"type":        "p1p2",
+				"bridge":      "P1P2MQTT",
+				"unitAddress": "2",
+				"temperature": "24",
+				"fanSpeed":    "Auto",
+				"status":      "true",
+				"defrost":     "false",
+			},
+			expected: []discovered{
+				{
+					topic: "homeassistant/sensor/yasp_p1p2_p1p2mqtt_2_temperature/config",
+					config: discoveryConfig{
+						Name:              "Temperature",
+						UniqueId:          "yasp_p1p2_p1p2mqtt_2_temperature",
+						StateTopic:        "yasp/p1p2_p1p2mqtt_2/temperature/state",
+						AvailabilityTopic: "yasp/p1p2_p1p2mqtt_2/availability",
+						DeviceClass:       "temperature",
+						UnitOfMeasurement: "°C",
+						StateClass:        "measurement",
+					},
+					state: "24",
+				},
+				{
+					topic: "homeassistant/sensor/yasp_p1p2_p1p2mqtt_2_fan_speed/config",
+					config: discoveryConfig{
+						Name:              "Fan speed",
+						UniqueId:          "yasp_p1p2_p1p2mqtt_2_fan_speed",
+						StateTopic:        "yasp/p1p2_p1p2mqtt_2/fan_speed/state",
+						AvailabilityTopic: "yasp/p1p2_p1p2mqtt_2/availability",
+					},
+					state: "Auto",
+				},
+				{
+					topic: "homeassistant/binary_sensor/yasp_p1p2_p1p2mqtt_2_status/config",
+					config: discoveryConfig{
+						Name:              "Running",
+						UniqueId:          "yasp_p1p2_p1p2mqtt_2_status",
+						StateTopic:        "yasp/p1p2_p1p2mqtt_2/status/state",
+						AvailabilityTopic: "yasp/p1p2_p1p2mqtt_2/availability",
+						DeviceClass:       "running",
+						PayloadOn:         "true",
+						PayloadOff:        "false",
+					},
+					state: "true",
+				},
+				{
+					topic: "homeassistant/binary_sensor/yasp_p1p2_p1p2mqtt_2_defrost/config",
+					config: discoveryConfig{
+						Name:              "Defrost",
+						UniqueId:          "yasp_p1p2_p1p2mqtt_2_defrost",
+						StateTopic:        "yasp/p1p2_p1p2mqtt_2/defrost/state",
+						AvailabilityTopic: "yasp/p1p2_p1p2mqtt_2/availability",
+						PayloadOn:         "true",
+						PayloadOff:        "false",
+					},
+					state: "false",
+				},
+			},
+		},
+	}
+	ho := &homeAssistantOutput{config: &config.HomeAssistant{}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			device, deviceEntities, err := entities(&output.Data{Properties: tt.properties})
+			require.NoError(t, err)
+
+			var actual []discovered
+			for _, e := range deviceEntities {
+				topic, discovery := ho.discovery(device, e)
+				assert.Equal(t, []string{"yasp_" + device.key}, discovery.Device.Identifiers)
+				discovery.Device = discoveryDevice{}
+				actual = append(actual, discovered{topic: topic, config: discovery, state: e.value})
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestP1P2UnitDevices(t *testing.T) {
+	ho := &homeAssistantOutput{config: &config.HomeAssistant{}}
+	for _, unitAddress := range []string{"1", "2"} {
+		device, deviceEntities, err := entities(&output.Data{
+			Properties: map[string]interface{}{
+				"type":        "p1p2",
+				"bridge":      "P1P2MQTT",
+				"unitAddress": unitAddress,
+				"temperature": "24",
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, deviceEntities, 1)
+
+		_, discovery := ho.discovery(device, deviceEntities[0])
+		assert.Equal(t, discoveryDevice{
+			Identifiers:  []string{"yasp_p1p2_p1p2mqtt_" + unitAddress},
+			Name:         "P1P2 P1P2MQTT unit " + unitAddress,
+			Model:        "p1p2",
+			Manufacturer: "Hitachi",
+		}, discovery.Device)
+	}
+}
@@ -0,0 +1,360 @@
+package homeassistant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/metrics"
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+const (
+	availabilityOnline  = "online"
+	availabilityOffline = "offline"
+)
+
+var (
+	discoveryPublishedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "output_discovery_published",
+		Help:      "The amount of discovery configs homeassistant output published.",
+		Namespace: "yasp",
+		Subsystem: "homeassistant",
+	}, []string{"device"})
+	statesPublishedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "output_states_published",
+		Help:      "The amount of states homeassistant output published.",
+		Namespace: "yasp",
+		Subsystem: "homeassistant",
+	}, []string{"device"})
+)
+
+type discoveryDevice struct {
+	Identifiers  []string   `json:"identifiers"`
+	Connections  [][]string `json:"connections,omitempty"`
+	Name         string     `json:"name"`
+	Model        string     `json:"model,omitempty"`
+	Manufacturer string     `json:"manufacturer,omitempty"`
+}
+
+// discoveryConfig - https://www.home-assistant.io/integrations/sensor.mqtt/
+// https://www.home-assistant.io/integrations/binary_sensor.mqtt/
+type discoveryConfig struct {
+	Name              string          `json:"name"`
+	UniqueId          string          `json:"unique_id"`
+	StateTopic        string          `json:"state_topic"`
+	AvailabilityTopic string          `json:"availability_topic"`
+	DeviceClass       string          `json:"device_class,omitempty"`
+	UnitOfMeasurement string          `json:"unit_of_measurement,omitempty"`
+	StateClass        string          `json:"state_class,omitempty"`
+	PayloadOn         string          `json:"payload_on,omitempty"`
+	PayloadOff        string          `json:"payload_off,omitempty"`
+	Device            discoveryDevice `json:"device"`
+}
+
+type deviceState struct {
+	lastSeen time.Time
+	online   bool
+}
+
+type homeAssistantOutput struct {
+	config            *config.HomeAssistant
+	connectionManager *autopaho.ConnectionManager
+	announced         map[string]bool
+	devices           map[string]*deviceState
+	lock              sync.Mutex
+	cancelFunc        context.CancelFunc
+	done              chan struct{}
+}
+
+func newHomeAssistantOutput(ctx context.Context, config *config.HomeAssistant) (output.Output, error) {
+	keepAlive := config.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = 5
+	}
+
+	ho := &homeAssistantOutput{
+		config:    config,
+		announced: make(map[string]bool),
+		devices:   make(map[string]*deviceState),
+		done:      make(chan struct{}),
+	}
+
+	statusTopic := config.GetDiscoveryPrefix() + "/status"
+	clientConfig := autopaho.ClientConfig{
+		BrokerUrls:       config.GetBrokerUrls(),
+		KeepAlive:        keepAlive,
+		ReconnectBackoff: autopaho.DefaultExponentialBackoff(),
+		OnConnectionUp: func(cm *autopaho.ConnectionManager, _ *paho.Connack) {
+			logrus.Info("homeassistant output: connected to server")
+			if _, err := cm.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: []paho.SubscribeOptions{{Topic: statusTopic, QoS: config.QoS}},
+			}); err != nil {
+				logrus.WithError(err).Error("homeassistant output: failed to subscribe to status topic")
+			}
+		},
+		OnConnectError: func(err error) { logrus.WithError(err).Error("homeassistant output: failed to connect to server") },
+		ClientConfig: paho.ClientConfig{
+			ClientID:      config.ClientId,
+			Router:        paho.NewStandardRouterWithDefault(ho.statusHandler),
+			OnClientError: func(err error) { logrus.WithError(err).Error("homeassistant output: client error") },
+			OnServerDisconnect: func(d *paho.Disconnect) {
+				if d.Properties != nil {
+					logrus.WithField("reason", d.Properties.ReasonString).Error("homeassistant output: server requested disconnect")
+				} else {
+					logrus.WithField("reasonCode", d.ReasonCode).Error("homeassistant output: server requested disconnect")
+				}
+			},
+		},
+	}
+
+	if len(config.Username) != 0 && len(config.Password) != 0 {
+		clientConfig.ConnectUsername = config.Username
+		clientConfig.ConnectPassword = []byte(config.Password)
+	}
+
+	connectionManager, err := autopaho.NewConnection(ctx, clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("homeassistant output: failed to initialize connection manager: %w", err)
+	}
+	ho.connectionManager = connectionManager
+
+	availabilityCtx, cancelFunc := context.WithCancel(ctx)
+	ho.cancelFunc = cancelFunc
+	go ho.watchAvailability(availabilityCtx)
+
+	return ho, nil
+}
+
+func (ho *homeAssistantOutput) Publish(ctx context.Context, data *output.Data) error {
+	device, deviceEntities, err := entities(data)
+	if err != nil {
+		return err
+	}
+
+	if err := ho.connectionManager.AwaitConnection(ctx); err != nil {
+		return fmt.Errorf("homeassistant output: failed to await connection: %w", err)
+	}
+
+	ho.lock.Lock()
+	state, exists := ho.devices[device.key]
+	if !exists {
+		state = &deviceState{}
+		ho.devices[device.key] = state
+	}
+	state.lastSeen = time.Now()
+	wasOnline := state.online
+	state.online = true
+	ho.lock.Unlock()
+
+	for _, e := range deviceEntities {
+		if e.value == "" {
+			continue
+		}
+		if err := ho.announce(ctx, device, e); err != nil {
+			return err
+		}
+	}
+
+	if !wasOnline {
+		if err := ho.publish(ctx, ho.availabilityTopic(device.key), availabilityOnline, true); err != nil {
+			ho.lock.Lock()
+			state.online = false
+			ho.lock.Unlock()
+			return err
+		}
+	}
+
+	for _, e := range deviceEntities {
+		if e.value == "" {
+			continue
+		}
+		if err := ho.publish(ctx, ho.stateTopic(device.key, e.key), e.value, ho.config.Retain); err != nil {
+			return err
+		}
+		statesPublishedCounter.WithLabelValues(device.name).Inc()
+	}
+	return nil
+}
+
+// announce publishes the retained discovery config of the entity unless it was already published.
+func (ho *homeAssistantOutput) announce(ctx context.Context, device *deviceInfo, e entity) error {
+	topic, discovery := ho.discovery(device, e)
+
+	ho.lock.Lock()
+	announced := ho.announced[discovery.UniqueId]
+	ho.lock.Unlock()
+	if announced {
+		return nil
+	}
+
+	payload, err := json.Marshal(discovery)
+	if err != nil {
+		return fmt.Errorf("homeassistant output: failed to marshal discovery config: %w", err)
+	}
+
+	if err := ho.publish(ctx, topic, string(payload), true); err != nil {
+		return err
+	}
+	discoveryPublishedCounter.WithLabelValues(device.name).Inc()
+
+	ho.lock.Lock()
+	ho.announced[discovery.UniqueId] = true
+	ho.lock.Unlock()
+	return nil
+}
+
+// discovery returns the discovery topic and config of the entity.
+func (ho *homeAssistantOutput) discovery(device *deviceInfo, e entity) (string, discoveryConfig) {
+	uniqueId := fmt.Sprintf("yasp_%s_%s", device.key, e.key)
+	discoveryDevice := discoveryDevice{
+		Identifiers:  []string{"yasp_" + device.key},
+		Name:         device.name,
+		Model:        device.model,
+		Manufacturer: device.manufacturer,
+	}
+	if device.macAddress != "" {
+		discoveryDevice.Connections = [][]string{{"mac", device.macAddress}}
+	}
+
+	topic := fmt.Sprintf("%s/%s/%s/config", ho.config.GetDiscoveryPrefix(), e.class.component(), uniqueId)
+	return topic, discoveryConfig{
+		Name:              e.name,
+		UniqueId:          uniqueId,
+		StateTopic:        ho.stateTopic(device.key, e.key),
+		AvailabilityTopic: ho.availabilityTopic(device.key),
+		DeviceClass:       e.class.deviceClass,
+		UnitOfMeasurement: e.class.unit,
+		StateClass:        e.class.stateClass,
+		PayloadOn:         e.class.payloadOn,
+		PayloadOff:        e.class.payloadOff,
+		Device:            discoveryDevice,
+	}
+}
+
+func (ho *homeAssistantOutput) publish(ctx context.Context, topic, payload string, retain bool) error {
+	logrus.WithField("topic", topic).WithField("payload", payload).Debug("output published")
+	_, err := ho.connectionManager.Publish(ctx, &paho.Publish{
+		QoS:     ho.config.QoS,
+		Retain:  retain,
+		Topic:   topic,
+		Payload: []byte(payload),
+	})
+	if err != nil {
+		return fmt.Errorf("homeassistant output: failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// statusHandler re-announces every entity once home assistant comes back online, as it may have lost the discovery configs.
+func (ho *homeAssistantOutput) statusHandler(publish *paho.Publish) {
+	if string(publish.Payload) != availabilityOnline {
+		return
+	}
+
+	logrus.Info("homeassistant output: home assistant is online, re-announcing entities")
+	ho.lock.Lock()
+	defer ho.lock.Unlock()
+	clear(ho.announced)
+	for _, state := range ho.devices {
+		state.online = false
+	}
+}
+
+// watchAvailability marks the devices which were not seen within the availability timeout as offline.
+func (ho *homeAssistantOutput) watchAvailability(ctx context.Context) {
+	defer close(ho.done)
+
+	timeout := ho.config.GetAvailabilityTimeout()
+	ticker := time.NewTicker(max(timeout/10, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var offline []string
+		ho.lock.Lock()
+		for key, state := range ho.devices {
+			if state.online && time.Since(state.lastSeen) > timeout {
+				state.online = false
+				offline = append(offline, key)
+			}
+		}
+		ho.lock.Unlock()
+
+		for _, key := range offline {
+			if err := ho.publish(ctx, ho.availabilityTopic(key), availabilityOffline, true); err != nil {
+				logrus.WithError(err).WithField("device", key).Error("homeassistant output: failed to publish availability")
+			}
+		}
+	}
+}
+
+func (ho *homeAssistantOutput) stateTopic(deviceKey, entityKey string) string {
+	return fmt.Sprintf("%s/%s/%s/state", ho.config.GetStateTopicPrefix(), deviceKey, entityKey)
+}
+
+func (ho *homeAssistantOutput) availabilityTopic(deviceKey string) string {
+	return fmt.Sprintf("%s/%s/availability", ho.config.GetStateTopicPrefix(), deviceKey)
+}
+
+// Close marks every online device as offline before disconnecting.
+func (ho *homeAssistantOutput) Close(ctx context.Context) error {
+	ho.cancelFunc()
+	<-ho.done
+
+	publishCtx, cancelFunc := context.WithTimeout(ctx, 5*time.Second)
+	defer cancelFunc()
+
+	var errs []error
+	ho.lock.Lock()
+	for key, state := range ho.devices {
+		if !state.online {
+			continue
+		}
+		state.online = false
+		errs = append(errs, ho.publish(publishCtx, ho.availabilityTopic(key), availabilityOffline, true))
+	}
+	ho.lock.Unlock()
+
+	errs = append(errs, ho.connectionManager.Disconnect(ctx))
+	return errors.Join(errs...)
+}
+
+func init() {
+	err := output.RegisterOutput("homeassistant", func(ctx context.Context, options config.Options, metricsService metrics.Service) (output.Output, error) {
+		var homeAssistantConfig config.HomeAssistant
+		if err := options.Decode(&homeAssistantConfig); err != nil {
+			return nil, fmt.Errorf("homeassistant output: failed to decode options: %w", err)
+		}
+		return newHomeAssistantOutput(ctx, &homeAssistantConfig)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	err = output.RegisterOutputValidator("homeassistant", func(options config.Options, _ *config.Metrics) error {
+		var homeAssistantConfig config.HomeAssistant
+		if err := options.DecodeStrict(&homeAssistantConfig); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+		return homeAssistantConfig.Validate()
+	})
+	if err != nil {
+		panic(err)
+	}
+}
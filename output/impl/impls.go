@@ -1,6 +1,7 @@
 package impl
 
 import (
+	_ "github.com/nikiforov-soft/yasp/output/impl/homeassistant"
 	_ "github.com/nikiforov-soft/yasp/output/impl/influxdb2"
 	_ "github.com/nikiforov-soft/yasp/output/impl/mqtt"
 	_ "github.com/nikiforov-soft/yasp/output/impl/prometheus"
@@ -140,8 +140,23 @@ func (i *influxdb) Close(_ context.Context) error {
 }
 
 func init() {
-	err := output.RegisterOutput("influxdb2", func(ctx context.Context, config *config.Output, metricsService metrics.Service) (output.Output, error) {
-		return newInfluxDb2(ctx, config.InfluxDb2)
+	err := output.RegisterOutput("influxdb2", func(ctx context.Context, options config.Options, metricsService metrics.Service) (output.Output, error) {
+		var influxDb2Config config.InfluxDb2
+		if err := options.Decode(&influxDb2Config); err != nil {
+			return nil, fmt.Errorf("influxdb2 output: failed to decode options: %w", err)
+		}
+		return newInfluxDb2(ctx, &influxDb2Config)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	err = output.RegisterOutputValidator("influxdb2", func(options config.Options, _ *config.Metrics) error {
+		var influxDb2Config config.InfluxDb2
+		if err := options.DecodeStrict(&influxDb2Config); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+		return influxDb2Config.Validate()
 	})
 	if err != nil {
 		panic(err)
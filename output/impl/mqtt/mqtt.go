@@ -103,8 +103,23 @@ func (mo *mqttOutput) Close(ctx context.Context) error {
 }
 
 func init() {
-	err := output.RegisterOutput("mqtt", func(ctx context.Context, config *config.Output, metricsService metrics.Service) (output.Output, error) {
-		return NewMqttOutput(ctx, config.Mqtt)
+	err := output.RegisterOutput("mqtt", func(ctx context.Context, options config.Options, metricsService metrics.Service) (output.Output, error) {
+		var mqttConfig config.MqttOutput
+		if err := options.Decode(&mqttConfig); err != nil {
+			return nil, fmt.Errorf("mqtt output: failed to decode options: %w", err)
+		}
+		return NewMqttOutput(ctx, &mqttConfig)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	err = output.RegisterOutputValidator("mqtt", func(options config.Options, _ *config.Metrics) error {
+		var mqttConfig config.MqttOutput
+		if err := options.DecodeStrict(&mqttConfig); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+		return mqttConfig.Validate()
 	})
 	if err != nil {
 		panic(err)
@@ -108,8 +108,27 @@ func (p *promeheus) Close(_ context.Context) error {
 }
 
 func init() {
-	err := output.RegisterOutput("prometheus", func(ctx context.Context, config *config.Output, metricsService metrics.Service) (output.Output, error) {
-		return newPrometheus(ctx, config.Prometheus, metricsService)
+	err := output.RegisterOutput("prometheus", func(ctx context.Context, options config.Options, metricsService metrics.Service) (output.Output, error) {
+		var prometheusConfig config.Prometheus
+		if err := options.Decode(&prometheusConfig); err != nil {
+			return nil, fmt.Errorf("prometheus: failed to decode options: %w", err)
+		}
+		return newPrometheus(ctx, &prometheusConfig, metricsService)
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	err = output.RegisterOutputValidator("prometheus", func(options config.Options, metricsConfig *config.Metrics) error {
+		var prometheusConfig config.Prometheus
+		if err := options.DecodeStrict(&prometheusConfig); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+		var metricsMapping []*config.MetricsMapping
+		if metricsConfig != nil {
+			metricsMapping = metricsConfig.MetricsMapping
+		}
+		return prometheusConfig.Validate(metricsMapping)
 	})
 	if err != nil {
 		panic(err)
@@ -15,10 +15,11 @@ var ErrOutputNotFound = errors.New("output not found")
 
 var (
 	outputs     = make(map[string]Factory)
+	validators  = make(map[string]Validator)
 	outputsLock sync.RWMutex
 )
 
-func NewOutput(ctx context.Context, outputName string, config *config.Output, metricsService metrics.Service) (Output, error) {
+func NewOutput(ctx context.Context, outputName string, options config.Options, metricsService metrics.Service) (Output, error) {
 	outputsLock.RLock()
 	defer outputsLock.RUnlock()
 
@@ -26,7 +27,15 @@ func NewOutput(ctx context.Context, outputName string, config *config.Output, me
 	if outputFactory == nil {
 		return nil, fmt.Errorf("%w: %s", ErrOutputNotFound, outputName)
 	}
-	return outputFactory(ctx, config, metricsService)
+	return outputFactory(ctx, options, metricsService)
+}
+
+func HasOutput(outputName string) bool {
+	outputsLock.RLock()
+	defer outputsLock.RUnlock()
+
+	_, exists := outputs[strings.ToLower(outputName)]
+	return exists
 }
 
 func RegisterOutput(outputName string, outputFactory Factory) error {
@@ -40,3 +49,32 @@ func RegisterOutput(outputName string, outputFactory Factory) error {
 	outputs[key] = outputFactory
 	return nil
 }
+
+// ValidateOutput reports the problems of the options of the output without creating it, the options of an output without a
+// registered validator are not checked.
+func ValidateOutput(outputName string, options config.Options, metricsConfig *config.Metrics) error {
+	outputsLock.RLock()
+	defer outputsLock.RUnlock()
+
+	key := strings.ToLower(outputName)
+	if _, exists := outputs[key]; !exists {
+		return fmt.Errorf("%w: %s", ErrOutputNotFound, outputName)
+	}
+	validator := validators[key]
+	if validator == nil {
+		return nil
+	}
+	return validator(options, metricsConfig)
+}
+
+func RegisterOutputValidator(outputName string, validator Validator) error {
+	outputsLock.Lock()
+	defer outputsLock.Unlock()
+
+	key := strings.ToLower(outputName)
+	if _, exists := validators[key]; exists {
+		return fmt.Errorf("output validator name: %s already exists", outputName)
+	}
+	validators[key] = validator
+	return nil
+}
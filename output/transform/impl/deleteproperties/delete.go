@@ -0,0 +1,49 @@
+package deleteproperties
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+	"github.com/nikiforov-soft/yasp/output/transform"
+)
+
+const (
+	propertiesKey = "properties"
+)
+
+// deleteProperties removes the comma separated properties from the event.
+type deleteProperties struct {
+	properties []string
+}
+
+func (d *deleteProperties) Transform(_ context.Context, data *output.Data) (*output.Data, error) {
+	for _, property := range d.properties {
+		delete(data.Properties, property)
+	}
+	return data, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("delete", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		propertiesValue, exists := config.Properties[propertiesKey]
+		if !exists {
+			return nil, fmt.Errorf("delete transform: missing config property: %s", propertiesKey)
+		}
+
+		var properties []string
+		for _, property := range strings.Split(propertiesValue, ",") {
+			if property = strings.TrimSpace(property); property != "" {
+				properties = append(properties, property)
+			}
+		}
+		return &deleteProperties{
+			properties: properties,
+		}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,52 @@
+package deleteproperties
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+	"github.com/nikiforov-soft/yasp/output/transform"
+)
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties string
+		expected   map[string]interface{}
+	}{
+		{
+			name:       "removes the properties",
+			properties: "deviceName, unit",
+			expected:   map[string]interface{}{"value": 21.5},
+		},
+		{
+			name:       "ignores missing properties",
+			properties: "battery,,value ",
+			expected:   map[string]interface{}{"deviceName": "Kitchen", "unit": "Temperature"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := transform.NewTransform(context.Background(), &config.Transform{
+				Name:       "delete",
+				Properties: map[string]string{"properties": tt.properties},
+			})
+			require.NoError(t, err)
+
+			data := &output.Data{
+				Properties: map[string]interface{}{
+					"deviceName": "Kitchen",
+					"unit":       "Temperature",
+					"value":      21.5,
+				},
+			}
+			transformed, err := d.Transform(context.Background(), data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, transformed.Properties)
+		})
+	}
+}
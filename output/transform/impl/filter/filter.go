@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+	"github.com/nikiforov-soft/yasp/output/transform"
+	"github.com/nikiforov-soft/yasp/template"
+)
+
+const (
+	conditionKey = "condition"
+)
+
+// filter drops every event for which the condition template does not render true.
+type filter struct {
+	condition string
+}
+
+func (f *filter) Transform(_ context.Context, data *output.Data) (*output.Data, error) {
+	conditionBytes, err := template.Execute("filter.condition", f.condition, data)
+	if err != nil {
+		return nil, fmt.Errorf("filter transform: failed to process condition template: %w", err)
+	}
+
+	conditionValue, err := strconv.ParseBool(strings.TrimSpace(string(conditionBytes)))
+	if err != nil {
+		return nil, fmt.Errorf("filter transform: failed to parse condition as boolean: %w", err)
+	}
+	if !conditionValue {
+		return nil, nil
+	}
+	return data, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("filter", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		condition, exists := config.Properties[conditionKey]
+		if !exists {
+			return nil, fmt.Errorf("filter transform: missing config property: %s", conditionKey)
+		}
+		if err := template.Validate(conditionKey, condition); err != nil {
+			return nil, fmt.Errorf("filter transform: invalid %s template: %w", conditionKey, err)
+		}
+		return &filter{
+			condition: condition,
+		}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		name          string
+		condition     string
+		dropped       bool
+		expectedError string
+	}{
+		{
+			name:      "passes",
+			condition: `{{ eq (index .Properties "unit") "temperature" }}`,
+		},
+		{
+			name:      "drops",
+			condition: `{{ eq (index .Properties "unit") "humidity" }}`,
+			dropped:   true,
+		},
+		{
+			name:      "surrounding whitespace",
+			condition: " true\n",
+		},
+		{
+			name:          "not a boolean",
+			condition:     `{{ index .Properties "unit" }}`,
+			expectedError: `filter transform: failed to parse condition as boolean: strconv.ParseBool: parsing "temperature": invalid syntax`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &output.Data{
+				Properties: map[string]interface{}{
+					"unit": "temperature",
+				},
+			}
+			transformed, err := (&filter{condition: tt.condition}).Transform(context.Background(), data)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.dropped {
+				assert.Nil(t, transformed)
+			} else {
+				assert.Equal(t, data, transformed)
+			}
+		})
+	}
+}
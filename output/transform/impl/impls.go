@@ -1 +1,11 @@
 package impl
+
+import (
+	_ "github.com/nikiforov-soft/yasp/output/transform/impl/deleteproperties"
+	_ "github.com/nikiforov-soft/yasp/output/transform/impl/filter"
+	_ "github.com/nikiforov-soft/yasp/output/transform/impl/jsonobject"
+	_ "github.com/nikiforov-soft/yasp/output/transform/impl/onchange"
+	_ "github.com/nikiforov-soft/yasp/output/transform/impl/ratelimit"
+	_ "github.com/nikiforov-soft/yasp/output/transform/impl/rename"
+	_ "github.com/nikiforov-soft/yasp/output/transform/impl/set"
+)
@@ -0,0 +1,59 @@
+package jsonobject
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+	"github.com/nikiforov-soft/yasp/output/transform"
+	"github.com/nikiforov-soft/yasp/template"
+)
+
+// jsonObject replaces the data with a json object holding the rendered template of every configured property,
+// values which render valid json such as numbers, booleans or objects are embedded as is, anything else as a string.
+type jsonObject struct {
+	fields map[string]string
+}
+
+func (j *jsonObject) Transform(_ context.Context, data *output.Data) (*output.Data, error) {
+	object := make(map[string]any, len(j.fields))
+	for key, value := range j.fields {
+		renderedValue, err := template.Execute(key, value, data)
+		if err != nil {
+			return nil, fmt.Errorf("json transform: failed to process %s template: %w", key, err)
+		}
+		if json.Valid(renderedValue) {
+			object[key] = json.RawMessage(renderedValue)
+		} else {
+			object[key] = string(renderedValue)
+		}
+	}
+
+	objectBytes, err := json.Marshal(object)
+	if err != nil {
+		return nil, fmt.Errorf("json transform: failed to json encode object: %w", err)
+	}
+	data.Data = objectBytes
+	return data, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("json", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		if len(config.Properties) == 0 {
+			return nil, fmt.Errorf("json transform: no properties configured")
+		}
+		for key, value := range config.Properties {
+			if err := template.Validate(key, value); err != nil {
+				return nil, fmt.Errorf("json transform: invalid %s template: %w", key, err)
+			}
+		}
+		return &jsonObject{
+			fields: config.Properties,
+		}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,56 @@
+package jsonobject
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+func TestJsonObject(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   map[string]string
+		expected string
+	}{
+		{
+			name: "numbers and strings",
+			fields: map[string]string{
+				"device":      `{{ index .Properties "deviceName" }}`,
+				"temperature": `{{ index .Properties "temperature" }}`,
+			},
+			expected: `{"device":"kitchen","temperature":21.5}`,
+		},
+		{
+			name: "booleans and objects",
+			fields: map[string]string{
+				"online": `true`,
+				"raw":    `{{ printf "%s" .Data }}`,
+			},
+			expected: `{"online":true,"raw":{"value":1}}`,
+		},
+		{
+			name: "empty value",
+			fields: map[string]string{
+				"missing": `{{ index .Properties "unknown" }}`,
+			},
+			expected: `{"missing":"<no value>"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &output.Data{
+				Data: []byte(`{"value":1}`),
+				Properties: map[string]interface{}{
+					"deviceName":  "kitchen",
+					"temperature": 21.5,
+				},
+			}
+			transformed, err := (&jsonObject{fields: tt.fields}).Transform(context.Background(), data)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(transformed.Data))
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package onchange
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/internal/ttlmap"
+	"github.com/nikiforov-soft/yasp/output"
+	"github.com/nikiforov-soft/yasp/output/transform"
+	"github.com/nikiforov-soft/yasp/template"
+)
+
+const (
+	keyKey        = "key"
+	propertiesKey = "properties"
+	deadbandKey   = "deadband"
+	heartbeatKey  = "heartbeat"
+
+	defaultKey = `{{ index .Properties "deviceName" }}/{{ index .Properties "unit" }}`
+
+	keysSize = 1024
+	// keyExpiry is how long the values of a silent key are kept without a heartbeat, the next event of an expired key
+	// is passed as the first one
+	keyExpiry = 24 * time.Hour
+)
+
+// onChange passes an event only when one of the selected properties changed since the last event passed for its key,
+// numeric values have to change by more than the deadband, the heartbeat passes the event after a period of silence.
+type onChange struct {
+	key        string
+	properties []string
+	deadband   float64
+	heartbeat  time.Duration
+	now        func() time.Time
+	published  *ttlmap.Map[string, map[string]string]
+	lock       sync.Mutex
+}
+
+func (oc *onChange) Transform(_ context.Context, data *output.Data) (*output.Data, error) {
+	key, err := template.Execute("on-change.key", oc.key, data)
+	if err != nil {
+		return nil, fmt.Errorf("on-change transform: failed to process key template: %w", err)
+	}
+
+	values := make(map[string]string, len(oc.properties))
+	for _, property := range oc.properties {
+		if value, exists := data.Properties[property]; exists && value != nil {
+			values[property] = fmt.Sprint(value)
+		}
+	}
+
+	oc.lock.Lock()
+	defer oc.lock.Unlock()
+
+	now := oc.now()
+	last, at, exists := oc.published.Load(string(key))
+	if exists && !oc.changed(last, values) && (oc.heartbeat == 0 || now.Sub(at) < oc.heartbeat) {
+		return nil, nil
+	}
+	oc.published.Store(string(key), values, now)
+	return data, nil
+}
+
+func (oc *onChange) changed(last, current map[string]string) bool {
+	for _, property := range oc.properties {
+		lastValue, lastExists := last[property]
+		currentValue, currentExists := current[property]
+		if lastExists != currentExists {
+			return true
+		}
+		if lastValue == currentValue {
+			continue
+		}
+
+		lastNumber, lastErr := strconv.ParseFloat(lastValue, 64)
+		currentNumber, currentErr := strconv.ParseFloat(currentValue, 64)
+		if lastErr != nil || currentErr != nil || math.Abs(currentNumber-lastNumber) > oc.deadband {
+			return true
+		}
+	}
+	return false
+}
+
+func newOnChange(config *config.Transform) (*onChange, error) {
+	propertiesValue, exists := config.Properties[propertiesKey]
+	if !exists {
+		return nil, fmt.Errorf("on-change transform: missing config property: %s", propertiesKey)
+	}
+	var properties []string
+	for _, property := range strings.Split(propertiesValue, ",") {
+		if property = strings.TrimSpace(property); property != "" {
+			properties = append(properties, property)
+		}
+	}
+	if len(properties) == 0 {
+		return nil, fmt.Errorf("on-change transform: invalid %s: %s", propertiesKey, propertiesValue)
+	}
+
+	var deadband float64
+	if deadbandValue, exists := config.Properties[deadbandKey]; exists {
+		var err error
+		deadband, err = strconv.ParseFloat(deadbandValue, 64)
+		if err != nil || deadband < 0 {
+			return nil, fmt.Errorf("on-change transform: invalid %s: %s", deadbandKey, deadbandValue)
+		}
+	}
+
+	var heartbeat time.Duration
+	if heartbeatValue, exists := config.Properties[heartbeatKey]; exists {
+		var err error
+		heartbeat, err = time.ParseDuration(heartbeatValue)
+		if err != nil || heartbeat <= 0 {
+			return nil, fmt.Errorf("on-change transform: invalid %s: %s", heartbeatKey, heartbeatValue)
+		}
+	}
+
+	key := defaultKey
+	if keyValue, exists := config.Properties[keyKey]; exists {
+		key = keyValue
+	}
+	if err := template.Validate(keyKey, key); err != nil {
+		return nil, fmt.Errorf("on-change transform: invalid %s template: %w", keyKey, err)
+	}
+
+	expiry := keyExpiry
+	if heartbeat > 0 {
+		expiry = heartbeat
+	}
+	return &onChange{
+		key:        key,
+		properties: properties,
+		deadband:   deadband,
+		heartbeat:  heartbeat,
+		now:        time.Now,
+		published:  ttlmap.New[string, map[string]string](expiry, keysSize),
+	}, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("on-change", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		return newOnChange(config)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
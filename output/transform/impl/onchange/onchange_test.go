@@ -0,0 +1,145 @@
+package onchange
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+func TestOnChange(t *testing.T) {
+	oc, err := newOnChange(&config.Transform{
+		Name: "on-change",
+		Properties: map[string]string{
+			"key":        `{{ index .Properties "bridge" }}/{{ index .Properties "unitAddress" }}`,
+			"properties": "mode, temperature, roomTemperature",
+			"deadband":   "0.5",
+			"heartbeat":  "1m",
+		},
+	})
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	oc.now = func() time.Time { return now }
+
+	passes := func(unitAddress string, properties map[string]interface{}) bool {
+		properties["bridge"] = "P1P2"
+		properties["unitAddress"] = unitAddress
+		properties["inputId"] = now.UnixNano()
+		data, err := oc.Transform(context.Background(), &output.Data{
+			Properties: properties,
+		})
+		require.NoError(t, err)
+		return data != nil
+	}
+
+	assert.True(t, passes("1", map[string]interface{}{"mode": "Cooling", "temperature": "22", "roomTemperature": 20.0}))
+	assert.True(t, passes("2", map[string]interface{}{"mode": "Cooling", "temperature": "22", "roomTemperature": 20.0}))
+	assert.False(t, passes("1", map[string]interface{}{"mode": "Cooling", "temperature": "22", "roomTemperature": 20.0}))
+
+	// numeric changes within the deadband of the last passed value are dropped
+	assert.False(t, passes("1", map[string]interface{}{"mode": "Cooling", "temperature": "22", "roomTemperature": 20.3}))
+	assert.False(t, passes("1", map[string]interface{}{"mode": "Cooling", "temperature": "22", "roomTemperature": 20.5}))
+	assert.True(t, passes("1", map[string]interface{}{"mode": "Cooling", "temperature": "22", "roomTemperature": 20.6}))
+
+	assert.True(t, passes("1", map[string]interface{}{"mode": "Heating", "temperature": "22", "roomTemperature": 20.6}))
+	assert.True(t, passes("1", map[string]interface{}{"mode": "Heating", "temperature": "22"}))
+	assert.False(t, passes("1", map[string]interface{}{"mode": "Heating", "temperature": "22"}))
+
+	now = now.Add(59 * time.Second)
+	assert.False(t, passes("1", map[string]interface{}{"mode": "Heating", "temperature": "22"}))
+
+	now = now.Add(time.Second)
+	assert.True(t, passes("1", map[string]interface{}{"mode": "Heating", "temperature": "22"}))
+	assert.False(t, passes("1", map[string]interface{}{"mode": "Heating", "temperature": "22"}))
+}
+
+func TestOnChangeConfig(t *testing.T) {
+	_, err := newOnChange(&config.Transform{Name: "on-change"})
+	assert.EqualError(t, err, "on-change transform: missing config property: properties")
+
+	_, err = newOnChange(&config.Transform{Name: "on-change", Properties: map[string]string{"properties": " , "}})
+	assert.EqualError(t, err, "on-change transform: invalid properties:  , ")
+
+	_, err = newOnChange(&config.Transform{Name: "on-change", Properties: map[string]string{"properties": "value", "deadband": "-1"}})
+	assert.EqualError(t, err, "on-change transform: invalid deadband: -1")
+
+	_, err = newOnChange(&config.Transform{Name: "on-change", Properties: map[string]string{"properties": "value", "heartbeat": "never"}})
+	assert.EqualError(t, err, "on-change transform: invalid heartbeat: never")
+}
+
+func TestOnChangeDefaultKey(t *testing.T) {
+	oc, err := newOnChange(&config.Transform{
+		Name: "on-change",
+		Properties: map[string]string{
+			"properties": "value",
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		deviceName string
+		unit       string
+		value      interface{}
+		passes     bool
+	}{
+		{deviceName: "kitchen", unit: "Temperature", value: 21.5, passes: true},
+		{deviceName: "kitchen", unit: "Humidity", value: 40, passes: true},
+		{deviceName: "kitchen", unit: "Temperature", value: 21.5, passes: false},
+		{deviceName: "kitchen", unit: "Humidity", value: 40, passes: false},
+		{deviceName: "garage", unit: "Temperature", value: 21.5, passes: true},
+		{deviceName: "kitchen", unit: "Temperature", value: 22, passes: true},
+		{deviceName: "kitchen", unit: "Humidity", value: 40, passes: false},
+		{deviceName: "kitchen", unit: "Humidity", value: 41, passes: true},
+	}
+	for i, tt := range tests {
+		data, err := oc.Transform(context.Background(), &output.Data{
+			Properties: map[string]interface{}{
+				"deviceName": tt.deviceName,
+				"unit":       tt.unit,
+				"value":      tt.value,
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, tt.passes, data != nil, "event %d: %s %s %v", i, tt.deviceName, tt.unit, tt.value)
+	}
+}
+
+func TestOnChangeExpiryWithoutHeartbeat(t *testing.T) {
+	oc, err := newOnChange(&config.Transform{
+		Name: "on-change",
+		Properties: map[string]string{
+			"key":        `{{ index .Properties "deviceName" }}`,
+			"properties": "value",
+		},
+	})
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	oc.now = func() time.Time { return now }
+	passes := func(deviceName string) bool {
+		data, err := oc.Transform(context.Background(), &output.Data{
+			Properties: map[string]interface{}{"deviceName": deviceName, "value": "1"},
+		})
+		require.NoError(t, err)
+		return data != nil
+	}
+
+	for i := 0; i < keysSize; i++ {
+		assert.True(t, passes(strconv.Itoa(i)))
+	}
+	assert.False(t, passes("0"))
+
+	// the keys silent for the expiry are pruned once the map is full
+	now = now.Add(keyExpiry)
+	assert.True(t, passes("active"))
+	assert.Equal(t, 1, oc.published.Len())
+	assert.True(t, passes("0"))
+	assert.False(t, passes("active"))
+}
@@ -0,0 +1,84 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/internal/ttlmap"
+	"github.com/nikiforov-soft/yasp/output"
+	"github.com/nikiforov-soft/yasp/output/transform"
+	"github.com/nikiforov-soft/yasp/template"
+)
+
+const (
+	keyKey      = "key"
+	intervalKey = "interval"
+
+	defaultKey = `{{ index .Properties "deviceName" }}/{{ index .Properties "unit" }}`
+
+	keysSize = 1024
+)
+
+// rateLimit passes at most one event per key within the interval, the key is rendered from a template.
+type rateLimit struct {
+	key      string
+	interval time.Duration
+	now      func() time.Time
+	lastSeen *ttlmap.Map[string, struct{}]
+	lock     sync.Mutex
+}
+
+func (r *rateLimit) Transform(_ context.Context, data *output.Data) (*output.Data, error) {
+	key, err := template.Execute("rate-limit.key", r.key, data)
+	if err != nil {
+		return nil, fmt.Errorf("rate-limit transform: failed to process key template: %w", err)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	now := r.now()
+	if _, lastSeen, exists := r.lastSeen.Load(string(key)); exists && now.Sub(lastSeen) < r.interval {
+		return nil, nil
+	}
+	r.lastSeen.Store(string(key), struct{}{}, now)
+	return data, nil
+}
+
+func newRateLimit(config *config.Transform) (*rateLimit, error) {
+	intervalValue, exists := config.Properties[intervalKey]
+	if !exists {
+		return nil, fmt.Errorf("rate-limit transform: missing config property: %s", intervalKey)
+	}
+	interval, err := time.ParseDuration(intervalValue)
+	if err != nil || interval <= 0 {
+		return nil, fmt.Errorf("rate-limit transform: invalid %s: %s", intervalKey, intervalValue)
+	}
+
+	key := defaultKey
+	if keyValue, exists := config.Properties[keyKey]; exists {
+		key = keyValue
+	}
+	if err := template.Validate(keyKey, key); err != nil {
+		return nil, fmt.Errorf("rate-limit transform: invalid %s template: %w", keyKey, err)
+	}
+
+	return &rateLimit{
+		key:      key,
+		interval: interval,
+		now:      time.Now,
+		lastSeen: ttlmap.New[string, struct{}](interval, keysSize),
+	}, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("rate-limit", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		return newRateLimit(config)
+	})
+	if err != nil {
+		panic(err)
+	}
+}
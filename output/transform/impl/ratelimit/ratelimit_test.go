@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+func TestRateLimit(t *testing.T) {
+	rl, err := newRateLimit(&config.Transform{
+		Name: "rate-limit",
+		Properties: map[string]string{
+			"interval": "1m",
+		},
+	})
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rl.now = func() time.Time { return now }
+
+	passes := func(deviceName string) bool {
+		data, err := rl.Transform(context.Background(), &output.Data{
+			Properties: map[string]interface{}{
+				"deviceName": deviceName,
+				"unit":       "Temperature",
+			},
+		})
+		require.NoError(t, err)
+		return data != nil
+	}
+
+	assert.True(t, passes("kitchen"))
+	assert.True(t, passes("garage"))
+	assert.False(t, passes("kitchen"))
+
+	now = now.Add(59 * time.Second)
+	assert.False(t, passes("kitchen"))
+
+	now = now.Add(time.Second)
+	assert.True(t, passes("kitchen"))
+	assert.True(t, passes("garage"))
+}
+
+func TestRateLimitDefaultKey(t *testing.T) {
+	rl, err := newRateLimit(&config.Transform{
+		Name: "rate-limit",
+		Properties: map[string]string{
+			"interval": "1m",
+		},
+	})
+	require.NoError(t, err)
+
+	passes := func(unit string) bool {
+		data, err := rl.Transform(context.Background(), &output.Data{
+			Properties: map[string]interface{}{
+				"deviceName": "kitchen",
+				"unit":       unit,
+			},
+		})
+		require.NoError(t, err)
+		return data != nil
+	}
+
+	// every measurement of a device is limited on its own
+	assert.True(t, passes("Temperature"))
+	assert.True(t, passes("Humidity"))
+	assert.False(t, passes("Temperature"))
+	assert.False(t, passes("Humidity"))
+}
+
+func TestRateLimitConfig(t *testing.T) {
+	_, err := newRateLimit(&config.Transform{Name: "rate-limit"})
+	assert.EqualError(t, err, "rate-limit transform: missing config property: interval")
+
+	_, err = newRateLimit(&config.Transform{Name: "rate-limit", Properties: map[string]string{"interval": "soon"}})
+	assert.EqualError(t, err, "rate-limit transform: invalid interval: soon")
+}
@@ -0,0 +1,48 @@
+package rename
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+	"github.com/nikiforov-soft/yasp/output/transform"
+)
+
+// rename moves the value of every configured property to its new name, missing properties are skipped.
+type rename struct {
+	names map[string]string
+}
+
+func (r *rename) Transform(_ context.Context, data *output.Data) (*output.Data, error) {
+	values := make(map[string]interface{}, len(r.names))
+	for from := range r.names {
+		if value, exists := data.Properties[from]; exists {
+			values[from] = value
+			delete(data.Properties, from)
+		}
+	}
+	for from, value := range values {
+		data.Properties[r.names[from]] = value
+	}
+	return data, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("rename", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		if len(config.Properties) == 0 {
+			return nil, fmt.Errorf("rename transform: no properties configured")
+		}
+		for from, to := range config.Properties {
+			if to == "" {
+				return nil, fmt.Errorf("rename transform: missing new name of property: %s", from)
+			}
+		}
+		return &rename{
+			names: config.Properties,
+		}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,54 @@
+package rename
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+func TestRename(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    map[string]string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "moves a property",
+			names:    map[string]string{"deviceName": "device"},
+			expected: map[string]interface{}{"device": "Kitchen", "unit": "Temperature", "value": 21.5},
+		},
+		{
+			name:     "replaces the property of the new name",
+			names:    map[string]string{"deviceName": "unit"},
+			expected: map[string]interface{}{"unit": "Kitchen", "value": 21.5},
+		},
+		{
+			name:     "swaps properties",
+			names:    map[string]string{"deviceName": "unit", "unit": "deviceName"},
+			expected: map[string]interface{}{"deviceName": "Temperature", "unit": "Kitchen", "value": 21.5},
+		},
+		{
+			name:     "skips missing properties",
+			names:    map[string]string{"battery": "batteryLevel"},
+			expected: map[string]interface{}{"deviceName": "Kitchen", "unit": "Temperature", "value": 21.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &output.Data{
+				Properties: map[string]interface{}{
+					"deviceName": "Kitchen",
+					"unit":       "Temperature",
+					"value":      21.5,
+				},
+			}
+			transformed, err := (&rename{names: tt.names}).Transform(context.Background(), data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, transformed.Properties)
+		})
+	}
+}
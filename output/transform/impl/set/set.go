@@ -0,0 +1,52 @@
+package set
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+	"github.com/nikiforov-soft/yasp/output/transform"
+	"github.com/nikiforov-soft/yasp/template"
+)
+
+// set sets every configured property to the rendered value of its template.
+type set struct {
+	properties map[string]string
+}
+
+func (s *set) Transform(_ context.Context, data *output.Data) (*output.Data, error) {
+	values := make(map[string]string, len(s.properties))
+	for key, value := range s.properties {
+		renderedValue, err := template.Execute(key, value, data)
+		if err != nil {
+			return nil, fmt.Errorf("set transform: failed to process %s template: %w", key, err)
+		}
+		values[key] = string(renderedValue)
+	}
+
+	// every template sees the properties as they were before the transform
+	for key, value := range values {
+		data.Properties[key] = value
+	}
+	return data, nil
+}
+
+func init() {
+	err := transform.RegisterTransform("set", func(ctx context.Context, config *config.Transform) (transform.Transform, error) {
+		if len(config.Properties) == 0 {
+			return nil, fmt.Errorf("set transform: no properties configured")
+		}
+		for key, value := range config.Properties {
+			if err := template.Validate(key, value); err != nil {
+				return nil, fmt.Errorf("set transform: invalid %s template: %w", key, err)
+			}
+		}
+		return &set{
+			properties: config.Properties,
+		}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
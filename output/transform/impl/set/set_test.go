@@ -0,0 +1,52 @@
+package set
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name       string
+		properties map[string]string
+		expected   map[string]interface{}
+	}{
+		{
+			name:       "adds a property",
+			properties: map[string]string{"room": `{{ index .Properties "deviceName" | ToLower }}`},
+			expected:   map[string]interface{}{"deviceName": "Kitchen", "unit": "Temperature", "value": 21.5, "room": "kitchen"},
+		},
+		{
+			name:       "replaces a property",
+			properties: map[string]string{"unit": `{{ index .Properties "unit" | ToLower }}`},
+			expected:   map[string]interface{}{"deviceName": "Kitchen", "unit": "temperature", "value": 21.5},
+		},
+		{
+			name: "templates see the properties before the transform",
+			properties: map[string]string{
+				"deviceName": `{{ index .Properties "unit" }}`,
+				"unit":       `{{ index .Properties "deviceName" }}`,
+			},
+			expected: map[string]interface{}{"deviceName": "Temperature", "unit": "Kitchen", "value": 21.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &output.Data{
+				Properties: map[string]interface{}{
+					"deviceName": "Kitchen",
+					"unit":       "Temperature",
+					"value":      21.5,
+				},
+			}
+			transformed, err := (&set{properties: tt.properties}).Transform(context.Background(), data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, transformed.Properties)
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/nikiforov-soft/yasp/output"
 )
 
+// Transform returns the transformed data or nil to drop the event, every output receives its own copy of the data,
+// so transforms may modify and return it.
 type Transform interface {
 	Transform(ctx context.Context, data *output.Data) (*output.Data, error)
 }
@@ -0,0 +1,108 @@
+package process
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+	"github.com/nikiforov-soft/yasp/template"
+)
+
+// bucket accumulates the values of a key within the current window.
+type bucket struct {
+	min   float64
+	max   float64
+	sum   float64
+	last  float64
+	count int
+	data  *output.Data
+}
+
+// aggregator buckets the numeric value property of the events by the rendered key until the window is flushed.
+type aggregator struct {
+	key     string
+	buckets map[string]*bucket
+	keys    []string
+	lock    sync.Mutex
+}
+
+func newAggregator(aggregateConfig *config.OutputAggregate) (*aggregator, error) {
+	key := aggregateConfig.GetKey()
+	if err := template.Validate("key", key); err != nil {
+		return nil, fmt.Errorf("process: invalid output aggregate key template: %w", err)
+	}
+	return &aggregator{
+		key:     key,
+		buckets: make(map[string]*bucket),
+	}, nil
+}
+
+// add adds the value of outputData to the bucket of its key, false is returned when the event has no numeric value.
+func (a *aggregator) add(outputData *output.Data) (bool, error) {
+	rawValue, exists := outputData.Properties["value"]
+	if !exists || rawValue == nil {
+		return false, nil
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprint(rawValue)), 64)
+	if err != nil {
+		return false, nil
+	}
+
+	key, err := template.Execute("aggregate.key", a.key, outputData)
+	if err != nil {
+		return false, fmt.Errorf("process: failed to process output aggregate key template: %w", err)
+	}
+
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	b, exists := a.buckets[string(key)]
+	if !exists {
+		b = &bucket{
+			min: value,
+			max: value,
+		}
+		a.buckets[string(key)] = b
+		a.keys = append(a.keys, string(key))
+	}
+	b.min = min(b.min, value)
+	b.max = max(b.max, value)
+	b.sum += value
+	b.last = value
+	b.count++
+	b.data = outputData
+	return true, nil
+}
+
+// flush returns an event per key aggregated since the previous flush, in the order the keys were first seen, and
+// starts a new window. The events keep the properties of the last aggregated event.
+func (a *aggregator) flush() []*output.Data {
+	a.lock.Lock()
+	buckets := a.buckets
+	keys := a.keys
+	a.buckets = make(map[string]*bucket)
+	a.keys = nil
+	a.lock.Unlock()
+
+	result := make([]*output.Data, 0, len(keys))
+	for _, key := range keys {
+		b := buckets[key]
+		properties := make(map[string]interface{}, len(b.data.Properties)+5)
+		for k, v := range b.data.Properties {
+			properties[k] = v
+		}
+		properties["min"] = b.min
+		properties["max"] = b.max
+		properties["mean"] = b.sum / float64(b.count)
+		properties["count"] = b.count
+		properties["last"] = b.last
+		result = append(result, &output.Data{
+			Data:       b.data.Data,
+			Properties: properties,
+		})
+	}
+	return result
+}
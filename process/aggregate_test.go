@@ -0,0 +1,132 @@
+package process
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+func measurement(deviceName, unit string, value interface{}) *output.Data {
+	return &output.Data{
+		Data: []byte(deviceName),
+		Properties: map[string]interface{}{
+			"deviceName": deviceName,
+			"unit":       unit,
+			"value":      value,
+		},
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	a, err := newAggregator(&config.OutputAggregate{Enabled: true})
+	require.NoError(t, err)
+
+	for _, data := range []*output.Data{
+		measurement("kitchen", "Temperature", "21.5"),
+		measurement("garage", "Temperature", 12.0),
+		measurement("kitchen", "Temperature", 20.5),
+		measurement("kitchen", "Humidity", 40),
+		measurement("kitchen", "Temperature", "22.5"),
+	} {
+		aggregated, err := a.add(data)
+		require.NoError(t, err)
+		assert.True(t, aggregated)
+	}
+
+	aggregated, err := a.add(measurement("kitchen", "Motion", "detected"))
+	require.NoError(t, err)
+	assert.False(t, aggregated)
+
+	flushed := a.flush()
+	require.Len(t, flushed, 3)
+	assert.Equal(t, map[string]interface{}{
+		"deviceName": "kitchen",
+		"unit":       "Temperature",
+		"value":      "22.5",
+		"min":        20.5,
+		"max":        22.5,
+		"mean":       21.5,
+		"count":      3,
+		"last":       22.5,
+	}, flushed[0].Properties)
+	assert.Equal(t, "garage", string(flushed[1].Data))
+	assert.Equal(t, 1, flushed[1].Properties["count"])
+	assert.Equal(t, "Humidity", flushed[2].Properties["unit"])
+
+	assert.Empty(t, a.flush())
+}
+
+type recordingOutput struct {
+	published []*output.Data
+	lock      sync.Mutex
+}
+
+func (ro *recordingOutput) Publish(_ context.Context, data *output.Data) error {
+	ro.lock.Lock()
+	defer ro.lock.Unlock()
+	ro.published = append(ro.published, data)
+	return nil
+}
+
+func (ro *recordingOutput) Close(_ context.Context) error {
+	return nil
+}
+
+func TestOutputGroupAggregate(t *testing.T) {
+	og, err := newOutputGroup("sensor", "influxdb2", 0, &config.Output{
+		Aggregate: &config.OutputAggregate{
+			Enabled: true,
+			Window:  time.Hour,
+		},
+	})
+	require.NoError(t, err)
+	ro := &recordingOutput{}
+	og.Output = ro
+	og.start(context.Background())
+
+	og.enqueue(context.Background(), measurement("kitchen", "Temperature", "21"))
+	og.enqueue(context.Background(), measurement("kitchen", "Temperature", "23"))
+	og.enqueue(context.Background(), measurement("kitchen", "Motion", "detected"))
+
+	// the partial window is published when the output group is closed
+	require.NoError(t, og.Close())
+	require.Len(t, ro.published, 2)
+	assert.Equal(t, "detected", ro.published[0].Properties["value"])
+	assert.Equal(t, 22.0, ro.published[1].Properties["mean"])
+	assert.Equal(t, 2, ro.published[1].Properties["count"])
+}
+
+func TestOutputGroupAggregateQueue(t *testing.T) {
+	og, err := newOutputGroup("aggregate-queue", "mqtt", 0, &config.Output{
+		Queue: &config.OutputQueue{Size: 2, OverflowPolicy: config.OverflowPolicyDropNewest},
+		Aggregate: &config.OutputAggregate{
+			Enabled: true,
+			Window:  time.Hour,
+		},
+	})
+	require.NoError(t, err)
+	ro := &recordingOutput{}
+	og.Output = ro
+
+	// the flushed windows are queued behind the pending events and subject to the overflow policy
+	og.enqueue(context.Background(), measurement("kitchen", "Motion", "detected"))
+	og.enqueue(context.Background(), measurement("kitchen", "Temperature", "21"))
+	og.enqueue(context.Background(), measurement("garage", "Temperature", "12"))
+	og.flushAggregates(context.Background())
+	assert.Equal(t, 1.0, testutil.ToFloat64(outputQueueDroppedCounter.WithLabelValues("aggregate-queue", "mqtt-0", config.OverflowPolicyDropNewest)))
+
+	og.start(context.Background())
+	require.NoError(t, og.Close())
+	require.Len(t, ro.published, 2)
+	assert.Equal(t, "detected", ro.published[0].Properties["value"])
+	assert.Equal(t, "kitchen", ro.published[1].Properties["deviceName"])
+	assert.Equal(t, 21.0, ro.published[1].Properties["mean"])
+}
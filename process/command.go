@@ -0,0 +1,337 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+var (
+	commandsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "commands",
+		Help:      "The amount of device commands by their outcome.",
+		Namespace: "yasp",
+		Subsystem: "process",
+	}, []string{"sensor", "device", "status"})
+)
+
+// commandQueueSize is the amount of received commands and acks waiting for the command worker.
+const commandQueueSize = 100
+
+const (
+	commandStatusAcknowledged = "acknowledged"
+	commandStatusTimeout      = "timeout"
+	commandStatusFailed       = "failed"
+)
+
+// commandAck is published to the ack topic of the device once the outcome of a command is known.
+type commandAck struct {
+	Status  string         `json:"status"`
+	Command device.Command `json:"command"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// pendingCommand is a sent command waiting for the decoded device state to reflect it.
+type pendingCommand struct {
+	device  *sensorDevice
+	command device.Command
+	timer   *time.Timer
+}
+
+// commandHandler receives the commands of the devices of a sensor group, publishes the encoded messages and
+// acknowledges the commands by watching the events decoded afterwards. The commands are encoded and published by a
+// single worker, so neither the mqtt client nor the sensor wait for the broker.
+type commandHandler struct {
+	config            *config.SensorCommands
+	sensorName        string
+	devices           []*sensorDevice
+	connectionManager *autopaho.ConnectionManager
+	publish           func(ctx context.Context, topic string, payload []byte) error
+	work              chan func(ctx context.Context)
+	cancel            context.CancelFunc
+	worker            sync.WaitGroup
+	pending           []*pendingCommand
+	closed            bool
+	pendingLock       sync.Mutex
+}
+
+func newCommandHandler(ctx context.Context, sensorName string, commandsConfig *config.SensorCommands, devices []*sensorDevice) (*commandHandler, error) {
+	ch := &commandHandler{
+		config:     commandsConfig,
+		sensorName: sensorName,
+		work:       make(chan func(ctx context.Context), commandQueueSize),
+	}
+	for _, sd := range devices {
+		if _, ok := sd.device.(device.Commander); !ok {
+			continue
+		}
+		if !commandsConfig.IsTopicLevel(sd.config.Name) {
+			return nil, fmt.Errorf("process: device %q name must not contain whitespace, +, # or / when commands are enabled", sd.config.Name)
+		}
+		ch.devices = append(ch.devices, sd)
+	}
+	if len(ch.devices) == 0 {
+		return nil, fmt.Errorf("process: sensor %s has commands enabled but none of its devices supports commands", sensorName)
+	}
+
+	keepAlive := commandsConfig.KeepAlive
+	if keepAlive == 0 {
+		keepAlive = 5
+	}
+
+	clientConfig := autopaho.ClientConfig{
+		BrokerUrls:       commandsConfig.GetBrokerUrls(),
+		KeepAlive:        keepAlive,
+		ReconnectBackoff: autopaho.DefaultExponentialBackoff(),
+		OnConnectionUp: func(cm *autopaho.ConnectionManager, connAck *paho.Connack) {
+			subscriptions := make([]paho.SubscribeOptions, 0, len(ch.devices))
+			for _, sd := range ch.devices {
+				subscriptions = append(subscriptions, paho.SubscribeOptions{
+					Topic: ch.commandTopic(sd),
+					QoS:   commandsConfig.QoS,
+				})
+			}
+			if _, err := cm.Subscribe(ctx, &paho.Subscribe{
+				Subscriptions: subscriptions,
+			}); err != nil {
+				logrus.WithError(err).WithField("sensor", sensorName).Error("process: failed to subscribe to command topics")
+				return
+			}
+			logrus.WithField("sensor", sensorName).Info("process: subscribed to command topics")
+		},
+		OnConnectError: func(err error) { logrus.WithError(err).Error("process: commands failed to connect to server") },
+		ClientConfig: paho.ClientConfig{
+			ClientID: commandsConfig.ClientId,
+			Router: paho.NewStandardRouterWithDefault(func(publish *paho.Publish) {
+				ch.submit(func(ctx context.Context) {
+					ch.receive(ctx, publish.Topic, publish.Payload)
+				})
+			}),
+			OnClientError: func(err error) { logrus.WithError(err).Error("process: commands client error") },
+		},
+	}
+
+	if len(commandsConfig.Username) != 0 && len(commandsConfig.Password) != 0 {
+		clientConfig.ConnectUsername = commandsConfig.Username
+		clientConfig.ConnectPassword = []byte(commandsConfig.Password)
+	}
+
+	connectionManager, err := autopaho.NewConnection(ctx, clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("process: failed to initialize commands connection manager: %w", err)
+	}
+	ch.connectionManager = connectionManager
+	ch.publish = func(ctx context.Context, topic string, payload []byte) error {
+		if err := connectionManager.AwaitConnection(ctx); err != nil {
+			return err
+		}
+		_, err := connectionManager.Publish(ctx, &paho.Publish{
+			QoS:     commandsConfig.QoS,
+			Topic:   topic,
+			Payload: payload,
+		})
+		return err
+	}
+	// the commands received meanwhile wait in the queue
+	ch.start(ctx)
+	return ch, nil
+}
+
+// start launches the worker encoding the commands and publishing the messages and acks, it runs until the handler is
+// closed.
+func (ch *commandHandler) start(ctx context.Context) {
+	ctx, ch.cancel = context.WithCancel(ctx)
+	ch.worker.Add(1)
+	go func() {
+		defer ch.worker.Done()
+		for job := range ch.work {
+			if ctx.Err() == nil {
+				job(ctx)
+			}
+		}
+	}()
+}
+
+// submit hands job to the worker, the job is dropped when the handler is closed or the worker is falling behind.
+func (ch *commandHandler) submit(job func(ctx context.Context)) {
+	ch.pendingLock.Lock()
+	defer ch.pendingLock.Unlock()
+	ch.submitLocked(job)
+}
+
+func (ch *commandHandler) submitLocked(job func(ctx context.Context)) {
+	if ch.closed {
+		return
+	}
+	select {
+	case ch.work <- job:
+	default:
+		logrus.WithField("sensor", ch.sensorName).Warn("process: command queue full, dropping command")
+	}
+}
+
+func (ch *commandHandler) commandTopic(sd *sensorDevice) string {
+	return ch.config.Topic + "/" + sd.config.Name + "/set"
+}
+
+func (ch *commandHandler) ackTopic(sd *sensorDevice) string {
+	return ch.config.Topic + "/" + sd.config.Name + "/ack"
+}
+
+// receive encodes the command published to the command topic of a device and publishes the encoded messages.
+func (ch *commandHandler) receive(ctx context.Context, topic string, payload []byte) {
+	index := slices.IndexFunc(ch.devices, func(sd *sensorDevice) bool {
+		return ch.commandTopic(sd) == topic
+	})
+	if index == -1 {
+		return
+	}
+	sd := ch.devices[index]
+
+	var command device.Command
+	if err := json.Unmarshal(payload, &command); err != nil {
+		ch.acknowledge(ctx, sd, command, commandStatusFailed, fmt.Errorf("process: invalid command: %w", err))
+		return
+	}
+
+	resolved, messages, err := sd.device.(device.Commander).Encode(ctx, command)
+	if err != nil {
+		ch.acknowledge(ctx, sd, command, commandStatusFailed, err)
+		return
+	}
+	command = resolved
+	for _, message := range messages {
+		outputTopic, _ := message.Properties["outputTopic"].(string)
+		if outputTopic == "" {
+			ch.acknowledge(ctx, sd, command, commandStatusFailed, errors.New("process: encoded command has no outputTopic"))
+			return
+		}
+		if err := ch.publish(ctx, outputTopic, message.Data); err != nil {
+			ch.acknowledge(ctx, sd, command, commandStatusFailed, fmt.Errorf("process: failed to publish command: %w", err))
+			return
+		}
+		logrus.
+			WithField("device", sd.config.Name).
+			WithField("topic", outputTopic).
+			WithField("payload", string(message.Data)).
+			Info("process: command sent")
+	}
+
+	pc := &pendingCommand{
+		device:  sd,
+		command: command,
+	}
+	ch.pendingLock.Lock()
+	defer ch.pendingLock.Unlock()
+	if ch.closed {
+		return
+	}
+	pc.timer = time.AfterFunc(ch.config.GetAckTimeout(), func() {
+		ch.timeout(pc)
+	})
+	ch.pending = append(ch.pending, pc)
+}
+
+// observe acknowledges the pending commands of the device which are reflected by the decoded event.
+func (ch *commandHandler) observe(sd *sensorDevice, event *device.Data) {
+	ch.pendingLock.Lock()
+	var acknowledged []*pendingCommand
+	ch.pending = slices.DeleteFunc(ch.pending, func(pc *pendingCommand) bool {
+		if pc.device != sd || !sd.device.(device.Commander).Acknowledged(pc.command, event) {
+			return false
+		}
+		pc.timer.Stop()
+		acknowledged = append(acknowledged, pc)
+		return true
+	})
+	ch.pendingLock.Unlock()
+
+	for _, pc := range acknowledged {
+		ch.submit(func(ctx context.Context) {
+			ch.acknowledge(ctx, sd, pc.command, commandStatusAcknowledged, nil)
+		})
+	}
+}
+
+// timeout acknowledges pc with the timeout status unless it was acknowledged meanwhile. The timer callbacks which
+// already started when the handler is closed find it closed and return without touching the handler.
+func (ch *commandHandler) timeout(pc *pendingCommand) {
+	ch.pendingLock.Lock()
+	defer ch.pendingLock.Unlock()
+	index := slices.Index(ch.pending, pc)
+	if ch.closed || index == -1 {
+		return
+	}
+	ch.pending = slices.Delete(ch.pending, index, index+1)
+	ch.submitLocked(func(ctx context.Context) {
+		ch.acknowledge(ctx, pc.device, pc.command, commandStatusTimeout, nil)
+	})
+}
+
+func (ch *commandHandler) acknowledge(ctx context.Context, sd *sensorDevice, command device.Command, status string, err error) {
+	commandsCounter.WithLabelValues(ch.sensorName, sd.config.Name, status).Inc()
+
+	ack := commandAck{
+		Status:  status,
+		Command: command,
+	}
+	entry := logrus.WithField("device", sd.config.Name).WithField("status", status)
+	if err != nil {
+		ack.Error = err.Error()
+		entry = entry.WithError(err)
+	}
+	entry.Info("process: command completed")
+
+	// the handler is closing, the ack would not reach the broker anyway
+	if ctx.Err() != nil {
+		return
+	}
+
+	payload, marshalErr := json.Marshal(ack)
+	if marshalErr != nil {
+		logrus.WithError(marshalErr).Error("process: failed to marshal command ack")
+		return
+	}
+	if publishErr := ch.publish(ctx, ch.ackTopic(sd), payload); publishErr != nil {
+		logrus.WithError(publishErr).Error("process: failed to publish command ack")
+	}
+}
+
+// Close stops the ack timeouts and waits for the worker, nothing is published once Close returns.
+func (ch *commandHandler) Close() error {
+	ch.pendingLock.Lock()
+	if !ch.closed {
+		close(ch.work)
+	}
+	ch.closed = true
+	for _, pc := range ch.pending {
+		pc.timer.Stop()
+	}
+	ch.pending = nil
+	ch.pendingLock.Unlock()
+
+	if ch.cancel != nil {
+		ch.cancel()
+	}
+	ch.worker.Wait()
+
+	if ch.connectionManager == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return ch.connectionManager.Disconnect(ctx)
+}
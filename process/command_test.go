@@ -0,0 +1,207 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+)
+
+// fakeCommander encodes a command into its temperature and acknowledges it once an event reports that temperature.
+type fakeCommander struct{}
+
+func (fc *fakeCommander) Decode(_ context.Context, data *device.Data) ([]*device.Data, error) {
+	return []*device.Data{data}, nil
+}
+
+func (fc *fakeCommander) Encode(_ context.Context, command device.Command) (device.Command, []*device.Data, error) {
+	temperature, ok := command["temperature"].(float64)
+	if !ok {
+		return nil, nil, errors.New("temperature is required")
+	}
+	return command, []*device.Data{
+		{
+			Data:       []byte{byte(temperature)},
+			Properties: map[string]interface{}{"outputTopic": "bridge/write"},
+		},
+	}, nil
+}
+
+func (fc *fakeCommander) Acknowledged(command device.Command, event *device.Data) bool {
+	temperature, _ := command["temperature"].(float64)
+	return len(event.Data) == 1 && event.Data[0] == byte(temperature)
+}
+
+type publishedMessage struct {
+	topic   string
+	payload string
+}
+
+func TestCommandHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		topic      string
+		payload    string
+		publishErr error
+		events     [][]byte
+		expected   []publishedMessage
+	}{
+		{
+			name:    "acknowledged by the device state",
+			topic:   "yasp/commands/heat-pump/set",
+			payload: `{"temperature":22}`,
+			events:  [][]byte{{21}, {22}, {22}},
+			expected: []publishedMessage{
+				{topic: "bridge/write", payload: "\x16"},
+				{topic: "yasp/commands/heat-pump/ack", payload: `{"status":"acknowledged","command":{"temperature":22}}`},
+			},
+		},
+		{
+			name:    "device state not reflecting the command",
+			topic:   "yasp/commands/heat-pump/set",
+			payload: `{"temperature":24}`,
+			events:  [][]byte{{21}, {22}},
+			expected: []publishedMessage{
+				{topic: "bridge/write", payload: "\x18"},
+				{topic: "yasp/commands/heat-pump/ack", payload: `{"status":"timeout","command":{"temperature":24}}`},
+			},
+		},
+		{
+			name:    "encode failure",
+			topic:   "yasp/commands/heat-pump/set",
+			payload: `{"mode":"cooling"}`,
+			expected: []publishedMessage{
+				{topic: "yasp/commands/heat-pump/ack", payload: `{"status":"failed","command":{"mode":"cooling"},"error":"temperature is required"}`},
+			},
+		},
+		{
+			name:    "invalid command",
+			topic:   "yasp/commands/heat-pump/set",
+			payload: `{"temperature":`,
+			expected: []publishedMessage{
+				{topic: "yasp/commands/heat-pump/ack", payload: `{"status":"failed","command":null,"error":"process: invalid command: unexpected end of JSON input"}`},
+			},
+		},
+		{
+			name:       "publish failure",
+			topic:      "yasp/commands/heat-pump/set",
+			payload:    `{"temperature":22}`,
+			publishErr: errors.New("broker unavailable"),
+			expected: []publishedMessage{
+				{topic: "bridge/write", payload: "\x16"},
+				{topic: "yasp/commands/heat-pump/ack", payload: `{"status":"failed","command":{"temperature":22},"error":"process: failed to publish command: broker unavailable"}`},
+			},
+		},
+		{
+			name:    "command of another device",
+			topic:   "yasp/commands/other-device/set",
+			payload: `{"temperature":22}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd := &sensorDevice{
+				config: &config.Device{Name: "heat-pump"},
+				device: &fakeCommander{},
+			}
+
+			var published []publishedMessage
+			var publishedLock sync.Mutex
+			ch := &commandHandler{
+				config:     &config.SensorCommands{Topic: "yasp/commands", AckTimeout: 50 * time.Millisecond},
+				sensorName: "sensor",
+				devices:    []*sensorDevice{sd},
+				work:       make(chan func(ctx context.Context), commandQueueSize),
+				publish: func(_ context.Context, topic string, payload []byte) error {
+					publishedLock.Lock()
+					defer publishedLock.Unlock()
+					published = append(published, publishedMessage{topic: topic, payload: string(payload)})
+					if topic == "bridge/write" {
+						return tt.publishErr
+					}
+					return nil
+				},
+			}
+			ch.start(context.Background())
+			defer ch.Close()
+			messages := func() []publishedMessage {
+				publishedLock.Lock()
+				defer publishedLock.Unlock()
+				return append([]publishedMessage(nil), published...)
+			}
+
+			// the worker runs the commands in the order they were received
+			ch.submit(func(ctx context.Context) {
+				ch.receive(ctx, tt.topic, []byte(tt.payload))
+			})
+			ch.submit(func(ctx context.Context) {
+				for _, event := range tt.events {
+					ch.observe(sd, &device.Data{Data: event})
+				}
+			})
+
+			if len(tt.expected) == 0 {
+				time.Sleep(100 * time.Millisecond)
+				assert.Empty(t, messages())
+				return
+			}
+			require.Eventually(t, func() bool {
+				return len(messages()) >= len(tt.expected)
+			}, time.Second, 10*time.Millisecond)
+			assert.Equal(t, tt.expected, messages())
+		})
+	}
+}
+
+func TestCommandHandlerClose(t *testing.T) {
+	sd := &sensorDevice{
+		config: &config.Device{Name: "heat-pump"},
+		device: &fakeCommander{},
+	}
+
+	var published []publishedMessage
+	var publishedLock sync.Mutex
+	ch := &commandHandler{
+		config:     &config.SensorCommands{Topic: "yasp/commands", AckTimeout: 10 * time.Millisecond},
+		sensorName: "sensor",
+		devices:    []*sensorDevice{sd},
+		work:       make(chan func(ctx context.Context), commandQueueSize),
+		publish: func(ctx context.Context, topic string, payload []byte) error {
+			publishedLock.Lock()
+			published = append(published, publishedMessage{topic: topic, payload: string(payload)})
+			publishedLock.Unlock()
+			// an unreachable broker holds the publish until the handler is closed
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	ch.start(context.Background())
+
+	ch.submit(func(ctx context.Context) {
+		ch.receive(ctx, "yasp/commands/heat-pump/set", []byte(`{"temperature":22}`))
+	})
+	require.Eventually(t, func() bool {
+		publishedLock.Lock()
+		defer publishedLock.Unlock()
+		return len(published) == 1
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, ch.Close())
+	ch.submit(func(ctx context.Context) {
+		ch.receive(ctx, "yasp/commands/heat-pump/set", []byte(`{"temperature":23}`))
+	})
+
+	// neither the pending ack timeout nor the commands submitted after closing publish anything
+	time.Sleep(50 * time.Millisecond)
+	publishedLock.Lock()
+	defer publishedLock.Unlock()
+	assert.Equal(t, []publishedMessage{{topic: "bridge/write", payload: "\x16"}}, published)
+	assert.Empty(t, ch.pending)
+}
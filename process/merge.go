@@ -0,0 +1,171 @@
+package process
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/nikiforov-soft/yasp/device"
+	"github.com/nikiforov-soft/yasp/input"
+)
+
+var (
+	mergedAdvertisementsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "merged_advertisements",
+		Help:      "The amount of advertisement copies merged into a copy received by another gateway.",
+		Namespace: "yasp",
+		Subsystem: "process",
+	}, []string{"sensor"})
+)
+
+// pendingAdvertisement collects the copies of an advertisement received by every gateway until its deadline.
+type pendingAdvertisement struct {
+	key         string
+	deadline    time.Time
+	best        *input.Data
+	bestRssi    int
+	hasRssi     bool
+	gateways    map[string]bool
+	gatewayRssi map[string]int
+}
+
+func newPendingAdvertisement(key string, deadline time.Time, data *input.Data) *pendingAdvertisement {
+	pa := &pendingAdvertisement{
+		key:         key,
+		deadline:    deadline,
+		best:        data,
+		gateways:    make(map[string]bool),
+		gatewayRssi: make(map[string]int),
+	}
+	pa.add(data)
+	return pa
+}
+
+// add records the gateway and rssi of the copy, the copy with the strongest rssi becomes the merged advertisement.
+func (pa *pendingAdvertisement) add(data *input.Data) {
+	gateway, _ := data.Properties[input.GatewayPropertyKey].(string)
+	pa.gateways[gateway] = true
+
+	rssiValue, ok := rssi(data.Properties)
+	if !ok {
+		return
+	}
+	if previous, exists := pa.gatewayRssi[gateway]; !exists || rssiValue > previous {
+		pa.gatewayRssi[gateway] = rssiValue
+	}
+	if !pa.hasRssi || rssiValue > pa.bestRssi {
+		pa.best = data
+		pa.bestRssi = rssiValue
+		pa.hasRssi = true
+	}
+}
+
+func (pa *pendingAdvertisement) merged() *input.Data {
+	properties := make(map[string]interface{}, len(pa.best.Properties)+3)
+	for k, v := range pa.best.Properties {
+		properties[k] = v
+	}
+	if pa.hasRssi {
+		properties[input.RssiPropertyKey] = pa.bestRssi
+	}
+	properties[input.GatewayCountPropertyKey] = len(pa.gateways)
+	properties[input.GatewayRssiPropertyKey] = pa.gatewayRssi
+	return &input.Data{
+		Data:       pa.best.Data,
+		Properties: properties,
+	}
+}
+
+// mergeAdvertisements holds every advertisement for window to merge the copies received by other gateways into the
+// copy with the strongest rssi, messages without a mac address are forwarded right away.
+func mergeAdvertisements(ctx context.Context, sensorName string, window time.Duration, events <-chan *input.Data) <-chan *input.Data {
+	merged := make(chan *input.Data)
+	go func() {
+		defer close(merged)
+
+		send := func(data *input.Data) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case merged <- data:
+				return true
+			}
+		}
+
+		pending := make(map[string]*pendingAdvertisement)
+		var queue []*pendingAdvertisement
+		timer := time.NewTimer(window)
+		defer timer.Stop()
+		for {
+			var timerChan <-chan time.Time
+			if len(queue) != 0 {
+				timer.Reset(time.Until(queue[0].deadline))
+				timerChan = timer.C
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case data, ok := <-events:
+				if !ok {
+					for _, pa := range queue {
+						if !send(pa.merged()) {
+							return
+						}
+					}
+					return
+				}
+
+				key, ok := advertisementKey(data)
+				if !ok {
+					if !send(data) {
+						return
+					}
+					continue
+				}
+				if pa, exists := pending[key]; exists {
+					pa.add(data)
+					mergedAdvertisementsCounter.WithLabelValues(sensorName).Inc()
+					continue
+				}
+				pa := newPendingAdvertisement(key, time.Now().Add(window), data)
+				pending[key] = pa
+				queue = append(queue, pa)
+			case now := <-timerChan:
+				for len(queue) != 0 && !queue[0].deadline.After(now) {
+					pa := queue[0]
+					queue = queue[1:]
+					delete(pending, pa.key)
+					if !send(pa.merged()) {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return merged
+}
+
+// advertisementKey identifies the copies of an advertisement by the mac address of the advertiser and the payload.
+func advertisementKey(data *input.Data) (string, bool) {
+	macAddress, ok := device.MacAddress(data.Properties)
+	if !ok {
+		return "", false
+	}
+	return macAddress + "/" + string(data.Data), true
+}
+
+func rssi(properties map[string]interface{}) (int, bool) {
+	switch value := properties[input.RssiPropertyKey].(type) {
+	case int:
+		return value, true
+	case int64:
+		return int(value), true
+	case float64:
+		return int(value), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,64 @@
+package process
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikiforov-soft/yasp/input"
+)
+
+func TestMergeAdvertisements(t *testing.T) {
+	events := make(chan *input.Data, 5)
+	events <- advertisement("a4:c1:38:00:00:01", "payload", "kitchen", -80)
+	events <- advertisement("a4:c1:38:00:00:01", "payload", "living-room", -60)
+	events <- advertisement("A4-C1-38-00-00-01", "payload", "kitchen", -75)
+	events <- advertisement("a4:c1:38:00:00:01", "other payload", "garage", -90)
+	events <- &input.Data{Data: []byte("no mac address"), Properties: map[string]interface{}{}}
+	close(events)
+
+	var merged []*input.Data
+	for data := range mergeAdvertisements(context.Background(), "sensor", time.Hour, events) {
+		merged = append(merged, data)
+	}
+
+	assert.Len(t, merged, 3)
+	assert.Equal(t, "no mac address", string(merged[0].Data))
+
+	assert.Equal(t, "payload", string(merged[1].Data))
+	assert.Equal(t, "living-room", merged[1].Properties[input.GatewayPropertyKey])
+	assert.Equal(t, -60, merged[1].Properties[input.RssiPropertyKey])
+	assert.Equal(t, 2, merged[1].Properties[input.GatewayCountPropertyKey])
+	assert.Equal(t, map[string]int{"kitchen": -75, "living-room": -60}, merged[1].Properties[input.GatewayRssiPropertyKey])
+
+	assert.Equal(t, "other payload", string(merged[2].Data))
+	assert.Equal(t, "garage", merged[2].Properties[input.GatewayPropertyKey])
+	assert.Equal(t, 1, merged[2].Properties[input.GatewayCountPropertyKey])
+}
+
+func TestMergeAdvertisementsWindow(t *testing.T) {
+	events := make(chan *input.Data)
+	merged := mergeAdvertisements(context.Background(), "sensor", 10*time.Millisecond, events)
+
+	events <- advertisement("a4:c1:38:00:00:01", "payload", "kitchen", -80)
+	first := <-merged
+	assert.Equal(t, 1, first.Properties[input.GatewayCountPropertyKey])
+
+	events <- advertisement("a4:c1:38:00:00:01", "payload", "kitchen", -70)
+	second := <-merged
+	assert.Equal(t, -70, second.Properties[input.RssiPropertyKey])
+	close(events)
+}
+
+func advertisement(macAddress, payload, gateway string, rssi int) *input.Data {
+	return &input.Data{
+		Data: []byte(payload),
+		Properties: map[string]interface{}{
+			input.MacAddressPropertyKey: macAddress,
+			input.GatewayPropertyKey:    gateway,
+			input.RssiPropertyKey:       rssi,
+		},
+	}
+}
@@ -1,11 +1,390 @@
 package process
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/internal/spool"
 	"github.com/nikiforov-soft/yasp/output"
 	outputtransform "github.com/nikiforov-soft/yasp/output/transform"
 )
 
+var (
+	outputQueueDepthGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "output_queue_depth",
+		Help:      "The amount of events waiting in the output queue.",
+		Namespace: "yasp",
+		Subsystem: "process",
+	}, []string{"sensor", "output"})
+	outputQueueDroppedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "output_queue_dropped",
+		Help:      "The amount of events dropped because the output queue was full.",
+		Namespace: "yasp",
+		Subsystem: "process",
+	}, []string{"sensor", "output", "policy"})
+	outputRetriesCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "output_retries",
+		Help:      "The amount of retried output publishes.",
+		Namespace: "yasp",
+		Subsystem: "process",
+	}, []string{"sensor", "output"})
+)
+
+// outputMetricsKey identifies the metric series of an output of a sensor.
+type outputMetricsKey struct {
+	sensorName string
+	outputKey  string
+}
+
+var (
+	// outputMetricsRefs counts the output groups sharing the metric series of an output, a replaced output group is
+	// closed after its replacement was created so the series are deleted once the last of them is closed.
+	outputMetricsRefs     = make(map[outputMetricsKey]int)
+	outputMetricsRefsLock sync.Mutex
+)
+
+func acquireOutputMetrics(sensorName, outputKey string) {
+	outputMetricsRefsLock.Lock()
+	defer outputMetricsRefsLock.Unlock()
+	outputMetricsRefs[outputMetricsKey{sensorName: sensorName, outputKey: outputKey}]++
+}
+
+// releaseOutputMetrics deletes the metric series of the output once no output group uses them anymore.
+func releaseOutputMetrics(sensorName, outputKey string) {
+	outputMetricsRefsLock.Lock()
+	defer outputMetricsRefsLock.Unlock()
+
+	key := outputMetricsKey{sensorName: sensorName, outputKey: outputKey}
+	outputMetricsRefs[key]--
+	if outputMetricsRefs[key] > 0 {
+		return
+	}
+	delete(outputMetricsRefs, key)
+
+	labels := prometheus.Labels{"sensor": sensorName, "output": outputKey}
+	for _, metricVec := range []*prometheus.MetricVec{
+		outputQueueDepthGauge.MetricVec,
+		outputQueueDroppedCounter.MetricVec,
+		outputRetriesCounter.MetricVec,
+		outputSpoolSizeGauge.MetricVec,
+		outputSpooledCounter.MetricVec,
+		outputSpoolReplayedCounter.MetricVec,
+		outputSpoolDroppedCounter.MetricVec,
+	} {
+		metricVec.DeletePartialMatch(labels)
+	}
+}
+
+// outputGroup publishes the events of a sensor to a single output, events are transformed in order on the sensor
+// goroutine, then queued and published by the output workers so a slow output does not hold back the sensor or the
+// other outputs. More than one worker publishes concurrently and does not preserve the order of the events.
 type outputGroup struct {
+	config           *config.Output
+	sensorName       string
+	outputName       string
+	outputKey        string
+	clientId         string
+	overflowPolicy   string
 	Output           output.Output
 	OutputTransforms []outputtransform.Transform
+	queue            chan *output.Data
+	queueLock        sync.RWMutex
+	closed           bool
+	workers          sync.WaitGroup
+	spool            *spool.Spool
+	stopReplay       chan struct{}
+	replayDone       chan struct{}
+	aggregator       *aggregator
+	stopAggregate    chan struct{}
+	aggregateDone    chan struct{}
+}
+
+// newOutputGroup creates the output group of the outputIndex-th output entry of the sensor, the entry index and the output
+// name form the key identifying the output group within the sensor.
+func newOutputGroup(sensorName, outputName string, outputIndex int, outputConfig *config.Output) (*outputGroup, error) {
+	overflowPolicy := outputConfig.Queue.GetOverflowPolicy()
+	switch overflowPolicy {
+	case config.OverflowPolicyBlock, config.OverflowPolicyDropOldest, config.OverflowPolicyDropNewest:
+	default:
+		return nil, fmt.Errorf("process: unsupported output queue overflow policy: %s", overflowPolicy)
+	}
+
+	og := &outputGroup{
+		config:         outputConfig,
+		sensorName:     sensorName,
+		outputName:     outputName,
+		outputKey:      outputKey(outputName, outputIndex),
+		overflowPolicy: overflowPolicy,
+		queue:          make(chan *output.Data, outputConfig.Queue.GetSize()),
+	}
+
+	if aggregateConfig := outputConfig.Aggregate; aggregateConfig != nil && aggregateConfig.Enabled {
+		var err error
+		og.aggregator, err = newAggregator(aggregateConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if spoolConfig := outputConfig.Spool; spoolConfig != nil && spoolConfig.Enabled {
+		if spoolConfig.Directory == "" {
+			return nil, errors.New("process: output spool directory is required")
+		}
+		var err error
+		// every output spools into its own directory, a shared spool would be replayed through the wrong output
+		og.spool, err = spool.Open(filepath.Join(spoolConfig.Directory, spoolDirectoryName(sensorName), og.outputKey), spool.Options{
+			MaxSize: spoolConfig.MaxSize,
+			MaxAge:  spoolConfig.MaxAge,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("process: failed to open output spool: %w", err)
+		}
+		outputSpoolSizeGauge.WithLabelValues(sensorName, og.outputKey).Set(float64(og.spool.Size()))
+	}
+	acquireOutputMetrics(sensorName, og.outputKey)
+	return og, nil
+}
+
+func outputKey(outputName string, outputIndex int) string {
+	return fmt.Sprintf("%s-%d", outputName, outputIndex)
+}
+
+// start launches the output workers, they run until the output group is closed.
+func (og *outputGroup) start(ctx context.Context) {
+	for range og.config.Queue.GetWorkers() {
+		og.workers.Add(1)
+		go func() {
+			defer og.workers.Done()
+			for outputData := range og.queue {
+				outputQueueDepthGauge.WithLabelValues(og.sensorName, og.outputKey).Dec()
+				og.deliver(ctx, outputData)
+			}
+		}()
+	}
+
+	if og.spool != nil {
+		og.stopReplay = make(chan struct{})
+		og.replayDone = make(chan struct{})
+		go og.replay(ctx)
+	}
+
+	if og.aggregator != nil {
+		og.stopAggregate = make(chan struct{})
+		og.aggregateDone = make(chan struct{})
+		go og.aggregate(ctx)
+	}
+}
+
+// aggregate publishes the aggregated events every window until the output group is closed.
+func (og *outputGroup) aggregate(ctx context.Context) {
+	defer close(og.aggregateDone)
+	ticker := time.NewTicker(og.config.Aggregate.GetWindow())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-og.stopAggregate:
+			return
+		case <-ticker.C:
+			og.flushAggregates(ctx)
+		}
+	}
+}
+
+// flushAggregates queues the aggregated windows behind the pending events, they skip the transforms which already
+// ran on the aggregated events.
+func (og *outputGroup) flushAggregates(ctx context.Context) {
+	og.queueLock.RLock()
+	defer og.queueLock.RUnlock()
+	if og.closed {
+		return
+	}
+	for _, outputData := range og.aggregator.flush() {
+		og.push(ctx, outputData)
+	}
+}
+
+// enqueue transforms outputData and queues the result for publishing.
+func (og *outputGroup) enqueue(ctx context.Context, outputData *output.Data) {
+	og.queueLock.RLock()
+	defer og.queueLock.RUnlock()
+	if og.closed {
+		return
+	}
+
+	// stateful transforms and the aggregation depend on the event order, so they run before the events are handed
+	// to the workers
+	outputData = og.transform(ctx, outputData)
+	if outputData == nil {
+		return
+	}
+	og.push(ctx, outputData)
+}
+
+// push queues outputData applying the overflow policy when the queue is full, the queue lock has to be held.
+func (og *outputGroup) push(ctx context.Context, outputData *output.Data) {
+	depthGauge := outputQueueDepthGauge.WithLabelValues(og.sensorName, og.outputKey)
+	depthGauge.Inc()
+	switch og.overflowPolicy {
+	case config.OverflowPolicyDropNewest:
+		select {
+		case og.queue <- outputData:
+		default:
+			depthGauge.Dec()
+			og.dropped()
+		}
+	case config.OverflowPolicyDropOldest:
+		for {
+			select {
+			case og.queue <- outputData:
+				return
+			default:
+			}
+
+			select {
+			case <-og.queue:
+				depthGauge.Dec()
+				og.dropped()
+			default:
+			}
+		}
+	default:
+		select {
+		case og.queue <- outputData:
+		case <-ctx.Done():
+			depthGauge.Dec()
+		}
+	}
+}
+
+func (og *outputGroup) dropped() {
+	outputQueueDroppedCounter.WithLabelValues(og.sensorName, og.outputKey, og.overflowPolicy).Inc()
+	logrus.
+		WithField("sensor", og.sensorName).
+		WithField("output", og.outputKey).
+		Debug("process: output queue full, dropping event")
+}
+
+// transform applies the output transforms and the aggregation to outputData, nil is returned when the event was
+// filtered or aggregated.
+func (og *outputGroup) transform(ctx context.Context, outputData *output.Data) *output.Data {
+	for _, transform := range og.OutputTransforms {
+		transformData, err := transform.Transform(ctx, outputData)
+		if err != nil {
+			logrus.WithError(err).Error("process: failed to transform output data")
+			continue
+		}
+		if transformData == nil {
+			return nil
+		}
+
+		// the transformed properties replace the previous ones, so transforms are able to rename and delete properties
+		outputData = transformData
+	}
+
+	if og.aggregator != nil {
+		aggregated, err := og.aggregator.add(outputData)
+		if err != nil {
+			logrus.WithError(err).Error("process: failed to aggregate output data")
+			return nil
+		}
+		if aggregated {
+			return nil
+		}
+	}
+	return outputData
+}
+
+// deliver publishes outputData, or spools it when the output is failing.
+func (og *outputGroup) deliver(ctx context.Context, outputData *output.Data) {
+	if og.spool != nil && og.spool.Pending() {
+		// keeps the spooled events in order, they are published by the replay once the output is healthy again.
+		og.spoolData(outputData)
+		return
+	}
+
+	if err := og.publishWithRetry(ctx, outputData); err != nil {
+		logrus.WithError(err).Error("process: failed to publish output data")
+		if og.spool != nil {
+			og.spoolData(outputData)
+		}
+	}
+}
+
+// publishWithRetry publishes outputData retrying with an exponential backoff up to the configured max attempts.
+func (og *outputGroup) publishWithRetry(ctx context.Context, outputData *output.Data) error {
+	retry := og.config.Retry
+	backoff := retry.GetInitialBackoff()
+	for attempt := 1; ; attempt++ {
+		err := og.Output.Publish(ctx, outputData)
+		if err == nil || attempt >= retry.GetMaxAttempts() {
+			return err
+		}
+
+		logrus.
+			WithError(err).
+			WithField("sensor", og.sensorName).
+			WithField("output", og.outputKey).
+			WithField("attempt", attempt).
+			Warn("process: failed to publish output data, retrying")
+		outputRetriesCounter.WithLabelValues(og.sensorName, og.outputKey).Inc()
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, retry.GetMaxBackoff())
+	}
+}
+
+// Close stops accepting events, queues the partially aggregated windows, waits for the workers to publish the queued
+// events and closes the output. The metric series of the output are deleted unless a replacement output group still
+// uses them.
+func (og *outputGroup) Close() error {
+	if og.stopAggregate != nil {
+		close(og.stopAggregate)
+		<-og.aggregateDone
+		og.stopAggregate = nil
+	}
+
+	og.queueLock.Lock()
+	closing := !og.closed
+	if closing {
+		if og.aggregator != nil {
+			for _, outputData := range og.aggregator.flush() {
+				og.push(context.Background(), outputData)
+			}
+		}
+		og.closed = true
+		close(og.queue)
+	}
+	og.queueLock.Unlock()
+	og.workers.Wait()
+
+	var errs []error
+	if og.spool != nil {
+		if og.stopReplay != nil {
+			close(og.stopReplay)
+			<-og.replayDone
+			og.stopReplay = nil
+		}
+		errs = append(errs, og.spool.Close())
+		og.spool = nil
+	}
+	if og.Output != nil {
+		errs = append(errs, og.Output.Close(context.Background()))
+	}
+	if closing {
+		releaseOutputMetrics(og.sensorName, og.outputKey)
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,79 @@
+package process
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+// sequenceTransform records the order of the transformed events.
+type sequenceTransform struct {
+	transformed []string
+	lock        sync.Mutex
+}
+
+func (st *sequenceTransform) Transform(_ context.Context, data *output.Data) (*output.Data, error) {
+	st.lock.Lock()
+	defer st.lock.Unlock()
+	st.transformed = append(st.transformed, string(data.Data))
+	return data, nil
+}
+
+func TestOutputGroupTransformOrder(t *testing.T) {
+	og, err := newOutputGroup("sensor", "mqtt", 0, &config.Output{
+		Queue: &config.OutputQueue{Workers: 8},
+	})
+	require.NoError(t, err)
+	st := &sequenceTransform{}
+	ro := &recordingOutput{}
+	og.Output = ro
+	og.OutputTransforms = append(og.OutputTransforms, st)
+	og.start(context.Background())
+
+	var expected []string
+	for i := range 100 {
+		expected = append(expected, strconv.Itoa(i))
+		og.enqueue(context.Background(), &output.Data{Data: []byte(strconv.Itoa(i))})
+	}
+	require.NoError(t, og.Close())
+
+	// the transforms see the events in order even though the workers publish them concurrently
+	assert.Equal(t, expected, st.transformed)
+	assert.Len(t, ro.published, len(expected))
+}
+
+func TestOutputGroupMetrics(t *testing.T) {
+	series := testutil.CollectAndCount(outputQueueDepthGauge)
+	newGroup := func(outputIndex int) *outputGroup {
+		og, err := newOutputGroup("metrics", "mqtt", outputIndex, &config.Output{})
+		require.NoError(t, err)
+		og.Output = &recordingOutput{}
+		return og
+	}
+
+	// two outputs of the same type queue into their own series, no workers are started so the events stay queued
+	first := newGroup(0)
+	second := newGroup(1)
+	first.enqueue(context.Background(), &output.Data{})
+	second.enqueue(context.Background(), &output.Data{})
+	second.enqueue(context.Background(), &output.Data{})
+	assert.Equal(t, 1.0, testutil.ToFloat64(outputQueueDepthGauge.WithLabelValues("metrics", "mqtt-0")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(outputQueueDepthGauge.WithLabelValues("metrics", "mqtt-1")))
+
+	// the series of a replaced output group are kept for its replacement
+	replacement := newGroup(0)
+	require.NoError(t, first.Close())
+	assert.Equal(t, series+2, testutil.CollectAndCount(outputQueueDepthGauge))
+
+	require.NoError(t, replacement.Close())
+	require.NoError(t, second.Close())
+	assert.Equal(t, series, testutil.CollectAndCount(outputQueueDepthGauge))
+}
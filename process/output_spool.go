@@ -0,0 +1,128 @@
+package process
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+var (
+	outputSpoolSizeGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name:      "output_spool_size_bytes",
+		Help:      "The size of the output spool in bytes.",
+		Namespace: "yasp",
+		Subsystem: "process",
+	}, []string{"sensor", "output"})
+	outputSpooledCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "output_spooled",
+		Help:      "The amount of events written to the output spool.",
+		Namespace: "yasp",
+		Subsystem: "process",
+	}, []string{"sensor", "output"})
+	outputSpoolReplayedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "output_spool_replayed",
+		Help:      "The amount of spooled events published by the replay.",
+		Namespace: "yasp",
+		Subsystem: "process",
+	}, []string{"sensor", "output"})
+	outputSpoolDroppedCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:      "output_spool_dropped",
+		Help:      "The amount of spooled events dropped because of the spool size or age limits.",
+		Namespace: "yasp",
+		Subsystem: "process",
+	}, []string{"sensor", "output", "reason"})
+)
+
+// spoolDirectoryName turns the sensor name into a single path element.
+func spoolDirectoryName(sensorName string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, sensorName)
+}
+
+// spooledData is the on-disk representation of output.Data, properties are stored as json so values which are
+// not json values themselves come back in their json form.
+type spooledData struct {
+	Data       []byte                 `json:"data"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// spoolData writes outputData to the spool so it is published by the replay.
+func (og *outputGroup) spoolData(outputData *output.Data) {
+	record, err := json.Marshal(spooledData{
+		Data:       outputData.Data,
+		Properties: outputData.Properties,
+	})
+	if err != nil {
+		logrus.WithError(err).Error("process: failed to marshal output data for the spool, dropping it")
+		return
+	}
+
+	dropped, err := og.spool.Append(record)
+	if dropped != 0 {
+		outputSpoolDroppedCounter.WithLabelValues(og.sensorName, og.outputKey, "size").Add(float64(dropped))
+	}
+	outputSpoolSizeGauge.WithLabelValues(og.sensorName, og.outputKey).Set(float64(og.spool.Size()))
+	if err != nil {
+		logrus.WithError(err).Error("process: failed to spool output data, dropping it")
+		return
+	}
+	outputSpooledCounter.WithLabelValues(og.sensorName, og.outputKey).Inc()
+}
+
+// replay periodically publishes the spooled events in order, it stops at the first failure and tries again on the next tick.
+func (og *outputGroup) replay(ctx context.Context) {
+	defer close(og.replayDone)
+
+	ticker := time.NewTicker(og.config.Spool.GetReplayInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-og.stopReplay:
+			return
+		case <-ticker.C:
+		}
+
+		if !og.spool.Pending() {
+			continue
+		}
+
+		expired, err := og.spool.Replay(func(record []byte) error {
+			var data spooledData
+			if err := json.Unmarshal(record, &data); err != nil {
+				logrus.WithError(err).Error("process: failed to unmarshal spooled output data, dropping it")
+				return nil
+			}
+			if err := og.Output.Publish(ctx, &output.Data{Data: data.Data, Properties: data.Properties}); err != nil {
+				return err
+			}
+			outputSpoolReplayedCounter.WithLabelValues(og.sensorName, og.outputKey).Inc()
+			return nil
+		})
+		if expired != 0 {
+			outputSpoolDroppedCounter.WithLabelValues(og.sensorName, og.outputKey, "age").Add(float64(expired))
+		}
+		outputSpoolSizeGauge.WithLabelValues(og.sensorName, og.outputKey).Set(float64(og.spool.Size()))
+		if err != nil {
+			logrus.
+				WithError(err).
+				WithField("sensor", og.sensorName).
+				WithField("output", og.outputKey).
+				Warn("process: failed to replay spooled output data")
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+// togglingOutput fails every publish while failing is set and records the published events otherwise.
+type togglingOutput struct {
+	failing   bool
+	published []string
+	lock      sync.Mutex
+}
+
+func (to *togglingOutput) Publish(_ context.Context, data *output.Data) error {
+	to.lock.Lock()
+	defer to.lock.Unlock()
+	if to.failing {
+		return errors.New("output unavailable")
+	}
+	to.published = append(to.published, string(data.Data))
+	return nil
+}
+
+func (to *togglingOutput) Close(_ context.Context) error {
+	return nil
+}
+
+func (to *togglingOutput) setFailing(failing bool) {
+	to.lock.Lock()
+	defer to.lock.Unlock()
+	to.failing = failing
+}
+
+func (to *togglingOutput) events() []string {
+	to.lock.Lock()
+	defer to.lock.Unlock()
+	return append([]string(nil), to.published...)
+}
+
+func TestOutputGroupSpoolPerOutput(t *testing.T) {
+	directory := t.TempDir()
+	newSpooledOutputGroup := func(sensorName string, outputIndex int) (*outputGroup, *togglingOutput) {
+		og, err := newOutputGroup(sensorName, "mqtt", outputIndex, &config.Output{
+			Spool: &config.OutputSpool{
+				Enabled:        true,
+				Directory:      directory,
+				ReplayInterval: 10 * time.Millisecond,
+			},
+		})
+		require.NoError(t, err)
+		to := &togglingOutput{}
+		og.Output = to
+		og.start(context.Background())
+		t.Cleanup(func() {
+			assert.NoError(t, og.Close())
+		})
+		return og, to
+	}
+
+	// two outputs of the same type within a sensor and one of another sensor
+	first, firstOutput := newSpooledOutputGroup("living room", 0)
+	second, secondOutput := newSpooledOutputGroup("living room", 1)
+	other, otherOutput := newSpooledOutputGroup("garage", 0)
+	assert.NotSame(t, first.spool, second.spool)
+	assert.NotSame(t, first.spool, other.spool)
+
+	firstOutput.setFailing(true)
+	first.enqueue(context.Background(), &output.Data{Data: []byte("first")})
+	require.Eventually(t, first.spool.Pending, time.Second, time.Millisecond)
+
+	// a healthy output keeps publishing while another output of the same type spools
+	second.enqueue(context.Background(), &output.Data{Data: []byte("second")})
+	other.enqueue(context.Background(), &output.Data{Data: []byte("other")})
+	require.Eventually(t, func() bool {
+		return len(secondOutput.events()) == 1 && len(otherOutput.events()) == 1
+	}, time.Second, time.Millisecond)
+	assert.False(t, second.spool.Pending())
+	assert.False(t, other.spool.Pending())
+
+	firstOutput.setFailing(false)
+	require.Eventually(t, func() bool {
+		return len(firstOutput.events()) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []string{"first"}, firstOutput.events())
+	assert.Equal(t, []string{"second"}, secondOutput.events())
+	assert.Equal(t, []string{"other"}, otherOutput.events())
+}
@@ -3,6 +3,7 @@ package process
 import (
 	"context"
 	"errors"
+	"slices"
 
 	"github.com/nikiforov-soft/yasp/config"
 	"github.com/nikiforov-soft/yasp/device"
@@ -11,22 +12,111 @@ import (
 )
 
 type sensorGroup struct {
-	config          *config.Sensor
-	input           input.Input
-	inputTransforms []inputtransform.Transform
-	outputGroups    []outputGroup
-	devices         []device.Device
+	config       *config.Sensor
+	inputs       []*sensorInput
+	outputGroups []*outputGroup
+	devices      []*sensorDevice
+	commands     *commandHandler
+	cancelFunc   context.CancelFunc
+	done         chan struct{}
+	// released is set once a component has been closed to free its client id for a replacement sensor group
+	released bool
 }
 
-func (sg *sensorGroup) Close() error {
+// sensorInput is a single subscribed input of a sensor group, its messages are tagged with name.
+type sensorInput struct {
+	config     *config.Input
+	pluginType string
+	name       string
+	clientId   string
+	input      input.Input
+	dataChan   <-chan *input.Data
+	transforms []inputtransform.Transform
+}
+
+type sensorDevice struct {
+	config *config.Device
+	device device.Device
+}
+
+// stop cancels the sensor group handler and waits for it to return, the inputs and outputs are left open.
+func (sg *sensorGroup) stop() {
+	if sg.cancelFunc == nil {
+		return
+	}
+	sg.cancelFunc()
+	<-sg.done
+	sg.cancelFunc = nil
+}
+
+// closeUnused closes every input and output of the sensor group that has not been carried over to next, the command
+// handler is always closed as it is bound to the devices of the sensor group.
+func (sg *sensorGroup) closeUnused(next *sensorGroup) error {
 	var errs []error
-	if err := sg.input.Close(context.Background()); err != nil {
-		errs = append(errs, err)
+	for _, si := range sg.inputs {
+		if next != nil && slices.Contains(next.inputs, si) {
+			continue
+		}
+		if err := si.input.Close(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
 	}
 	for _, og := range sg.outputGroups {
-		if err := og.Output.Close(context.Background()); err != nil {
+		if next != nil && slices.Contains(next.outputGroups, og) {
+			continue
+		}
+		if err := og.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if sg.commands != nil {
+		if err := sg.commands.Close(); err != nil {
 			errs = append(errs, err)
 		}
 	}
 	return errors.Join(errs...)
 }
+
+// releaseClientId closes the inputs, outputs and command handler of the sensor group which are not carried over to next
+// and connect with clientId, a broker disconnects one of two connections sharing a client id so the previous
+// connection has to be closed before the replacement connects.
+func (sg *sensorGroup) releaseClientId(next *sensorGroup, clientId string) error {
+	if sg == nil || clientId == "" {
+		return nil
+	}
+
+	var errs []error
+	sg.inputs = slices.DeleteFunc(sg.inputs, func(si *sensorInput) bool {
+		if si.clientId != clientId || slices.Contains(next.inputs, si) {
+			return false
+		}
+		if err := si.input.Close(context.Background()); err != nil {
+			errs = append(errs, err)
+		}
+		sg.released = true
+		return true
+	})
+	sg.outputGroups = slices.DeleteFunc(sg.outputGroups, func(og *outputGroup) bool {
+		if og.clientId != clientId || slices.Contains(next.outputGroups, og) {
+			return false
+		}
+		if err := og.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		sg.released = true
+		return true
+	})
+	if sg.commands != nil && sg.commands.config.ClientId == clientId {
+		if err := sg.commands.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		sg.commands = nil
+		sg.released = true
+	}
+	return errors.Join(errs...)
+}
+
+func (sg *sensorGroup) Close() error {
+	sg.stop()
+	return sg.closeUnused(nil)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"sync"
 
 	"github.com/sirupsen/logrus"
@@ -19,152 +20,381 @@ import (
 )
 
 type Service interface {
+	Reload(sensors []*config.Sensor) error
 	Close() error
 }
 
 type service struct {
-	metricsService metrics.Service
-	sensorGroups   []*sensorGroup
-	wg             sync.WaitGroup
-	cancelFunc     context.CancelFunc
+	ctx              context.Context
+	metricsService   metrics.Service
+	sensorGroups     map[string]*sensorGroup
+	sensorGroupsLock sync.Mutex
+	cancelFunc       context.CancelFunc
 }
 
 func NewService(ctx context.Context, metricsService metrics.Service, sensors []*config.Sensor) (Service, error) {
 	cancellableCtx, cancelFunc := context.WithCancel(ctx)
 	s := &service{
+		ctx:            cancellableCtx,
 		metricsService: metricsService,
+		sensorGroups:   make(map[string]*sensorGroup),
 		cancelFunc:     cancelFunc,
 	}
-	if err := s.init(cancellableCtx, sensors); err != nil {
-		return nil, err
+	if err := s.Reload(sensors); err != nil {
+		cancelFunc()
+		return nil, errors.Join(err, s.closeSensorGroups())
 	}
 	return s, nil
 }
 
-func (s *service) init(ctx context.Context, sensorsConfig []*config.Sensor) error {
-	sensorGroups := make([]*sensorGroup, 0, len(sensorsConfig))
+// Reload diffs the given sensors against the running ones and rebuilds only the sensor groups whose
+// configuration changed, reusing the inputs, outputs and devices whose own configuration is unchanged.
+func (s *service) Reload(sensorsConfig []*config.Sensor) error {
+	s.sensorGroupsLock.Lock()
+	defer s.sensorGroupsLock.Unlock()
+
+	enabledSensors := make(map[string]*config.Sensor, len(sensorsConfig))
 	for _, sensorConfig := range sensorsConfig {
 		if !sensorConfig.Enabled {
 			logrus.WithField("sensor", sensorConfig.Name).Info("skipping disabled sensor")
 			continue
 		}
+		if _, exists := enabledSensors[sensorConfig.Name]; exists {
+			return fmt.Errorf("process: duplicate sensor name: %s", sensorConfig.Name)
+		}
+		enabledSensors[sensorConfig.Name] = sensorConfig
+	}
+
+	var errs []error
+	for name, sg := range s.sensorGroups {
+		if _, exists := enabledSensors[name]; exists {
+			continue
+		}
+		logrus.WithField("sensor", name).Info("removing sensor")
+		if err := sg.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(s.sensorGroups, name)
+	}
+
+	for _, sensorConfig := range sensorsConfig {
+		if enabledSensors[sensorConfig.Name] != sensorConfig {
+			continue
+		}
+
+		previous := s.sensorGroups[sensorConfig.Name]
+		if previous != nil && reflect.DeepEqual(previous.config, sensorConfig) {
+			continue
+		}
 		logrus.WithField("sensor", sensorConfig.Name).Info("initializing sensor")
 
-		var inputImpl input.Input
-		var inputTransforms []inputtransform.Transform
-		var outputs []outputGroup
-		var devices []device.Device
-		var err error
-		if mqtt := sensorConfig.Input.Mqtt; mqtt != nil && mqtt.Enabled {
-			inputImpl, err = input.NewInput(ctx, "mqtt", sensorConfig.Input)
-			if err != nil {
-				return fmt.Errorf("process: failed to initialize input: %w", err)
-			}
-		} else if memphis := sensorConfig.Input.Memphis; memphis != nil && memphis.Enabled {
-			inputImpl, err = input.NewInput(ctx, "memphis", sensorConfig.Input)
-			if err != nil {
-				return fmt.Errorf("process: failed to initialize input: %w", err)
+		// the previous sensor group is stopped first as building the replacement may close its connections
+		if previous != nil {
+			previous.stop()
+		}
+		sg, err := s.newSensorGroup(sensorConfig, previous)
+		if err != nil {
+			errs = append(errs, err)
+			if previous != nil {
+				if err := s.restoreSensorGroup(previous); err != nil {
+					errs = append(errs, err)
+				}
 			}
+			continue
 		}
 
-		for _, transform := range sensorConfig.Input.Transforms {
-			inputTransform, err := inputtransform.NewTransform(ctx, transform)
-			if err != nil {
-				return fmt.Errorf("process: failed to initialize input transform: %s - %w", transform.Name, err)
+		if previous != nil {
+			if err := previous.closeUnused(sg); err != nil {
+				errs = append(errs, err)
 			}
-			inputTransforms = append(inputTransforms, inputTransform)
 		}
 
-		for _, o := range sensorConfig.Outputs {
-			var outputContainer outputGroup
-			if mqtt := o.Mqtt; mqtt != nil && mqtt.Enabled {
-				outputImpl, err := output.NewOutput(ctx, "mqtt", o, s.metricsService)
-				if err != nil {
-					return fmt.Errorf("process: failed to initialize mqtt output: %w", err)
-				}
-				outputContainer.Output = outputImpl
-			} else if influxdb2 := o.InfluxDb2; influxdb2 != nil && influxdb2.Enabled {
-				outputImpl, err := output.NewOutput(ctx, "influxdb2", o, s.metricsService)
-				if err != nil {
-					return fmt.Errorf("process: failed to initialize influxdb2 output: %w", err)
-				}
-				outputContainer.Output = outputImpl
-			} else if prometheus := o.Prometheus; prometheus != nil && prometheus.Enabled {
-				outputImpl, err := output.NewOutput(ctx, "prometheus", o, s.metricsService)
-				if err != nil {
-					return fmt.Errorf("process: failed to initialize prometheus output: %w", err)
+		s.startSensorGroup(sg)
+		s.sensorGroups[sensorConfig.Name] = sg
+	}
+
+	return errors.Join(errs...)
+}
+
+// newSensorGroup builds a sensor group for sensorConfig taking over the components of previous whose
+// configuration did not change, on failure every newly created component is closed again.
+func (s *service) newSensorGroup(sensorConfig *config.Sensor, previous *sensorGroup) (sg *sensorGroup, err error) {
+	sg = &sensorGroup{
+		config: sensorConfig,
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if closeErr := sg.closeUnused(previous); closeErr != nil {
+			err = errors.Join(err, closeErr)
+		}
+	}()
+
+	inputNames := make(map[string]bool)
+	for _, inputConfig := range sensorConfig.GetInputs() {
+		plugins, err := inputConfig.Plugins()
+		if err != nil {
+			return sg, fmt.Errorf("process: failed to resolve input: %w", err)
+		}
+
+		for _, plugin := range plugins {
+			name := uniqueInputName(inputNames, inputConfig, plugin, len(plugins))
+			if previous != nil {
+				index := slices.IndexFunc(previous.inputs, func(si *sensorInput) bool {
+					return reflect.DeepEqual(si.config, inputConfig) && si.pluginType == plugin.Type && si.name == name && !slices.Contains(sg.inputs, si)
+				})
+				if index != -1 {
+					sg.inputs = append(sg.inputs, previous.inputs[index])
+					continue
 				}
-				outputContainer.Output = outputImpl
 			}
 
-			if outputContainer.Output == nil {
-				continue
+			if err := previous.releaseClientId(sg, pluginClientId(plugin)); err != nil {
+				return sg, err
 			}
+			if err := s.initInput(sg, inputConfig, plugin, name); err != nil {
+				return sg, err
+			}
+		}
+	}
 
-			for _, transform := range o.Transforms {
-				outputTransform, err := outputtransform.NewTransform(ctx, transform)
-				if err != nil {
-					return fmt.Errorf("process: failed to initialize output transform: %s - %w", transform.Name, err)
+	if len(sg.inputs) == 0 {
+		return sg, errors.New("process: failed to initialize input, none provided")
+	}
+
+	for outputIndex, o := range sensorConfig.Outputs {
+		plugins, err := o.Plugins()
+		if err != nil {
+			return sg, fmt.Errorf("process: failed to resolve outputs: %w", err)
+		}
+
+		for _, plugin := range plugins {
+			if previous != nil {
+				index := slices.IndexFunc(previous.outputGroups, func(og *outputGroup) bool {
+					return reflect.DeepEqual(og.config, o) && og.outputName == plugin.Type && og.outputKey == outputKey(plugin.Type, outputIndex) && !slices.Contains(sg.outputGroups, og)
+				})
+				if index != -1 {
+					sg.outputGroups = append(sg.outputGroups, previous.outputGroups[index])
+					continue
 				}
-				outputContainer.OutputTransforms = append(outputContainer.OutputTransforms, outputTransform)
 			}
 
-			outputs = append(outputs, outputContainer)
+			if err := previous.releaseClientId(sg, pluginClientId(plugin)); err != nil {
+				return sg, err
+			}
+			og, err := s.newOutputGroup(sensorConfig.Name, outputIndex, o, plugin)
+			if err != nil {
+				return sg, err
+			}
+			sg.outputGroups = append(sg.outputGroups, og)
 		}
+	}
 
-		if len(outputs) == 0 {
-			return errors.New("process: failed to initialize output, none provided")
-		}
+	if len(sg.outputGroups) == 0 {
+		return sg, errors.New("process: failed to initialize output, none provided")
+	}
 
-		for _, dev := range sensorConfig.Devices {
-			deviceImpl, err := device.NewDevice(ctx, dev)
-			if err != nil {
-				return fmt.Errorf("process: failed to initialize device: %s - %w", dev.Name, err)
+	for _, dev := range sensorConfig.Devices {
+		if previous != nil {
+			index := slices.IndexFunc(previous.devices, func(sd *sensorDevice) bool {
+				return reflect.DeepEqual(sd.config, dev) && !slices.Contains(sg.devices, sd)
+			})
+			if index != -1 {
+				sg.devices = append(sg.devices, previous.devices[index])
+				continue
 			}
-			devices = append(devices, deviceImpl)
 		}
 
-		sg := &sensorGroup{
-			config:          sensorConfig,
-			input:           inputImpl,
-			inputTransforms: inputTransforms,
-			outputGroups:    outputs,
-			devices:         devices,
+		deviceImpl, err := device.NewDevice(s.ctx, dev)
+		if err != nil {
+			return sg, fmt.Errorf("process: failed to initialize device: %s - %w", dev.Name, err)
+		}
+		sg.devices = append(sg.devices, &sensorDevice{
+			config: dev,
+			device: deviceImpl,
+		})
+	}
+
+	if commands := sensorConfig.Commands; commands != nil && commands.Enabled {
+		if err := previous.releaseClientId(sg, commands.ClientId); err != nil {
+			return sg, err
 		}
+		sg.commands, err = newCommandHandler(s.ctx, sensorConfig.Name, commands, sg.devices)
+		if err != nil {
+			return sg, err
+		}
+	}
 
-		go s.handleSensor(ctx, sg)
+	return sg, nil
+}
 
-		sensorGroups = append(sensorGroups, sg)
+// restoreSensorGroup restarts the stopped sensor group after its replacement failed to build, the components it
+// released to the replacement are created again. The sensor is removed when that fails as well.
+func (s *service) restoreSensorGroup(previous *sensorGroup) error {
+	name := previous.config.Name
+	if !previous.released {
+		s.startSensorGroup(previous)
+		return nil
 	}
 
+	logrus.WithField("sensor", name).Info("restoring sensor")
+	sg, err := s.newSensorGroup(previous.config, previous)
+	if err != nil {
+		delete(s.sensorGroups, name)
+		return errors.Join(
+			fmt.Errorf("process: failed to restore sensor: %s - %w", name, err),
+			previous.closeUnused(nil),
+		)
+	}
+
+	closeErr := previous.closeUnused(sg)
+	s.startSensorGroup(sg)
+	s.sensorGroups[name] = sg
+	return closeErr
+}
+
+// initInput creates, subscribes and adds the input of plugin to sg, a partially initialized input is added as well so it gets closed.
+func (s *service) initInput(sg *sensorGroup, inputConfig *config.Input, plugin config.Plugin, name string) error {
+	inputImpl, err := input.NewInput(s.ctx, plugin.Type, plugin.Options)
+	if err != nil {
+		return fmt.Errorf("process: failed to initialize %s input: %w", plugin.Type, err)
+	}
+	si := &sensorInput{
+		config:     inputConfig,
+		pluginType: plugin.Type,
+		name:       name,
+		clientId:   pluginClientId(plugin),
+		input:      inputImpl,
+	}
+	sg.inputs = append(sg.inputs, si)
+
+	for _, transform := range inputConfig.Transforms {
+		inputTransform, err := inputtransform.NewTransform(s.ctx, transform)
+		if err != nil {
+			return fmt.Errorf("process: failed to initialize input transform: %s - %w", transform.Name, err)
+		}
+		si.transforms = append(si.transforms, inputTransform)
+	}
+
+	si.dataChan, err = si.input.Subscribe(s.ctx)
+	if err != nil {
+		return fmt.Errorf("process: failed to subscribe to input: %s - %w", name, err)
+	}
 	return nil
 }
 
-func (s *service) handleSensor(ctx context.Context, sg *sensorGroup) {
-	s.wg.Add(1)
-	defer s.wg.Done()
+// pluginClientId returns the mqtt client id configured in the options of the plugin, empty when there is none.
+func pluginClientId(plugin config.Plugin) string {
+	var options struct {
+		ClientId string `yaml:"clientId"`
+	}
+	if err := plugin.Options.Decode(&options); err != nil {
+		return ""
+	}
+	return options.ClientId
+}
+
+// uniqueInputName returns the configured name of the input, or its type when unnamed, suffixed with a counter when already taken.
+func uniqueInputName(taken map[string]bool, inputConfig *config.Input, plugin config.Plugin, pluginCount int) string {
+	base := plugin.Type
+	if inputConfig.Name != "" {
+		base = inputConfig.Name
+		if pluginCount > 1 {
+			base = inputConfig.Name + "-" + plugin.Type
+		}
+	}
 
-	dataChan, err := sg.input.Subscribe(ctx)
+	name := base
+	for i := 2; taken[name]; i++ {
+		name = fmt.Sprintf("%s-%d", base, i)
+	}
+	taken[name] = true
+	return name
+}
+
+func (s *service) newOutputGroup(sensorName string, outputIndex int, o *config.Output, plugin config.Plugin) (*outputGroup, error) {
+	outputName := plugin.Type
+	og, err := newOutputGroup(sensorName, outputName, outputIndex, o)
 	if err != nil {
-		logrus.
-			WithError(err).
-			WithField("input", reflect.ValueOf(sg.input).Type().String()).
-			WithField("sensor", sg.config.Name).
-			Error("process: failed to subscribe to input")
-		return
+		return nil, err
 	}
+	og.clientId = pluginClientId(plugin)
 
+	og.Output, err = output.NewOutput(s.ctx, outputName, plugin.Options, s.metricsService)
+	if err != nil {
+		return nil, errors.Join(
+			fmt.Errorf("process: failed to initialize %s output: %w", outputName, err),
+			og.Close(),
+		)
+	}
+
+	for _, transform := range o.Transforms {
+		outputTransform, err := outputtransform.NewTransform(s.ctx, transform)
+		if err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("process: failed to initialize output transform: %s - %w", transform.Name, err),
+				og.Close(),
+			)
+		}
+		og.OutputTransforms = append(og.OutputTransforms, outputTransform)
+	}
+
+	og.start(s.ctx)
+	return og, nil
+}
+
+func (s *service) startSensorGroup(sg *sensorGroup) {
+	ctx, cancelFunc := context.WithCancel(s.ctx)
+	sg.cancelFunc = cancelFunc
+	sg.done = make(chan struct{})
+
+	events := make(chan *input.Data)
+	var wg sync.WaitGroup
+	for _, si := range sg.inputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.receive(ctx, si, events)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(events)
+	}()
+
+	var sensorEvents <-chan *input.Data = events
+	if merge := sg.config.Merge; merge != nil && merge.Enabled {
+		sensorEvents = mergeAdvertisements(ctx, sg.config.Name, merge.GetWindow(), events)
+	}
+
+	go func() {
+		defer close(sg.done)
+		s.handleSensor(ctx, sg, sensorEvents)
+		wg.Wait()
+	}()
+}
+
+// receive tags every message of the input with its name, applies the input transforms and forwards it to events.
+func (s *service) receive(ctx context.Context, si *sensorInput, events chan<- *input.Data) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case inputData := <-dataChan:
+		case inputData, ok := <-si.dataChan:
+			if !ok {
+				return
+			}
 			if inputData == nil {
-				break
+				continue
+			}
+			if inputData.Properties == nil {
+				inputData.Properties = make(map[string]interface{})
 			}
+			inputData.Properties[input.NamePropertyKey] = si.name
 
 			var doNotProcess bool
-			for _, transform := range sg.inputTransforms {
+			for _, transform := range si.transforms {
 				transformData, err := transform.Transform(ctx, inputData)
 				if err != nil {
 					logrus.WithError(err).Error("process: failed to transform input data")
@@ -184,7 +414,27 @@ func (s *service) handleSensor(ctx context.Context, sg *sensorGroup) {
 				continue
 			}
 
-			for _, deviceImpl := range sg.devices {
+			select {
+			case <-ctx.Done():
+				return
+			case events <- inputData:
+			}
+		}
+	}
+}
+
+// handleSensor decodes the merged messages of every input of the sensor group and publishes the device events.
+func (s *service) handleSensor(ctx context.Context, sg *sensorGroup, events <-chan *input.Data) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case inputData, ok := <-events:
+			if !ok {
+				return
+			}
+
+			for _, sd := range sg.devices {
 				deviceData := &device.Data{
 					Data:       inputData.Data,
 					Properties: make(map[string]interface{}),
@@ -193,64 +443,53 @@ func (s *service) handleSensor(ctx context.Context, sg *sensorGroup) {
 					deviceData.Properties[k] = v
 				}
 
-				decodedDeviceData, err := deviceImpl.Decode(ctx, deviceData)
+				decodedDeviceData, err := sd.device.Decode(ctx, deviceData)
 				if err != nil {
 					logrus.WithError(err).Error("process: failed to decode device data")
 					continue
 				}
-				if decodedDeviceData == nil {
-					continue
-				}
-
-				for _, og := range sg.outputGroups {
-					outputData := &output.Data{
-						Data:       decodedDeviceData.Data,
-						Properties: make(map[string]interface{}),
-					}
-					for k, v := range decodedDeviceData.Properties {
-						outputData.Properties[k] = v
-					}
-
-					var doNotProcess bool
-					for _, transform := range og.OutputTransforms {
-						transformData, err := transform.Transform(ctx, outputData)
-						if err != nil {
-							logrus.WithError(err).Error("process: failed to transform output data")
-							continue
-						}
-						if transformData == nil {
-							doNotProcess = true
-							break
-						}
-
-						outputData.Data = transformData.Data
-						for k, v := range transformData.Properties {
-							outputData.Properties[k] = v
-						}
-					}
-					if doNotProcess {
-						continue
-					}
 
-					if err = og.Output.Publish(ctx, outputData); err != nil {
-						logrus.WithError(err).Error("process: failed to publish output data")
-						continue
+				for _, deviceEvent := range decodedDeviceData {
+					if sg.commands != nil {
+						sg.commands.observe(sd, deviceEvent)
 					}
+					s.publish(ctx, sg, deviceEvent)
 				}
 			}
 		}
 	}
 }
 
+// publish hands a copy of deviceData to every output group, the output transforms run before the event is queued.
+func (s *service) publish(ctx context.Context, sg *sensorGroup, deviceData *device.Data) {
+	for _, og := range sg.outputGroups {
+		outputData := &output.Data{
+			Data:       deviceData.Data,
+			Properties: make(map[string]interface{}),
+		}
+		for k, v := range deviceData.Properties {
+			outputData.Properties[k] = v
+		}
+		og.enqueue(ctx, outputData)
+	}
+}
+
 func (s *service) Close() error {
+	s.sensorGroupsLock.Lock()
+	defer s.sensorGroupsLock.Unlock()
+
+	err := s.closeSensorGroups()
 	s.cancelFunc()
-	s.wg.Wait()
+	return err
+}
 
+func (s *service) closeSensorGroups() error {
 	var errs []error
-	for _, container := range s.sensorGroups {
-		if err := container.Close(); err != nil {
+	for name, sg := range s.sensorGroups {
+		if err := sg.Close(); err != nil {
 			errs = append(errs, err)
 		}
+		delete(s.sensorGroups, name)
 	}
 	return errors.Join(errs...)
 }
@@ -0,0 +1,428 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+	"github.com/nikiforov-soft/yasp/input"
+	"github.com/nikiforov-soft/yasp/metrics"
+	"github.com/nikiforov-soft/yasp/output"
+)
+
+// lifecycle records the components opened and closed by the fake inputs, outputs and devices in order.
+var lifecycle struct {
+	events []string
+	lock   sync.Mutex
+}
+
+func record(format string, args ...any) {
+	lifecycle.lock.Lock()
+	defer lifecycle.lock.Unlock()
+	lifecycle.events = append(lifecycle.events, fmt.Sprintf(format, args...))
+}
+
+func recorded() []string {
+	lifecycle.lock.Lock()
+	defer lifecycle.lock.Unlock()
+	events := lifecycle.events
+	lifecycle.events = nil
+	return events
+}
+
+type fakeOptions struct {
+	Id       string `yaml:"id"`
+	ClientId string `yaml:"clientId"`
+}
+
+type fakeInput struct {
+	id       string
+	dataChan chan *input.Data
+	once     sync.Once
+}
+
+func (fi *fakeInput) Subscribe(_ context.Context) (<-chan *input.Data, error) {
+	return fi.dataChan, nil
+}
+
+func (fi *fakeInput) Close(_ context.Context) error {
+	fi.once.Do(func() {
+		record("close input %s", fi.id)
+		close(fi.dataChan)
+	})
+	return nil
+}
+
+type fakeOutput struct {
+	id string
+}
+
+func (fo *fakeOutput) Publish(_ context.Context, _ *output.Data) error {
+	return nil
+}
+
+func (fo *fakeOutput) Close(_ context.Context) error {
+	record("close output %s", fo.id)
+	return nil
+}
+
+type fakeDevice struct{}
+
+func (fd *fakeDevice) Decode(_ context.Context, data *device.Data) ([]*device.Data, error) {
+	return []*device.Data{data}, nil
+}
+
+func init() {
+	err := input.RegisterInput("test-input", func(_ context.Context, options config.Options) (input.Input, error) {
+		var fo fakeOptions
+		if err := options.Decode(&fo); err != nil {
+			return nil, err
+		}
+		record("open input %s", fo.Id)
+		return &fakeInput{id: fo.Id, dataChan: make(chan *input.Data)}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = output.RegisterOutput("test-output", func(_ context.Context, options config.Options, _ metrics.Service) (output.Output, error) {
+		var fo fakeOptions
+		if err := options.Decode(&fo); err != nil {
+			return nil, err
+		}
+		record("open output %s", fo.Id)
+		return &fakeOutput{id: fo.Id}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = output.RegisterOutputValidator("test-output", func(options config.Options, _ *config.Metrics) error {
+		var fo fakeOptions
+		if err := options.DecodeStrict(&fo); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+		if fo.Id == "" {
+			return errors.New("id is required")
+		}
+		return nil
+	})
+	if err != nil {
+		panic(err)
+	}
+	err = device.RegisterDevice("test-device", func(_ context.Context, config *config.Device) (device.Device, error) {
+		record("open device %s", config.Name)
+		return &fakeDevice{}, nil
+	})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func sensors(t *testing.T, value string) []*config.Sensor {
+	var result []*config.Sensor
+	require.NoError(t, yaml.Unmarshal([]byte(value), &result))
+	return result
+}
+
+const reloadSensors = `
+- name: kitchen
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: kitchen-in
+      clientId: kitchen
+  outputs:
+    - type: test-output
+      options:
+        id: kitchen-out
+  devices:
+    - name: kitchen-device
+      type: test-device
+- name: garage
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: garage-in
+  outputs:
+    - type: test-output
+      options:
+        id: garage-out
+  devices:
+    - name: garage-device
+      type: test-device
+`
+
+func TestReload(t *testing.T) {
+	tests := []struct {
+		name     string
+		sensors  string
+		expected []string
+	}{
+		{
+			name: "unchanged sensors",
+			// comments and line shifts do not change the sensors
+			sensors:  "# reloaded\n" + reloadSensors,
+			expected: nil,
+		},
+		{
+			name: "changed output",
+			sensors: `
+- name: kitchen
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: kitchen-in
+      clientId: kitchen
+  outputs:
+    - type: test-output
+      options:
+        id: kitchen-out-2
+  devices:
+    - name: kitchen-device
+      type: test-device
+- name: garage
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: garage-in
+  outputs:
+    - type: test-output
+      options:
+        id: garage-out
+  devices:
+    - name: garage-device
+      type: test-device
+`,
+			expected: []string{
+				"open output kitchen-out-2",
+				"close output kitchen-out",
+			},
+		},
+		{
+			name: "changed input with the same client id",
+			sensors: `
+- name: kitchen
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: kitchen-in-2
+      clientId: kitchen
+  outputs:
+    - type: test-output
+      options:
+        id: kitchen-out
+  devices:
+    - name: kitchen-device
+      type: test-device
+- name: garage
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: garage-in
+  outputs:
+    - type: test-output
+      options:
+        id: garage-out
+  devices:
+    - name: garage-device
+      type: test-device
+`,
+			// the previous connection is closed before the replacement connects with the same client id
+			expected: []string{
+				"close input kitchen-in",
+				"open input kitchen-in-2",
+			},
+		},
+		{
+			name: "changed device",
+			sensors: `
+- name: kitchen
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: kitchen-in
+      clientId: kitchen
+  outputs:
+    - type: test-output
+      options:
+        id: kitchen-out
+  devices:
+    - name: kitchen-device-2
+      type: test-device
+- name: garage
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: garage-in
+  outputs:
+    - type: test-output
+      options:
+        id: garage-out
+  devices:
+    - name: garage-device
+      type: test-device
+`,
+			expected: []string{
+				"open device kitchen-device-2",
+			},
+		},
+		{
+			name: "removed sensor",
+			sensors: `
+- name: kitchen
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: kitchen-in
+      clientId: kitchen
+  outputs:
+    - type: test-output
+      options:
+        id: kitchen-out
+  devices:
+    - name: kitchen-device
+      type: test-device
+`,
+			expected: []string{
+				"close input garage-in",
+				"close output garage-out",
+			},
+		},
+		{
+			name: "disabled sensor",
+			sensors: `
+- name: kitchen
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: kitchen-in
+      clientId: kitchen
+  outputs:
+    - type: test-output
+      options:
+        id: kitchen-out
+  devices:
+    - name: kitchen-device
+      type: test-device
+- name: garage
+  enabled: false
+`,
+			expected: []string{
+				"close input garage-in",
+				"close output garage-out",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorded()
+			s, err := NewService(context.Background(), nil, sensors(t, reloadSensors))
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{
+				"open input kitchen-in",
+				"open output kitchen-out",
+				"open device kitchen-device",
+				"open input garage-in",
+				"open output garage-out",
+				"open device garage-device",
+			}, recorded())
+
+			require.NoError(t, s.Reload(sensors(t, tt.sensors)))
+			assert.Equal(t, tt.expected, recorded())
+
+			require.NoError(t, s.Close())
+			recorded()
+		})
+	}
+}
+
+func TestReloadDuplicateSensorName(t *testing.T) {
+	s, err := NewService(context.Background(), nil, sensors(t, reloadSensors))
+	require.NoError(t, err)
+	defer s.Close()
+
+	err = s.Reload(sensors(t, reloadSensors+`
+- name: kitchen
+  enabled: true
+`))
+	assert.EqualError(t, err, "process: duplicate sensor name: kitchen")
+}
+
+func TestReloadRestoresReleasedClientId(t *testing.T) {
+	recorded()
+	svc, err := NewService(context.Background(), nil, sensors(t, reloadSensors))
+	require.NoError(t, err)
+	defer svc.Close()
+	recorded()
+
+	// the replacement input takes over the client id of the running input, then the unknown device fails the build
+	err = svc.Reload(sensors(t, `
+- name: kitchen
+  enabled: true
+  input:
+    type: test-input
+    options:
+      id: kitchen-in-2
+      clientId: kitchen
+  outputs:
+    - type: test-output
+      options:
+        id: kitchen-out
+  devices:
+    - name: kitchen-device
+      type: missing-device
+`+reloadSensors[strings.Index(reloadSensors, "- name: garage"):]))
+	assert.ErrorContains(t, err, "process: failed to initialize device: kitchen-device")
+	assert.Equal(t, []string{
+		"close input kitchen-in",
+		"open input kitchen-in-2",
+		"close input kitchen-in-2",
+		"open input kitchen-in",
+	}, recorded())
+
+	s := svc.(*service)
+	sg := s.sensorGroups["kitchen"]
+	require.NotNil(t, sg)
+	require.Len(t, sg.inputs, 1)
+	assert.Equal(t, "test-input", sg.inputs[0].name)
+	assert.NotNil(t, sg.cancelFunc)
+
+	// the restored sensor matches the previous configuration
+	require.NoError(t, svc.Reload(sensors(t, reloadSensors)))
+	assert.Empty(t, recorded())
+}
+
+func TestReloadFailureKeepsRunningSensor(t *testing.T) {
+	recorded()
+	svc, err := NewService(context.Background(), nil, sensors(t, reloadSensors))
+	require.NoError(t, err)
+	defer svc.Close()
+	recorded()
+
+	s := svc.(*service)
+	previous := s.sensorGroups["kitchen"]
+
+	err = svc.Reload(sensors(t, strings.Replace(reloadSensors, "type: test-device", "type: missing-device", 1)))
+	assert.ErrorContains(t, err, "process: failed to initialize device: kitchen-device")
+	assert.Empty(t, recorded())
+	assert.Same(t, previous, s.sensorGroups["kitchen"])
+	assert.NotNil(t, previous.cancelFunc)
+}
@@ -0,0 +1,114 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/nikiforov-soft/yasp/device"
+	"github.com/nikiforov-soft/yasp/input"
+	inputtransform "github.com/nikiforov-soft/yasp/input/transform"
+	"github.com/nikiforov-soft/yasp/output"
+	outputtransform "github.com/nikiforov-soft/yasp/output/transform"
+)
+
+// Validate builds every device and transform of the sensors and validates the options of every input and output through
+// their registered validators without connecting any of them, reporting unregistered input and output types, unknown
+// types and invalid properties and options. It returns all problems joined together.
+func Validate(ctx context.Context, metricsConfig *config.Metrics, sensors []*config.Sensor) error {
+	var errs []error
+	for i, sensor := range sensors {
+		if sensor == nil {
+			continue
+		}
+		path := fmt.Sprintf("sensors[%d]", i)
+		if sensor.Name != "" {
+			path = fmt.Sprintf("sensors[%s]", sensor.Name)
+		}
+
+		if sensor.Input != nil {
+			errs = append(errs, validateInput(ctx, path+".input", sensor.Input)...)
+		}
+		for j, in := range sensor.Inputs {
+			if in != nil {
+				errs = append(errs, validateInput(ctx, fmt.Sprintf("%s.inputs[%d]", path, j), in)...)
+			}
+		}
+
+		for j, o := range sensor.Outputs {
+			if o == nil {
+				continue
+			}
+			outputPath := fmt.Sprintf("%s.outputs[%d]", path, j)
+			if plugins, err := o.Plugins(); err == nil {
+				for _, plugin := range plugins {
+					if !output.HasOutput(plugin.Type) {
+						errs = append(errs, fmt.Errorf("%s: %w: %s", outputPath, output.ErrOutputNotFound, plugin.Type))
+						continue
+					}
+					errs = append(errs, prefixErrors(outputPath+"."+plugin.Key, output.ValidateOutput(plugin.Type, plugin.Options, metricsConfig))...)
+				}
+			}
+			for k, transform := range o.Transforms {
+				if transform == nil {
+					continue
+				}
+				if _, err := outputtransform.NewTransform(ctx, transform); err != nil {
+					errs = append(errs, fmt.Errorf("%s.transforms[%d]: %w", outputPath, k, err))
+				}
+			}
+		}
+
+		for j, dev := range sensor.Devices {
+			if dev == nil {
+				continue
+			}
+			if _, err := device.NewDevice(ctx, dev); err != nil {
+				errs = append(errs, fmt.Errorf("%s.devices[%d]: %w", path, j, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validateInput(ctx context.Context, path string, in *config.Input) []error {
+	var errs []error
+	if plugins, err := in.Plugins(); err == nil {
+		for _, plugin := range plugins {
+			if !input.HasInput(plugin.Type) {
+				errs = append(errs, fmt.Errorf("%s: %w: %s", path, input.ErrInputNotFound, plugin.Type))
+				continue
+			}
+			errs = append(errs, prefixErrors(path+"."+plugin.Key, input.ValidateInput(plugin.Type, plugin.Options))...)
+		}
+	}
+	for i, transform := range in.Transforms {
+		if transform == nil {
+			continue
+		}
+		if _, err := inputtransform.NewTransform(ctx, transform); err != nil {
+			errs = append(errs, fmt.Errorf("%s.transforms[%d]: %w", path, i, err))
+		}
+	}
+	return errs
+}
+
+// prefixErrors prefixes every joined error of err with path, the whitespace of the multi line decoding errors is
+// collapsed so that every problem stays on a single line.
+func prefixErrors(path string, err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{fmt.Errorf("%s: %s", path, strings.Join(strings.Fields(err.Error()), " "))}
+	}
+	var errs []error
+	for _, err := range joined.Unwrap() {
+		errs = append(errs, prefixErrors(path, err)...)
+	}
+	return errs
+}
@@ -0,0 +1,71 @@
+package process
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nikiforov-soft/yasp/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		sensors     string
+		expectedErr string
+	}{
+		{
+			name:    "registered types",
+			sensors: reloadSensors,
+		},
+		{
+			name: "unknown types",
+			sensors: `
+- name: sensor
+  input:
+    type: nope-input
+    transforms:
+      - name: nope-input-transform
+  inputs:
+    - name: second
+      type: test-input
+  outputs:
+    - type: nope-output
+      transforms:
+        - name: nope-output-transform
+  devices:
+    - name: thermometer
+      type: nope-device
+- outputs:
+    - type: test-output
+    - type: test-output
+      options:
+        id: test
+        topic: yasp/test
+  devices:
+    - name: thermometer
+      type: test-device
+    - name: hygrometer
+      type: nope-device
+`,
+			expectedErr: "sensors[sensor].input: input not found: nope-input\n" +
+				"sensors[sensor].input.transforms[0]: input transform not found: nope-input-transform\n" +
+				"sensors[sensor].outputs[0]: output not found: nope-output\n" +
+				"sensors[sensor].outputs[0].transforms[0]: output transform not found: nope-output-transform\n" +
+				"sensors[sensor].devices[0]: device not found: nope-device\n" +
+				"sensors[1].outputs[0].options: id is required\n" +
+				"sensors[1].outputs[1].options: invalid options: yaml: unmarshal errors: line 2: field topic not found in type process.fakeOptions\n" +
+				"sensors[1].devices[1]: device not found: nope-device",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(context.Background(), &config.Metrics{}, sensors(t, tt.sensors))
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -7,9 +7,9 @@ import (
 )
 
 func Execute(templateKey, templateValue string, data any) ([]byte, error) {
-	tmpl, err := template.New(templateKey).Funcs(funcsMap).Parse(templateValue)
+	tmpl, err := parse(templateKey, templateValue)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse template: %w", err)
+		return nil, err
 	}
 
 	var buf bytes.Buffer
@@ -18,3 +18,17 @@ func Execute(templateKey, templateValue string, data any) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// Validate parses the template without executing it, reporting syntax errors and unknown functions.
+func Validate(templateKey, templateValue string) error {
+	_, err := parse(templateKey, templateValue)
+	return err
+}
+
+func parse(templateKey, templateValue string) (*template.Template, error) {
+	tmpl, err := template.New(templateKey).Funcs(funcsMap).Parse(templateValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return tmpl, nil
+}